@@ -3,64 +3,216 @@ package main
 import (
 	"context"
 	"github.com/danielencestari/lab03/configuration/database/mongodb"
+	"github.com/danielencestari/lab03/configuration/env"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/danielencestari/lab03/internal/infra/api/web/controller/admin_controller"
 	"github.com/danielencestari/lab03/internal/infra/api/web/controller/auction_controller"
 	"github.com/danielencestari/lab03/internal/infra/api/web/controller/bid_controller"
+	"github.com/danielencestari/lab03/internal/infra/api/web/controller/config_controller"
+	"github.com/danielencestari/lab03/internal/infra/api/web/controller/health_controller"
+	"github.com/danielencestari/lab03/internal/infra/api/web/controller/metrics_controller"
 	"github.com/danielencestari/lab03/internal/infra/api/web/controller/user_controller"
+	"github.com/danielencestari/lab03/internal/infra/api/web/middleware"
 	"github.com/danielencestari/lab03/internal/infra/database/auction"
 	"github.com/danielencestari/lab03/internal/infra/database/bid"
 	"github.com/danielencestari/lab03/internal/infra/database/user"
+	"github.com/danielencestari/lab03/internal/metrics"
 	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
 	"github.com/danielencestari/lab03/internal/usecase/bid_usecase"
 	"github.com/danielencestari/lab03/internal/usecase/user_usecase"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func main() {
 	ctx := context.Background()
 
-	if err := godotenv.Load("cmd/auction/.env"); err != nil {
+	config, err := env.Load()
+	if err != nil {
 		log.Fatal("Error trying to load env variables")
 		return
 	}
 
-	databaseConnection, err := mongodb.NewMongoDBConnection(ctx)
+	databaseConnection, err := mongodb.NewMongoDBConnection(ctx, config.MongoDBURL, config.MongoDBDB)
 	if err != nil {
 		log.Fatal(err.Error())
 		return
 	}
 
-	router := gin.Default()
+	connectionMonitor := mongodb.NewConnectionMonitor(
+		databaseConnection.Client(), mongodb.ReconnectConfigFromEnv())
+	connectionMonitor.Start()
+	defer connectionMonitor.Stop()
+
+	router := gin.New()
+	router.Use(middleware.RequestLogger(), gin.Recovery())
+	router.Use(middleware.RequestTimeout(middleware.GetRequestTimeout()))
+
+	metricsRegistry := metrics.NewRegistry()
+
+	userController, bidController, auctionsController, healthController, adminController, configController :=
+		initDependencies(databaseConnection, config, metricsRegistry)
+
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
+	router.GET("/config", configController.GetConfig)
+	router.GET("/debug/metrics", metrics_controller.NewMetricsController(metricsRegistry).GetMetrics)
 
-	userController, bidController, auctionsController := initDependencies(databaseConnection)
+	router.Use(middleware.RequireDatabase(connectionMonitor))
 
 	router.GET("/auction", auctionsController.FindAuctions)
+	router.GET("/auctions/stats", auctionsController.GetAuctionStats)
+	router.GET("/auctions/timeseries", auctionsController.GetAuctionTimeseries)
+	router.GET("/auctions/ending-soon", auctionsController.GetAuctionsEndingSoon)
+	router.GET("/auctions/:auctionId/history", auctionsController.GetAuctionHistory)
 	router.GET("/auction/:auctionId", auctionsController.FindAuctionById)
 	router.POST("/auction", auctionsController.CreateAuction)
+	router.PATCH("/auction/:auctionId", auctionsController.UpdateAuction)
+	router.POST("/auction/:auctionId/pause", auctionsController.PauseAuction)
+	router.POST("/auction/:auctionId/resume", auctionsController.ResumeAuction)
 	router.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
 	router.POST("/bid", bidController.CreateBid)
 	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
+	router.DELETE("/bid/:bidId", bidController.RetractBid)
+	router.GET("/user", userController.FindUsers)
 	router.GET("/user/:userId", userController.FindUserById)
+	router.GET("/user/:userId/bids/export", bidController.ExportBidsByUser)
+	router.POST("/admin/auction/cancel-all", adminController.CancelAllActiveAuctions)
+	router.POST("/admin/auction/purge", adminController.PurgeCompletedAuctions)
+	router.POST("/admin/simulate", adminController.SimulateAuctions)
+	router.POST("/admin/limit", adminController.SetConcurrencyLimit)
+	router.POST("/admin/maintenance", adminController.SetMaintenanceMode)
 
 	router.Run(":8080")
 }
 
-func initDependencies(database *mongo.Database) (
+func initDependencies(database *mongo.Database, config *env.Config, metricsRegistry *metrics.Registry) (
 	userController *user_controller.UserController,
 	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController) {
+	auctionController *auction_controller.AuctionController,
+	healthController *health_controller.HealthController,
+	adminController *admin_controller.AdminController,
+	configController *config_controller.ConfigController) {
 
-	auctionRepository := auction.NewAuctionRepository(database)
-	bidRepository := bid.NewBidRepository(database, auctionRepository)
-	userRepository := user.NewUserRepository(database)
+	repositoryOptions := auctionRepositoryOptions(config, metricsRegistry)
+	auctionRepository := auction.NewAuctionRepository(database, repositoryOptions...)
+	routedAuctionRepository := readYourWrites(config,
+		routeAuctionRepository(database, config, auctionRepository, repositoryOptions))
+	userRepository := newUserRepository(database, config)
+	bidRepository := bid.NewBidRepository(database, routedAuctionRepository, userRepository)
 
 	userController = user_controller.NewUserController(
 		user_usecase.NewUserUseCase(userRepository))
-	auctionController = auction_controller.NewAuctionController(
-		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository))
+	auctionUseCase := auction_usecase.NewAuctionUseCase(routedAuctionRepository, bidRepository)
+	auctionController = auction_controller.NewAuctionController(auctionUseCase)
 	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository))
+	healthController = health_controller.NewHealthController(auctionRepository)
+	adminController = admin_controller.NewAdminController(auctionUseCase, config.AdminToken)
+	configController = config_controller.NewConfigController(auctionRepository)
 
 	return
 }
+
+// newUserRepository caches user lookups for USER_CACHE_TTL when it is set, so
+// bids from the same user don't hit the database every time.
+func newUserRepository(database *mongo.Database, config *env.Config) user_entity.UserRepositoryInterface {
+	repository := user.NewUserRepository(database)
+	if cacheTTL, err := time.ParseDuration(config.UserCacheTTL); err == nil && cacheTTL > 0 {
+		return user.NewCachedUserRepository(repository, cacheTTL)
+	}
+
+	return repository
+}
+
+// auctionRepositoryOptions builds the repository tunables from the loaded
+// config, and enables the FindAuctionById cache when
+// AUCTION_CACHE_SIZE is set (AUCTION_CACHE_TTL defaults to 5 seconds), the
+// listing total cache when AUCTION_COUNT_CACHE_TTL is set, database
+// backed monitors when DURABLE_MONITORS is true and lookup latency
+// metrics when METRICS_ENABLED is true.
+func auctionRepositoryOptions(config *env.Config, metricsRegistry *metrics.Registry) []auction.RepositoryOption {
+	options := []auction.RepositoryOption{auction.WithConfig(auction.NewConfig(config))}
+
+	if cacheSize, err := strconv.Atoi(config.AuctionCacheSize); err == nil && cacheSize > 0 {
+		cacheTTL, err := time.ParseDuration(config.AuctionCacheTTL)
+		if err != nil || cacheTTL <= 0 {
+			cacheTTL = 5 * time.Second
+		}
+		options = append(options, auction.WithFindByIdCache(cacheSize, cacheTTL))
+	}
+
+	if countCacheTTL, err := time.ParseDuration(config.AuctionCountCacheTTL); err == nil && countCacheTTL > 0 {
+		options = append(options, auction.WithCountCache(countCacheTTL))
+	}
+
+	if durableMonitors, _ := strconv.ParseBool(config.DurableMonitors); durableMonitors {
+		lease, _ := time.ParseDuration(config.MonitorLease)
+		pollInterval, _ := time.ParseDuration(config.MonitorPollInterval)
+		options = append(options, auction.WithDurableMonitors(lease, pollInterval))
+	}
+
+	if metricsEnabled, _ := strconv.ParseBool(config.MetricsEnabled); metricsEnabled {
+		options = append(options, auction.WithMetrics(metricsRegistry))
+	}
+
+	return options
+}
+
+// readYourWrites retries lookups of just created auctions that are not
+// visible yet for READ_YOUR_WRITES_WINDOW when it is set, for deployments
+// reading from replica set secondaries.
+func readYourWrites(config *env.Config, repository auction.RoutableRepository) auction.RoutableRepository {
+	if window, err := time.ParseDuration(config.ReadYourWritesWindow); err == nil && window > 0 {
+		return auction.NewReadYourWritesRepository(repository, window)
+	}
+
+	return repository
+}
+
+// routeAuctionRepository reads AUCTION_DATABASE_ROUTES, a comma separated
+// list of category=database pairs (e.g. "Electronics=auctions_electronics"),
+// and routes those categories to their own database. Bids stay in the main
+// database. Without routes the default repository is used as is; an
+// invalid database name stops the startup.
+func routeAuctionRepository(
+	database *mongo.Database,
+	config *env.Config,
+	defaultRepository *auction.AuctionRepository,
+	repositoryOptions []auction.RepositoryOption) auction.RoutableRepository {
+	routes := make(map[string]auction.RoutableRepository)
+	repositoriesByDatabase := map[string]*auction.AuctionRepository{
+		database.Name(): defaultRepository,
+	}
+
+	for _, route := range strings.Split(config.AuctionDatabaseRoutes, ",") {
+		category, databaseName, found := strings.Cut(route, "=")
+		category, databaseName = strings.TrimSpace(category), strings.TrimSpace(databaseName)
+		if !found || category == "" || databaseName == "" {
+			continue
+		}
+
+		repository, ok := repositoriesByDatabase[databaseName]
+		if !ok {
+			routedDatabase, err := mongodb.Database(database.Client(), databaseName)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+
+			options := append([]auction.RepositoryOption{
+				auction.WithBidsCollection(database.Collection("bids"))}, repositoryOptions...)
+			repository = auction.NewAuctionRepository(routedDatabase, options...)
+			repositoriesByDatabase[databaseName] = repository
+		}
+		routes[category] = repository
+	}
+
+	if len(routes) == 0 {
+		return defaultRepository
+	}
+
+	return auction.NewRepositoryRouter(defaultRepository, routes)
+}
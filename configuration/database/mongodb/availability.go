@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	MONGO_PING_TIMEOUT = "MONGO_PING_TIMEOUT"
+
+	defaultPingTimeout = 2 * time.Second
+)
+
+// PingTimeout reads MONGO_PING_TIMEOUT as a duration, falling back to 2
+// seconds when it is unset, invalid or not positive.
+func PingTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv(MONGO_PING_TIMEOUT))
+	if err != nil || timeout <= 0 {
+		return defaultPingTimeout
+	}
+
+	return timeout
+}
+
+// Ping checks that client reaches the server within timeout. A non-positive
+// timeout uses PingTimeout.
+func Ping(ctx context.Context, client *mongo.Client, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = PingTimeout()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return client.Ping(ctx, nil)
+}
+
+// IsAvailable reports whether a MongoDB server answers at uri within
+// timeout, connecting only for the check.
+func IsAvailable(uri string, timeout time.Duration) bool {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+	if err != nil {
+		return false
+	}
+	defer client.Disconnect(context.Background())
+
+	return Ping(context.Background(), client, timeout) == nil
+}
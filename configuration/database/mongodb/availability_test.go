@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Endereço não roteável: a conexão nunca é estabelecida
+const unreachableURI = "mongodb://10.255.255.1:27017"
+
+func TestPingTimeout(t *testing.T) {
+	defer os.Unsetenv(MONGO_PING_TIMEOUT)
+
+	for value, expected := range map[string]time.Duration{
+		"":      defaultPingTimeout,
+		"abc":   defaultPingTimeout,
+		"-1s":   defaultPingTimeout,
+		"250ms": 250 * time.Millisecond,
+	} {
+		os.Setenv(MONGO_PING_TIMEOUT, value)
+		assert.Equal(t, expected, PingTimeout(), value)
+	}
+}
+
+func TestPingUnreachableHostFailsFast(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(unreachableURI))
+	assert.Nil(t, err)
+	defer client.Disconnect(context.Background())
+
+	start := time.Now()
+	assert.NotNil(t, Ping(context.Background(), client, 50*time.Millisecond))
+	assert.Less(t, time.Since(start), time.Second)
+
+	start = time.Now()
+	assert.False(t, IsAvailable(unreachableURI, 50*time.Millisecond))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestPingUsesEnvTimeout(t *testing.T) {
+	os.Setenv(MONGO_PING_TIMEOUT, "50ms")
+	defer os.Unsetenv(MONGO_PING_TIMEOUT)
+
+	start := time.Now()
+	assert.False(t, IsAvailable(unreachableURI, 0))
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -5,17 +5,13 @@ import (
 	"github.com/danielencestari/lab03/configuration/logger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"os"
 )
 
-const (
-	MONGODB_URL = "MONGODB_URL"
-	MONGODB_DB  = "MONGODB_DB"
-)
-
-func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
-	mongoURL := os.Getenv(MONGODB_URL)
-	mongoDatabase := os.Getenv(MONGODB_DB)
+func NewMongoDBConnection(ctx context.Context, mongoURL, mongoDatabase string) (*mongo.Database, error) {
+	if err := ValidateDatabaseName(mongoDatabase); err != nil {
+		logger.Error("Error trying to validate mongodb database name", err)
+		return nil, err
+	}
 
 	client, err := mongo.Connect(
 		ctx, options.Client().ApplyURI(mongoURL))
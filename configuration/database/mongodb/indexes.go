@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// MissingIndexes lists which of the expected index names do not exist on
+// collection, in the order they were given.
+func MissingIndexes(ctx context.Context, collection *mongo.Collection, expected []string) ([]string, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []struct {
+		Name string `bson:"name"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(indexes))
+	for _, index := range indexes {
+		existing[index.Name] = true
+	}
+
+	var missing []string
+	for _, name := range expected {
+		if !existing[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
+}
+
+// WarnMissingIndexes logs a warning naming the expected indexes missing
+// from collection, e.g. when the database user cannot create them and
+// queries fall back to collection scans. It never fails.
+func WarnMissingIndexes(ctx context.Context, collection *mongo.Collection, expected []string) {
+	missing, err := MissingIndexes(ctx, collection, expected)
+	if err != nil {
+		logger.Error("Error trying to list the indexes of "+collection.Name(), err)
+		return
+	}
+
+	if len(missing) > 0 {
+		logger.Warn("Expected indexes are missing, queries on them will scan the collection",
+			zap.String("collection", collection.Name()),
+			zap.String("missing_indexes", strings.Join(missing, ",")))
+	}
+}
+
+// DropObsoleteIndexes drops the named indexes from collection when they
+// exist, e.g. those replaced by an index on more keys under a new name.
+// Failures are logged and never block startup.
+func DropObsoleteIndexes(ctx context.Context, collection *mongo.Collection, names []string) {
+	absent, err := MissingIndexes(ctx, collection, names)
+	if err != nil {
+		logger.Error("Error trying to list the indexes of "+collection.Name(), err)
+		return
+	}
+
+	missing := make(map[string]bool, len(absent))
+	for _, name := range absent {
+		missing[name] = true
+	}
+	for _, name := range names {
+		if missing[name] {
+			continue
+		}
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+			logger.Error("Error trying to drop obsolete index "+name, err,
+				zap.String("collection", collection.Name()))
+			continue
+		}
+		logger.Info("Dropped obsolete index",
+			zap.String("collection", collection.Name()), zap.String("index", name))
+	}
+}
@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxDatabaseNameLength = 63
+
+	// invalidDatabaseNameChars are rejected by MongoDB in database names
+	invalidDatabaseNameChars = "/\\. \"$\x00"
+)
+
+// ValidateDatabaseName checks name against the MongoDB naming rules, which
+// the driver leaves to the server until the first operation.
+func ValidateDatabaseName(name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid database name: it must not be empty")
+	}
+	if len(name) > maxDatabaseNameLength {
+		return fmt.Errorf("invalid database name %q: it must have at most %d bytes", name, maxDatabaseNameLength)
+	}
+	if index := strings.IndexAny(name, invalidDatabaseNameChars); index >= 0 {
+		return fmt.Errorf("invalid database name %q: character %q is not allowed", name, name[index])
+	}
+
+	return nil
+}
+
+// Database returns the database name of client once the name is known to
+// be valid.
+func Database(client *mongo.Client, name string) (*mongo.Database, error) {
+	if err := ValidateDatabaseName(name); err != nil {
+		return nil, err
+	}
+
+	return client.Database(name), nil
+}
@@ -0,0 +1,25 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDatabaseName(t *testing.T) {
+	assert.Nil(t, ValidateDatabaseName("auctions_electronics"))
+
+	for _, name := range []string{
+		"", "auctions/electronics", "auctions$", "auction db", "auctions.test", strings.Repeat("a", 64),
+	} {
+		assert.NotNil(t, ValidateDatabaseName(name), name)
+	}
+}
+
+func TestDatabaseRejectsInvalidNames(t *testing.T) {
+	// Validation happens before the client is ever used
+	database, err := Database(nil, "auction db")
+	assert.Nil(t, database)
+	assert.ErrorContains(t, err, "auction db")
+}
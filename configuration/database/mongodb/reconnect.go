@@ -0,0 +1,177 @@
+package mongodb
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
+)
+
+const (
+	MONGO_HEALTH_INTERVAL       = "MONGO_HEALTH_INTERVAL"
+	MONGO_RECONNECT_MAX_BACKOFF = "MONGO_RECONNECT_MAX_BACKOFF"
+
+	defaultHealthInterval      = 5 * time.Second
+	defaultReconnectBackoff    = 500 * time.Millisecond
+	defaultReconnectMaxBackoff = 30 * time.Second
+)
+
+// Client is the part of *mongo.Client the connection monitor uses, so tests
+// can fake a connection that drops.
+type Client interface {
+	Ping(ctx context.Context, rp *readpref.ReadPref) error
+}
+
+// ReconnectConfig tunes a ConnectionMonitor. Interval is the time between
+// health pings; while the database is down, pings start InitialBackoff
+// apart and double up to MaxBackoff.
+type ReconnectConfig struct {
+	Interval       time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// ReconnectConfigFromEnv reads MONGO_HEALTH_INTERVAL (default 5s) and
+// MONGO_RECONNECT_MAX_BACKOFF (default 30s); invalid or non-positive values
+// fall back to the defaults.
+func ReconnectConfigFromEnv() ReconnectConfig {
+	config := ReconnectConfig{
+		Interval:       defaultHealthInterval,
+		InitialBackoff: defaultReconnectBackoff,
+		MaxBackoff:     defaultReconnectMaxBackoff,
+	}
+
+	if interval, err := time.ParseDuration(os.Getenv(MONGO_HEALTH_INTERVAL)); err == nil && interval > 0 {
+		config.Interval = interval
+	}
+	if maxBackoff, err := time.ParseDuration(os.Getenv(MONGO_RECONNECT_MAX_BACKOFF)); err == nil && maxBackoff > 0 {
+		config.MaxBackoff = maxBackoff
+	}
+
+	return config
+}
+
+// ConnectionMonitor pings the client the repositories use every Interval.
+// When a ping fails it reports the database as unavailable and pings the
+// same client again with exponential backoff until it answers; the driver
+// restores its own pool, so no replacement client is dialed and the
+// repositories never hold a client the monitor isn't watching.
+type ConnectionMonitor struct {
+	client Client
+	config ReconnectConfig
+
+	available atomic.Bool
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewConnectionMonitor watches client, which the caller keeps owning.
+// Start begins the health pings.
+func NewConnectionMonitor(client Client, config ReconnectConfig) *ConnectionMonitor {
+	monitor := &ConnectionMonitor{
+		client: client,
+		config: config,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	monitor.available.Store(true)
+
+	return monitor
+}
+
+// Available reports whether the last health ping succeeded.
+func (m *ConnectionMonitor) Available() bool {
+	return m.available.Load()
+}
+
+// Start runs the health pings in the background until Stop.
+func (m *ConnectionMonitor) Start() {
+	go m.run()
+}
+
+// Stop ends the health pings and any wait for the database to come back.
+func (m *ConnectionMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+		<-m.done
+	})
+}
+
+func (m *ConnectionMonitor) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := m.ping()
+		if err == nil {
+			continue
+		}
+
+		m.available.Store(false)
+		logger.Error("MongoDB connection lost, waiting for it to come back", err)
+		if !m.waitForRecovery() {
+			return
+		}
+	}
+}
+
+// waitForRecovery pings until the client answers again, waiting twice as
+// long after every failure, then reports the database as available. It
+// returns false when stopped first.
+func (m *ConnectionMonitor) waitForRecovery() bool {
+	backoff := m.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-m.stop:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		err := m.ping()
+		if err == nil {
+			m.available.Store(true)
+			logger.Info("MongoDB connection restored", zap.Int("attempts", attempt))
+			return true
+		}
+
+		logger.Warn("MongoDB still unreachable",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+		backoff = nextBackoff(backoff, m.config.MaxBackoff)
+	}
+}
+
+func (m *ConnectionMonitor) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), PingTimeout())
+	defer cancel()
+
+	return m.client.Ping(ctx, nil)
+}
+
+func nextBackoff(backoff, maxBackoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+
+	return backoff
+}
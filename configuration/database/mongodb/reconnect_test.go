@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// fakeClient answers pings until it is marked down, counting the pings
+// received while down
+type fakeClient struct {
+	mutex       sync.Mutex
+	down        bool
+	failedPings int
+}
+
+func (c *fakeClient) Ping(ctx context.Context, rp *readpref.ReadPref) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.down {
+		c.failedPings++
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (c *fakeClient) failed() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.failedPings
+}
+
+func (c *fakeClient) setDown(down bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.down = down
+}
+
+func TestReconnectConfigFromEnv(t *testing.T) {
+	defer os.Unsetenv(MONGO_HEALTH_INTERVAL)
+	defer os.Unsetenv(MONGO_RECONNECT_MAX_BACKOFF)
+
+	assert.Equal(t, ReconnectConfig{
+		Interval:       defaultHealthInterval,
+		InitialBackoff: defaultReconnectBackoff,
+		MaxBackoff:     defaultReconnectMaxBackoff,
+	}, ReconnectConfigFromEnv())
+
+	os.Setenv(MONGO_HEALTH_INTERVAL, "1s")
+	os.Setenv(MONGO_RECONNECT_MAX_BACKOFF, "-1s")
+	config := ReconnectConfigFromEnv()
+	assert.Equal(t, time.Second, config.Interval)
+	assert.Equal(t, defaultReconnectMaxBackoff, config.MaxBackoff)
+}
+
+func TestNextBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, nextBackoff(time.Second, 30*time.Second))
+	assert.Equal(t, 30*time.Second, nextBackoff(20*time.Second, 30*time.Second))
+	assert.Equal(t, 30*time.Second, nextBackoff(30*time.Second, 30*time.Second))
+}
+
+func TestConnectionMonitorRecovers(t *testing.T) {
+	// O mesmo cliente usado pelos repositórios: o monitor não troca de
+	// cliente, só acompanha quando ele volta a responder
+	client := &fakeClient{}
+
+	monitor := NewConnectionMonitor(client, ReconnectConfig{
+		Interval:       5 * time.Millisecond,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     4 * time.Millisecond,
+	})
+	monitor.Start()
+	defer monitor.Stop()
+	assert.True(t, monitor.Available())
+
+	client.setDown(true)
+	assert.Eventually(t, func() bool { return !monitor.Available() }, time.Second, time.Millisecond)
+
+	// Continua indisponível enquanto as novas tentativas falham
+	assert.Eventually(t, func() bool { return client.failed() >= 3 }, time.Second, time.Millisecond)
+	assert.False(t, monitor.Available())
+
+	client.setDown(false)
+	assert.Eventually(t, monitor.Available, time.Second, time.Millisecond)
+	assert.Nil(t, client.Ping(context.Background(), nil))
+
+	// Uma nova queda é detectada de novo
+	client.setDown(true)
+	assert.Eventually(t, func() bool { return !monitor.Available() }, time.Second, time.Millisecond)
+}
+
+func TestConnectionMonitorStopsWhileDown(t *testing.T) {
+	client := &fakeClient{down: true}
+
+	monitor := NewConnectionMonitor(client, ReconnectConfig{
+		Interval:       time.Millisecond,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	})
+	monitor.Start()
+
+	assert.Eventually(t, func() bool { return !monitor.Available() }, time.Second, time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		monitor.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return while the database was down")
+	}
+}
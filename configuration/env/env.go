@@ -0,0 +1,115 @@
+package env
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	ENV_FILE         = "ENV_FILE"
+	DEFAULT_ENV_FILE = "cmd/auction/.env"
+)
+
+// Config centralizes the environment keys main hands to the components it
+// builds. Values are kept as raw strings; each consumer parses and defaults
+// its own. Keys only read deep inside a package (anti-sniping, webhooks,
+// bid batching...) are read there from the process environment, which Load
+// also fills.
+type Config struct {
+	MongoDBURL string
+	MongoDBDB  string
+
+	AuctionInterval       string
+	MaxConcurrentAuctions string
+	AuctionDatabaseRoutes string
+	MonitorWorkers        string
+
+	MaxActivePerOwner       string
+	AuctionLimits           string
+	MaxRemainingTime        string
+	RecoveryOverLimit       string
+	AuctionCloseGrace       string
+	DescriptionCompression  string
+	AuctionSchemaValidation string
+	WriteConcernW           string
+	WriteConcernJ           string
+	WriteConcernWTimeout    string
+
+	AuctionCacheSize     string
+	AuctionCacheTTL      string
+	AuctionCountCacheTTL string
+	UserCacheTTL         string
+	ReadYourWritesWindow string
+	PurgeInterval        string
+	PurgeRetention       string
+	MetricsEnabled       string
+
+	DurableMonitors     string
+	MonitorLease        string
+	MonitorPollInterval string
+
+	AdminToken string
+}
+
+// Load reads the .env file named by ENV_FILE (cmd/auction/.env by default)
+// into the process environment and returns the resulting Config. Variables
+// already set in the process win over the file. A missing default file is
+// not an error, so the application can be configured by the environment
+// alone; a missing ENV_FILE is.
+func Load() (*Config, error) {
+	path := os.Getenv(ENV_FILE)
+	explicit := path != ""
+	if !explicit {
+		path = DEFAULT_ENV_FILE
+	}
+
+	if err := godotenv.Load(path); err != nil {
+		if explicit || !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return FromEnv(), nil
+}
+
+// FromEnv snapshots the known keys from the process environment.
+func FromEnv() *Config {
+	return &Config{
+		MongoDBURL: os.Getenv("MONGODB_URL"),
+		MongoDBDB:  os.Getenv("MONGODB_DB"),
+
+		AuctionInterval:       os.Getenv("AUCTION_INTERVAL"),
+		MaxConcurrentAuctions: os.Getenv("MAX_CONCURRENT_AUCTIONS"),
+		AuctionDatabaseRoutes: os.Getenv("AUCTION_DATABASE_ROUTES"),
+		MonitorWorkers:        os.Getenv("MONITOR_WORKERS"),
+
+		MaxActivePerOwner:       os.Getenv("MAX_ACTIVE_PER_OWNER"),
+		AuctionLimits:           os.Getenv("AUCTION_LIMITS"),
+		MaxRemainingTime:        os.Getenv("MAX_REMAINING_TIME"),
+		RecoveryOverLimit:       os.Getenv("RECOVERY_OVER_LIMIT"),
+		AuctionCloseGrace:       os.Getenv("AUCTION_CLOSE_GRACE"),
+		DescriptionCompression:  os.Getenv("DESCRIPTION_COMPRESSION"),
+		AuctionSchemaValidation: os.Getenv("AUCTION_SCHEMA_VALIDATION"),
+		WriteConcernW:           os.Getenv("MONGODB_WRITE_CONCERN_W"),
+		WriteConcernJ:           os.Getenv("MONGODB_WRITE_CONCERN_J"),
+		WriteConcernWTimeout:    os.Getenv("MONGODB_WRITE_CONCERN_WTIMEOUT"),
+
+		AuctionCacheSize:     os.Getenv("AUCTION_CACHE_SIZE"),
+		AuctionCacheTTL:      os.Getenv("AUCTION_CACHE_TTL"),
+		AuctionCountCacheTTL: os.Getenv("AUCTION_COUNT_CACHE_TTL"),
+		UserCacheTTL:         os.Getenv("USER_CACHE_TTL"),
+		ReadYourWritesWindow: os.Getenv("READ_YOUR_WRITES_WINDOW"),
+		PurgeInterval:        os.Getenv("PURGE_INTERVAL"),
+		PurgeRetention:       os.Getenv("PURGE_RETENTION"),
+		MetricsEnabled:       os.Getenv("METRICS_ENABLED"),
+
+		DurableMonitors:     os.Getenv("DURABLE_MONITORS"),
+		MonitorLease:        os.Getenv("MONITOR_LEASE"),
+		MonitorPollInterval: os.Getenv("MONITOR_POLL_INTERVAL"),
+
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+}
@@ -0,0 +1,51 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeEnvFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("reads the file without overriding the process", func(t *testing.T) {
+		t.Setenv(ENV_FILE, writeEnvFile(t, "AUCTION_INTERVAL=42s\nADMIN_TOKEN=from-file\n"))
+		// Setenv restores the variable afterwards; it must be unset to be loaded
+		t.Setenv("AUCTION_INTERVAL", "")
+		os.Unsetenv("AUCTION_INTERVAL")
+		t.Setenv("ADMIN_TOKEN", "from-process")
+
+		config, err := Load()
+		assert.Nil(t, err)
+		assert.Equal(t, "42s", config.AuctionInterval)
+		assert.Equal(t, "42s", os.Getenv("AUCTION_INTERVAL"))
+		assert.Equal(t, "from-process", config.AdminToken)
+	})
+
+	t.Run("missing explicit file is an error", func(t *testing.T) {
+		t.Setenv(ENV_FILE, filepath.Join(t.TempDir(), "missing.env"))
+
+		_, err := Load()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("missing default file is ignored", func(t *testing.T) {
+		t.Setenv(ENV_FILE, "")
+		workingDir, err := os.Getwd()
+		assert.Nil(t, err)
+		assert.Nil(t, os.Chdir(t.TempDir()))
+		defer os.Chdir(workingDir)
+
+		_, err = Load()
+		assert.Nil(t, err)
+	})
+}
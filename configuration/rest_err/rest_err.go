@@ -10,6 +10,9 @@ type RestErr struct {
 	Err     string   `json:"err"`
 	Code    int      `json:"code"`
 	Causes  []Causes `json:"causes"`
+
+	// Errors maps invalid fields to their messages, see NewValidationError
+	Errors map[string]string `json:"errors,omitempty"`
 }
 
 type Causes struct {
@@ -24,11 +27,16 @@ func (r *RestErr) Error() string {
 func ConvertError(internalError *internal_error.InternalError) *RestErr {
 	switch internalError.Err {
 	case "bad_request":
-		return NewBadRequestError(internalError.Error())
+		if internalError.Fields != nil {
+			return NewValidationError(internalError.Message, internalError.Fields)
+		}
+		return NewBadRequestError(internalError.Message)
 	case "not_found":
-		return NewNotFoundError(internalError.Error())
+		return NewNotFoundError(internalError.Message)
+	case "conflict":
+		return NewConflictError(internalError.Message)
 	default:
-		return NewInternalServerError(internalError.Error())
+		return NewInternalServerError(internalError.Message)
 	}
 }
 
@@ -41,6 +49,18 @@ func NewBadRequestError(message string, causes ...Causes) *RestErr {
 	}
 }
 
+// NewValidationError is a bad request listing the message of every invalid
+// field under "errors".
+func NewValidationError(message string, fields map[string]string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "bad_request",
+		Code:    http.StatusBadRequest,
+		Causes:  nil,
+		Errors:  fields,
+	}
+}
+
 func NewInternalServerError(message string) *RestErr {
 	return &RestErr{
 		Message: message,
@@ -58,3 +78,39 @@ func NewNotFoundError(message string) *RestErr {
 		Causes:  nil,
 	}
 }
+
+func NewConflictError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "conflict",
+		Code:    http.StatusConflict,
+		Causes:  nil,
+	}
+}
+
+func NewUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "unauthorized",
+		Code:    http.StatusUnauthorized,
+		Causes:  nil,
+	}
+}
+
+func NewGatewayTimeoutError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "gateway_timeout",
+		Code:    http.StatusGatewayTimeout,
+		Causes:  nil,
+	}
+}
+
+func NewServiceUnavailableError(message string) *RestErr {
+	return &RestErr{
+		Message: message,
+		Err:     "service_unavailable",
+		Code:    http.StatusServiceUnavailable,
+		Causes:  nil,
+	}
+}
@@ -0,0 +1,126 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock that only moves when Advance is called. Timers
+// created from it fire once the fake time reaches their deadline.
+type FakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{}
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now:     now,
+		changed: make(chan struct{}),
+	}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	timer := &fakeTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		channel:  make(chan time.Time, 1),
+	}
+
+	if d <= 0 {
+		timer.channel <- c.now
+		return timer
+	}
+
+	c.timers = append(c.timers, timer)
+	c.notifyLocked()
+	return timer
+}
+
+// Advance moves the clock forward by d and fires every timer whose
+// deadline has been reached.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.channel <- c.now
+	}
+	c.timers = pending
+	c.notifyLocked()
+}
+
+// BlockUntil waits until at least n timers are pending, so a test can
+// advance the clock only after the code under test has started waiting.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mutex.Lock()
+		pending := len(c.timers)
+		changed := c.changed
+		c.mutex.Unlock()
+
+		if pending >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// PendingTimers returns how many timers are waiting to fire.
+func (c *FakeClock) PendingTimers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.timers)
+}
+
+func (c *FakeClock) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *FakeClock) stop(timer *fakeTimer) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, pending := range c.timers {
+		if pending == timer {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.notifyLocked()
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	channel  chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.channel
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.stop(t)
+}
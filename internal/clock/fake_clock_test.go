@@ -0,0 +1,75 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClockFiresTimersOnAdvance(t *testing.T) {
+	start := time.Unix(1718985600, 0)
+	fakeClock := NewFakeClock(start)
+
+	timer := fakeClock.NewTimer(time.Minute)
+
+	fakeClock.Advance(30 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired before its deadline")
+	default:
+	}
+
+	fakeClock.Advance(30 * time.Second)
+	select {
+	case fired := <-timer.C():
+		assert.Equal(t, start.Add(time.Minute), fired)
+	default:
+		t.Fatal("timer did not fire at its deadline")
+	}
+	assert.Equal(t, start.Add(time.Minute), fakeClock.Now())
+}
+
+func TestFakeClockStoppedTimerNeverFires(t *testing.T) {
+	fakeClock := NewFakeClock(time.Unix(1718985600, 0))
+
+	timer := fakeClock.NewTimer(time.Minute)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+
+	fakeClock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	fakeClock := NewFakeClock(time.Unix(1718985600, 0))
+
+	done := make(chan struct{})
+	go func() {
+		fakeClock.BlockUntil(2)
+		close(done)
+	}()
+
+	fakeClock.NewTimer(time.Minute)
+	fakeClock.NewTimer(time.Minute)
+
+	<-done
+}
+
+func TestFakeClockPendingTimers(t *testing.T) {
+	fakeClock := NewFakeClock(time.Unix(1718985600, 0))
+
+	short := fakeClock.NewTimer(time.Second)
+	fakeClock.NewTimer(time.Minute)
+	assert.Equal(t, 2, fakeClock.PendingTimers())
+
+	short.Stop()
+	assert.Equal(t, 1, fakeClock.PendingTimers())
+
+	fakeClock.Advance(time.Minute)
+	assert.Equal(t, 0, fakeClock.PendingTimers())
+}
@@ -2,25 +2,28 @@ package auction_entity
 
 import (
 	"context"
+	"fmt"
 	"github.com/danielencestari/lab03/internal/internal_error"
+	"net/url"
 	"time"
-
-	"github.com/google/uuid"
 )
 
+// MaxImageURLs is the most images an auction may reference.
+const MaxImageURLs = 10
+
 func CreateAuction(
 	productName, category, description string,
 	condition ProductCondition) (*Auction, *internal_error.InternalError) {
-	auction := &Auction{
-		Id:          uuid.New().String(),
-		ProductName: productName,
-		Category:    category,
-		Description: description,
-		Condition:   condition,
-		Status:      Active,
-		Timestamp:   time.Now(),
-	}
+	return CreateAuctionWithIDGenerator(UUIDGenerator{}, productName, category, description, condition)
+}
 
+// CreateAuctionWithIDGenerator is CreateAuction with the id taken from
+// idGenerator, so tests can predict the ids of created auctions.
+func CreateAuctionWithIDGenerator(
+	idGenerator IDGenerator,
+	productName, category, description string,
+	condition ProductCondition) (*Auction, *internal_error.InternalError) {
+	auction := NewAuction(idGenerator, productName, category, description, condition)
 	if err := auction.Validate(); err != nil {
 		return nil, err
 	}
@@ -28,26 +31,137 @@ func CreateAuction(
 	return auction, nil
 }
 
+// NewAuction builds an Active auction like CreateAuctionWithIDGenerator
+// but leaves it unvalidated, for callers that set more fields first; they
+// must call Validate on the final entity.
+func NewAuction(
+	idGenerator IDGenerator,
+	productName, category, description string,
+	condition ProductCondition) *Auction {
+	return &Auction{
+		Id:          idGenerator.NewID(),
+		ProductName: productName,
+		Category:    NormalizeCategory(category),
+		Description: description,
+		Condition:   condition,
+		Status:      Active,
+		Timestamp:   time.Now().UTC(),
+	}
+}
+
+// Validate reports every invalid field at once through a validation error.
 func (au *Auction) Validate() *internal_error.InternalError {
-	if len(au.ProductName) <= 1 ||
-		len(au.Category) <= 2 ||
-		len(au.Description) <= 10 && (au.Condition != New &&
-			au.Condition != Refurbished &&
-			au.Condition != Used) {
-		return internal_error.NewBadRequestError("invalid auction object")
+	fields := make(map[string]string)
+
+	if len(au.ProductName) <= 1 {
+		fields["product_name"] = "must have at least 2 characters"
+	}
+
+	if len(au.Category) <= 2 {
+		fields["category"] = "must have at least 3 characters"
+	}
+
+	// A short description is only rejected together with an unknown condition
+	if len(au.Description) <= 10 && (au.Condition != New &&
+		au.Condition != Refurbished &&
+		au.Condition != Used) {
+		fields["description"] = "must have more than 10 characters"
+		fields["condition"] = "must be new, used or refurbished"
+	}
+
+	if message := validateImageURLs(au.ImageURLs); message != "" {
+		fields["image_urls"] = message
+	}
+
+	if len(fields) > 0 {
+		return internal_error.NewValidationError(fields)
 	}
 
 	return nil
 }
 
+// validateImageURLs accepts up to MaxImageURLs absolute http(s) URLs and
+// returns what is wrong otherwise.
+func validateImageURLs(imageURLs []string) string {
+	if len(imageURLs) > MaxImageURLs {
+		return fmt.Sprintf("must have at most %d urls", MaxImageURLs)
+	}
+
+	for _, imageURL := range imageURLs {
+		parsed, err := url.ParseRequestURI(imageURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Sprintf("%q is not a valid http or https url", imageURL)
+		}
+	}
+
+	return ""
+}
+
 type Auction struct {
 	Id          string
 	ProductName string
 	Category    string
+	Subcategory string
 	Description string
 	Condition   ProductCondition
 	Status      AuctionStatus
-	Timestamp   time.Time
+
+	// ImageURLs references the product images, validated on create
+	ImageURLs []string
+
+	// OwnerId identifies the seller, when known; it is what
+	// MAX_ACTIVE_PER_OWNER counts against
+	OwnerId string
+
+	// Timestamp, EndTime and ClosedAt are always in UTC, regardless of the
+	// local zone; repositories store them as Unix seconds
+	Timestamp time.Time
+	EndTime   time.Time
+	ClosedAt  time.Time
+
+	WinnerBidId string
+	Version     int64
+	BidCount    int64
+
+	// CloseReason is one of the CloseReason constants once the auction is
+	// closed, and empty while it is active
+	CloseReason string
+
+	// PausedRemaining is the time the auction had left when it was paused,
+	// set only while it is Paused
+	PausedRemaining time.Duration
+
+	// IdempotencyKey, when set, makes retried creations return the auction
+	// first created with the same key instead of a duplicate
+	IdempotencyKey string
+
+	// Duration, when positive, replaces the configured auction interval for
+	// this auction only; it is not stored
+	Duration time.Duration
+}
+
+// AuctionUpdate holds the editable fields of an auction. Nil fields are
+// left untouched by AuctionRepositoryInterface.UpdateAuction.
+type AuctionUpdate struct {
+	ProductName *string
+	Category    *string
+	Description *string
+	Condition   *ProductCondition
+}
+
+func (au *Auction) ApplyUpdate(update AuctionUpdate) {
+	if update.ProductName != nil {
+		au.ProductName = *update.ProductName
+	}
+	if update.Category != nil {
+		au.Category = NormalizeCategory(*update.Category)
+	}
+	if update.Description != nil {
+		au.Description = *update.Description
+	}
+	if update.Condition != nil {
+		au.Condition = *update.Condition
+	}
 }
 
 type ProductCondition int
@@ -56,6 +170,20 @@ type AuctionStatus int
 const (
 	Active AuctionStatus = iota
 	Completed
+	Cancelled
+
+	// Paused freezes an auction, e.g. during a dispute: it takes no bids
+	// and its end is postponed by the time spent paused
+	Paused
+)
+
+// Close reasons record why an auction left the Active status.
+const (
+	CloseReasonTimeout         = "timeout"
+	CloseReasonManual          = "manual"
+	CloseReasonRecoveryLimit   = "limit_exceeded_on_restart"
+	CloseReasonClockSkew       = "clock_skew"
+	CloseReasonEmergencyCancel = "emergency_cancel"
 )
 
 const (
@@ -64,21 +192,158 @@ const (
 	Refurbished
 )
 
+// AuctionFilter narrows FindAuctions and FindAuctionSummaries. Zero values
+// leave the matching criterion out.
+type AuctionFilter struct {
+	Status      AuctionStatus
+	Category    string
+	ProductName string
+
+	// Categories matches any of several categories, together with Category
+	// when both are set
+	Categories []string
+
+	// Subcategory narrows a category listing, e.g. "Phones" within
+	// "Electronics"
+	Subcategory string
+
+	// CreatedFrom and CreatedTo bound the creation time in unix seconds,
+	// inclusive
+	CreatedFrom int64
+	CreatedTo   int64
+
+	// HasWinner selects auctions with (true) or without (false) a winning
+	// bid; nil matches both
+	HasWinner *bool
+
+	// ExcludeStatuses leaves out auctions in any of these statuses, e.g.
+	// Completed and Cancelled to list only open auctions
+	ExcludeStatuses []AuctionStatus
+
+	// Page and PageSize restrict a listing to one page, oldest first;
+	// Page starts at 1 and a zero PageSize lists every match. Counts
+	// ignore them.
+	Page     int64
+	PageSize int64
+}
+
+// AuctionStats holds the raw totals behind the auction statistics; the
+// averages are derived from them by the caller.
+type AuctionStats struct {
+	Total     int64
+	Active    int64
+	Completed int64
+
+	// CompletedDuration is the summed lifetime of the completed auctions
+	CompletedDuration time.Duration
+
+	Bids int64
+}
+
+// TimeBucket is the width of the buckets of an auction creation series.
+type TimeBucket string
+
+const (
+	HourBucket TimeBucket = "hour"
+	DayBucket  TimeBucket = "day"
+)
+
+// AuctionCountBucket counts the auctions created from Start (UTC) to the
+// start of the next bucket.
+type AuctionCountBucket struct {
+	Start time.Time
+	Count int64
+}
+
+// StatusChange is one entry of the status history of an auction.
+type StatusChange struct {
+	AuctionId string
+	From      AuctionStatus
+	To        AuctionStatus
+	At        time.Time
+	Reason    string
+
+	// CloseReason is set when the change closed the auction
+	CloseReason string
+}
+
 type AuctionRepositoryInterface interface {
 	CreateAuction(
 		ctx context.Context,
 		auctionEntity *Auction) *internal_error.InternalError
 
 	FindAuctions(
-		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]Auction, *internal_error.InternalError)
+		ctx context.Context, filter AuctionFilter) ([]Auction, *internal_error.InternalError)
 
 	FindAuctionById(
 		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
 
+	// FindAuctionSummaries and FindAuctionSummaryById only fill Id,
+	// ProductName, Category, Status and EndTime.
+	FindAuctionSummaries(
+		ctx context.Context, filter AuctionFilter) ([]Auction, *internal_error.InternalError)
+
+	FindAuctionSummaryById(
+		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
+
+	CountAuctions(
+		ctx context.Context, filter AuctionFilter) (int64, *internal_error.InternalError)
+
+	// FindAuctionsStatusByIds returns the status of each of auctionIds that
+	// exists; missing ids are left out of the map.
+	FindAuctionsStatusByIds(
+		ctx context.Context, auctionIds []string) (map[string]AuctionStatus, *internal_error.InternalError)
+
 	UpdateAuctionStatus(
 		ctx context.Context,
 		auctionId string,
 		status AuctionStatus) *internal_error.InternalError
+
+	UpdateAuction(
+		ctx context.Context,
+		auctionId string,
+		expectedVersion int64,
+		update AuctionUpdate) (*Auction, *internal_error.InternalError)
+
+	CancelAllActive(
+		ctx context.Context, reason string) (int64, *internal_error.InternalError)
+
+	PurgeCompletedBefore(
+		ctx context.Context, before time.Time) (int64, *internal_error.InternalError)
+
+	// PauseAuction freezes an active auction, keeping the time it has left.
+	PauseAuction(ctx context.Context, auctionId string) *internal_error.InternalError
+
+	// ResumeAuction reopens a paused auction, which then ends once the time
+	// it had left when paused has run out again.
+	ResumeAuction(ctx context.Context, auctionId string) *internal_error.InternalError
+
+	// FindStatusHistory pages through the status changes of an auction,
+	// oldest first, and returns their total; page starts at 1.
+	FindStatusHistory(
+		ctx context.Context,
+		auctionId string,
+		page, pageSize int64) ([]StatusChange, int64, *internal_error.InternalError)
+
+	// SetMaxConcurrentAuctions changes the concurrency limit at runtime;
+	// active auctions over a lowered limit are left running.
+	SetMaxConcurrentAuctions(n int64) *internal_error.InternalError
+
+	// SetMaintenanceMode makes CreateAuction refuse new auctions with a
+	// conflict while enabled; everything else keeps working.
+	SetMaintenanceMode(enabled bool)
+
+	AuctionStats(ctx context.Context) (*AuctionStats, *internal_error.InternalError)
+
+	// CountAuctionsByTime counts the auctions created within [from, to) per
+	// bucket, oldest first; empty buckets are left out. Zero bounds are open.
+	CountAuctionsByTime(
+		ctx context.Context,
+		bucket TimeBucket,
+		from, to time.Time) ([]AuctionCountBucket, *internal_error.InternalError)
+
+	// FindAuctionsEndingBefore lists the active auctions whose end_time is
+	// not after before, soonest first.
+	FindAuctionsEndingBefore(
+		ctx context.Context, before time.Time) ([]Auction, *internal_error.InternalError)
 }
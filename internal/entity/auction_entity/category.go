@@ -0,0 +1,40 @@
+package auction_entity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NormalizeCategory returns the canonical casing of a category: words
+// separated by single spaces, each capitalized and lower-cased otherwise
+// ("home  APPLIANCES" becomes "Home Appliances"). Categories are stored
+// and filtered in this form so that listings group regardless of how
+// clients typed them.
+func NormalizeCategory(category string) string {
+	words := strings.Fields(category)
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + strings.ToLower(word[size:])
+	}
+
+	return strings.Join(words, " ")
+}
+
+// FilterCategories returns the normalized categories the filter accepts,
+// Category first and without duplicates. An empty result matches every
+// category.
+func (f AuctionFilter) FilterCategories() []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, category := range append([]string{f.Category}, f.Categories...) {
+		normalized := NormalizeCategory(category)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		categories = append(categories, normalized)
+	}
+
+	return categories
+}
@@ -0,0 +1,15 @@
+package auction_entity
+
+import "github.com/google/uuid"
+
+// IDGenerator produces the ids of new auctions.
+type IDGenerator interface {
+	NewID() string
+}
+
+// UUIDGenerator is the default IDGenerator, backed by random UUIDs.
+type UUIDGenerator struct{}
+
+func (UUIDGenerator) NewID() string {
+	return uuid.New().String()
+}
@@ -11,17 +11,25 @@ type Bid struct {
 	Id        string
 	UserId    string
 	AuctionId string
-	Amount    float64
+	Amount    Cents
 	Timestamp time.Time
 }
 
-func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.InternalError) {
+// BidderRank is the highest bid of one user on an auction, as listed by
+// BidEntityRepository.TopBidders.
+type BidderRank struct {
+	UserId    string
+	Amount    Cents
+	Timestamp time.Time
+}
+
+func CreateBid(userId, auctionId string, amount Cents) (*Bid, *internal_error.InternalError) {
 	bid := &Bid{
 		Id:        uuid.New().String(),
 		UserId:    userId,
 		AuctionId: auctionId,
 		Amount:    amount,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().UTC(),
 	}
 
 	if err := bid.Validate(); err != nil {
@@ -48,9 +56,43 @@ type BidEntityRepository interface {
 		ctx context.Context,
 		bidEntities []Bid) *internal_error.InternalError
 
+	// ValidateBid checks a bid against its auction before it is queued for
+	// CreateBid.
+	ValidateBid(ctx context.Context, bid Bid) *internal_error.InternalError
+
 	FindBidByAuctionId(
 		ctx context.Context, auctionId string) ([]Bid, *internal_error.InternalError)
 
+	// FindLatestBidsByAuctionId lists the limit most recent bids of an
+	// auction, newest first, along with how many bids it has in total.
+	FindLatestBidsByAuctionId(
+		ctx context.Context, auctionId string, limit int64) ([]Bid, int64, *internal_error.InternalError)
+
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	CurrentHighBid(
+		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	RetractBid(
+		ctx context.Context, bidId string) *internal_error.InternalError
+
+	// FindBidsByUser lists one page of the bids placed by userId, newest
+	// first.
+	FindBidsByUser(
+		ctx context.Context, userId string, page, pageSize int64) ([]Bid, *internal_error.InternalError)
+
+	// FindBidsByUserAfter lists up to limit bids of userId in the same
+	// order, starting right after the bid at (afterTimestamp, afterId).
+	FindBidsByUserAfter(
+		ctx context.Context,
+		userId string,
+		afterTimestamp time.Time,
+		afterId string,
+		limit int64) ([]Bid, *internal_error.InternalError)
+
+	// TopBidders ranks the distinct bidders of an auction by their highest
+	// bid; ties go to whoever placed that amount first.
+	TopBidders(
+		ctx context.Context, auctionId string, n int) ([]BidderRank, *internal_error.InternalError)
 }
@@ -0,0 +1,100 @@
+package bid_entity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Cents is a money amount in hundredths of the currency unit. Keeping it
+// an integer makes sums and comparisons exact, unlike float64 where
+// 0.1 + 0.2 != 0.3.
+type Cents int64
+
+// ParseCents reads a decimal amount such as "10", "10.5" or "10.50"
+// without going through float64. More than two decimal places, signs
+// other than a leading minus and exponents are rejected.
+func ParseCents(value string) (Cents, error) {
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	units, fraction, hasFraction := strings.Cut(value, ".")
+	if units == "" || (hasFraction && fraction == "") || len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid amount %q: expected a decimal with up to two places", value)
+	}
+	for _, digit := range units + fraction {
+		if digit < '0' || digit > '9' {
+			return 0, fmt.Errorf("invalid amount %q: expected a decimal with up to two places", value)
+		}
+	}
+
+	wholeUnits, err := strconv.ParseInt(units, 10, 64)
+	if err != nil || wholeUnits > (math.MaxInt64-99)/100 {
+		return 0, fmt.Errorf("invalid amount %q: out of range", value)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+	hundredths, _ := strconv.ParseInt(fraction, 10, 64)
+
+	cents := Cents(wholeUnits*100 + hundredths)
+	if negative {
+		cents = -cents
+	}
+	return cents, nil
+}
+
+// String formats the amount with exactly two decimal places, e.g. "0.30".
+func (c Cents) String() string {
+	sign := ""
+	value := int64(c)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+// Format renders the amount as currency with thousands separators, e.g.
+// Format("$") gives "$1,234.50" and "-$0.30" for negative amounts.
+func (c Cents) Format(symbol string) string {
+	amount := c.String()
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign = "-"
+		amount = amount[1:]
+	}
+
+	units, fraction, _ := strings.Cut(amount, ".")
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return sign + symbol + grouped.String() + "." + fraction
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places.
+func (c Cents) MarshalJSON() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number, or a string holding one, as an exact
+// decimal amount. Like other types, null leaves the amount unchanged.
+func (c *Cents) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "null" {
+		return nil
+	}
+
+	cents, err := ParseCents(value)
+	if err != nil {
+		return err
+	}
+
+	*c = cents
+	return nil
+}
@@ -0,0 +1,86 @@
+package bid_entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCentsArithmeticIsExact(t *testing.T) {
+	tenCents, err := ParseCents("0.1")
+	assert.Nil(t, err)
+	twentyCents, err := ParseCents("0.2")
+	assert.Nil(t, err)
+	thirtyCents, err := ParseCents("0.3")
+	assert.Nil(t, err)
+
+	assert.Equal(t, thirtyCents, tenCents+twentyCents)
+	assert.True(t, tenCents+twentyCents >= thirtyCents)
+	assert.Equal(t, "0.30", (tenCents + twentyCents).String())
+
+	var total Cents
+	for i := 0; i < 10; i++ {
+		total += tenCents
+	}
+	assert.Equal(t, Cents(100), total)
+}
+
+func TestParseCents(t *testing.T) {
+	for value, expected := range map[string]Cents{
+		"10":       1000,
+		"10.5":     1050,
+		"10.05":    1005,
+		"0.01":     1,
+		"-1.50":    -150,
+		"00012.30": 1230,
+	} {
+		cents, err := ParseCents(value)
+		assert.Nil(t, err, value)
+		assert.Equal(t, expected, cents, value)
+	}
+
+	for _, value := range []string{"", ".5", "1.", "1.005", "1e3", "+1", "abc", "1,50", "99999999999999999999"} {
+		_, err := ParseCents(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestCentsFormat(t *testing.T) {
+	assert.Equal(t, "$0.05", Cents(5).Format("$"))
+	assert.Equal(t, "$999.99", Cents(99999).Format("$"))
+	assert.Equal(t, "R$ 1,234.50", Cents(123450).Format("R$ "))
+	assert.Equal(t, "$1,000,000.00", Cents(100000000).Format("$"))
+	assert.Equal(t, "-$0.30", Cents(-30).Format("$"))
+}
+
+func TestCentsJSON(t *testing.T) {
+	var input struct {
+		Amount Cents `json:"amount"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"amount": 0.3}`), &input))
+	assert.Equal(t, Cents(30), input.Amount)
+	assert.Nil(t, json.Unmarshal([]byte(`{"amount": "12.34"}`), &input))
+	assert.Equal(t, Cents(1234), input.Amount)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"amount": 0.001}`), &input))
+
+	output, err := json.Marshal(map[string]Cents{"amount": 1050})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"amount": 10.50}`, string(output))
+}
+
+func TestCreateBidRejectsNonPositiveAmounts(t *testing.T) {
+	userId := "8f0c6a1e-4a5b-4c8d-9e2f-1a2b3c4d5e6f"
+	auctionId := "1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e"
+
+	for _, amount := range []Cents{0, -1} {
+		_, err := CreateBid(userId, auctionId, amount)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "bad_request", err.Err)
+		}
+	}
+
+	bid, err := CreateBid(userId, auctionId, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, Cents(1), bid.Amount)
+}
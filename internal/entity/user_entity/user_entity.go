@@ -13,4 +13,9 @@ type User struct {
 type UserRepositoryInterface interface {
 	FindUserById(
 		ctx context.Context, userId string) (*User, *internal_error.InternalError)
+
+	// FindUsers lists one page of users ordered by name, with the total
+	// number of users; page starts at 1.
+	FindUsers(
+		ctx context.Context, page, pageSize int64) ([]User, int64, *internal_error.InternalError)
 }
@@ -0,0 +1,40 @@
+package admin_controller
+
+import (
+	"crypto/subtle"
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+const adminTokenHeader = "X-Admin-Token"
+
+type AdminController struct {
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+	adminToken     string
+}
+
+// NewAdminController builds the admin endpoints. Every request must carry
+// adminToken in the X-Admin-Token header; an empty adminToken disables
+// the admin endpoints altogether.
+func NewAdminController(
+	auctionUseCase auction_usecase.AuctionUseCaseInterface, adminToken string) *AdminController {
+	return &AdminController{
+		auctionUseCase: auctionUseCase,
+		adminToken:     adminToken,
+	}
+}
+
+func (a *AdminController) authorize(c *gin.Context) bool {
+	token := c.GetHeader(adminTokenHeader)
+
+	if a.adminToken == "" ||
+		subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) != 1 {
+		restErr := rest_err.NewUnauthorizedError("Invalid or missing admin token")
+
+		c.JSON(restErr.Code, restErr)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package admin_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/infra/api/web/validation"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (a *AdminController) CancelAllActiveAuctions(c *gin.Context) {
+	if !a.authorize(c) {
+		return
+	}
+
+	var cancelAllInputDTO auction_usecase.CancelAllInputDTO
+
+	if err := c.ShouldBindJSON(&cancelAllInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := a.auctionUseCase.CancelAllActive(c.Request.Context(), cancelAllInputDTO.Reason)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
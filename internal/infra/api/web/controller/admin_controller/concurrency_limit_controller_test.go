@@ -0,0 +1,90 @@
+package admin_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConcurrencyLimit(t *testing.T) {
+	os.Setenv("MAX_CONCURRENT_AUCTIONS", "2")
+	defer os.Unsetenv("MAX_CONCURRENT_AUCTIONS")
+
+	gin.SetMode(gin.TestMode)
+	repository := auction.NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repository.Close()
+
+	controller := NewAdminController(auction_usecase.NewAuctionUseCase(repository, nil), "secret")
+	router := gin.New()
+	router.POST("/admin/limit", controller.SetConcurrencyLimit)
+
+	ctx := context.Background()
+	createAuction := func() *internal_error.InternalError {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		return repository.CreateAuction(ctx, auction)
+	}
+	setLimit := func(body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/admin/limit", strings.NewReader(body))
+		request.Header.Set(adminTokenHeader, "secret")
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("rejects requests without the admin token", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/admin/limit",
+			strings.NewReader(`{"max_concurrent_auctions": 5}`)))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("rejects a limit that is not positive", func(t *testing.T) {
+		for _, body := range []string{`{"max_concurrent_auctions": 0}`, `{"max_concurrent_auctions": -1}`, `{}`} {
+			assert.Equal(t, http.StatusBadRequest, setLimit(body).Code, body)
+		}
+		assert.NotNil(t, repository.SetMaxConcurrentAuctions(0))
+	})
+
+	t.Run("raising the limit accepts more auctions", func(t *testing.T) {
+		assert.Nil(t, createAuction())
+		assert.Nil(t, createAuction())
+		assert.NotNil(t, createAuction())
+
+		recorder := setLimit(`{"max_concurrent_auctions": 3}`)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var output auction_usecase.ConcurrencyLimitOutputDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &output))
+		assert.Equal(t, int64(3), output.MaxConcurrentAuctions)
+
+		assert.Nil(t, createAuction())
+		assert.NotNil(t, createAuction())
+	})
+
+	t.Run("lowering the limit refuses new auctions but keeps the active ones", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, setLimit(`{"max_concurrent_auctions": 1}`).Code)
+
+		assert.NotNil(t, createAuction())
+		assert.Equal(t, int64(3), repository.ActiveAuctionsCount())
+
+		active, err := repository.FindAuctions(ctx, auction_entity.AuctionFilter{Status: auction_entity.Active})
+		assert.Nil(t, err)
+		assert.Len(t, active, 3)
+	})
+}
@@ -0,0 +1,86 @@
+package admin_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMaintenanceMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repository := auction.NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repository.Close()
+
+	controller := NewAdminController(auction_usecase.NewAuctionUseCase(repository, nil), "secret")
+	router := gin.New()
+	router.POST("/admin/maintenance", controller.SetMaintenanceMode)
+
+	ctx := context.Background()
+	createAuction := func() (*auction_entity.Auction, *internal_error.InternalError) {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		return auction, repository.CreateAuction(ctx, auction)
+	}
+	setMaintenance := func(body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/admin/maintenance", strings.NewReader(body))
+		request.Header.Set(adminTokenHeader, "secret")
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	existing, err := createAuction()
+	assert.Nil(t, err)
+
+	t.Run("rejects requests without the admin token", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/admin/maintenance",
+			strings.NewReader(`{"enabled": true}`)))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("requires the enabled flag", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, setMaintenance(`{}`).Code)
+	})
+
+	t.Run("blocks creates but not reads while enabled", func(t *testing.T) {
+		recorder := setMaintenance(`{"enabled": true}`)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var output auction_usecase.MaintenanceModeOutputDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &output))
+		assert.True(t, output.Enabled)
+
+		_, createErr := createAuction()
+		if assert.NotNil(t, createErr) {
+			assert.Equal(t, "conflict", createErr.Err)
+			assert.Contains(t, createErr.Message, "maintenance")
+		}
+
+		found, findErr := repository.FindAuctionById(ctx, existing.Id)
+		assert.Nil(t, findErr)
+		assert.Equal(t, existing.Id, found.Id)
+		assert.Nil(t, repository.UpdateAuctionStatus(ctx, existing.Id, auction_entity.Completed))
+	})
+
+	t.Run("accepts creates again once disabled", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, setMaintenance(`{"enabled": false}`).Code)
+
+		_, createErr := createAuction()
+		assert.Nil(t, createErr)
+	})
+}
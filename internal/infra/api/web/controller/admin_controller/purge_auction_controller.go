@@ -0,0 +1,36 @@
+package admin_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/infra/api/web/validation"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+func (a *AdminController) PurgeCompletedAuctions(c *gin.Context) {
+	if !a.authorize(c) {
+		return
+	}
+
+	var purgeInputDTO auction_usecase.PurgeInputDTO
+
+	if err := c.ShouldBindJSON(&purgeInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := a.auctionUseCase.PurgeCompletedBefore(
+		c.Request.Context(), time.Unix(purgeInputDTO.Before, 0))
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,41 @@
+package admin_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (a *AdminController) SimulateAuctions(c *gin.Context) {
+	if !a.authorize(c) {
+		return
+	}
+
+	count, errConv := strconv.Atoi(c.Query("count"))
+	if errConv != nil {
+		restErr := rest_err.NewBadRequestError("Error trying to validate count param")
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	duration, errConv := time.ParseDuration(c.Query("duration"))
+	if errConv != nil {
+		restErr := rest_err.NewBadRequestError("Error trying to validate duration param")
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	output, err := a.auctionUseCase.SimulateAuctions(c.Request.Context(), count, duration)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,78 @@
+package admin_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateAuctions(t *testing.T) {
+	os.Setenv("MAX_CONCURRENT_AUCTIONS", "3")
+	defer os.Unsetenv("MAX_CONCURRENT_AUCTIONS")
+
+	gin.SetMode(gin.TestMode)
+	fakeClock := clock.NewFakeClock(time.Now())
+	repository := auction.NewMemoryAuctionRepository(fakeClock)
+	defer repository.Close()
+
+	controller := NewAdminController(auction_usecase.NewAuctionUseCase(repository, nil), "secret")
+	router := gin.New()
+	router.POST("/admin/simulate", controller.SimulateAuctions)
+
+	t.Run("rejects requests without the admin token", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost,
+			"/admin/simulate?count=1&duration=2s", nil))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("rejects an invalid duration", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/admin/simulate?count=1&duration=abc", nil)
+		request.Header.Set(adminTokenHeader, "secret")
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("respects the concurrency limit and closes the accepted auctions", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/admin/simulate?count=5&duration=2s", nil)
+		request.Header.Set(adminTokenHeader, "secret")
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var output auction_usecase.SimulateOutputDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &output))
+		assert.Equal(t, 5, output.Requested)
+		assert.Equal(t, 3, output.Accepted)
+		assert.Equal(t, 2, output.Rejected)
+		assert.Len(t, output.AuctionIds, 3)
+
+		// Os leilões simulados fecham após a duração informada
+		fakeClock.BlockUntil(1)
+		fakeClock.Advance(2 * time.Second)
+		assert.Eventually(t, func() bool {
+			for _, id := range output.AuctionIds {
+				found, err := repository.FindAuctionById(context.Background(), id)
+				if err != nil || found.Status != auction_entity.Completed {
+					return false
+				}
+			}
+			return true
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int64(0), repository.ActiveAuctionsCount())
+	})
+}
@@ -0,0 +1,26 @@
+package auction_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+func (u *AuctionController) GetAuctionsEndingSoon(c *gin.Context) {
+	within, errConv := time.ParseDuration(c.Query("within"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate within param, expected a duration such as 5m")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	auctions, err := u.auctionUseCase.GetAuctionsEndingSoon(c.Request.Context(), within)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctions)
+}
@@ -0,0 +1,75 @@
+package auction_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type auctionEndingSoonRepositoryMock struct {
+	auction_entity.AuctionRepositoryInterface
+
+	auctions []auction_entity.Auction
+	calls    int
+}
+
+func (m *auctionEndingSoonRepositoryMock) FindAuctionsEndingBefore(
+	ctx context.Context, before time.Time) ([]auction_entity.Auction, *internal_error.InternalError) {
+	m.calls++
+
+	var auctions []auction_entity.Auction
+	for _, auction := range m.auctions {
+		if !auction.EndTime.After(before) {
+			auctions = append(auctions, auction)
+		}
+	}
+	return auctions, nil
+}
+
+func TestGetAuctionsEndingSoon(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+	repository := &auctionEndingSoonRepositoryMock{auctions: []auction_entity.Auction{
+		{Id: "one-minute", Status: auction_entity.Active, EndTime: now.Add(time.Minute)},
+		{Id: "four-minutes", Status: auction_entity.Active, EndTime: now.Add(4 * time.Minute)},
+		{Id: "one-hour", Status: auction_entity.Active, EndTime: now.Add(time.Hour)},
+	}}
+	controller := NewAuctionController(auction_usecase.NewAuctionUseCase(repository, nil))
+
+	router := gin.New()
+	router.GET("/auctions/ending-soon", controller.GetAuctionsEndingSoon)
+
+	t.Run("returns the auctions ending within the window", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auctions/ending-soon?within=5m", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var auctions []auction_usecase.AuctionEndingSoonDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &auctions))
+		if assert.Len(t, auctions, 2) {
+			assert.Equal(t, "one-minute", auctions[0].Id)
+			assert.InDelta(t, 60, auctions[0].RemainingSeconds, 1)
+			assert.Equal(t, "four-minutes", auctions[1].Id)
+			assert.InDelta(t, 240, auctions[1].RemainingSeconds, 1)
+		}
+	})
+
+	for _, query := range []string{"", "within=abc", "within=5", "within=-5m", "within=0s", "within=48h"} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			calls := repository.calls
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auctions/ending-soon?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, calls, repository.calls)
+		})
+	}
+}
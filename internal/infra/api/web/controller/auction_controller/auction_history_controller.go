@@ -0,0 +1,46 @@
+package auction_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (u *AuctionController) GetAuctionHistory(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	page, errConv := pagination.ParsePageParam(c.Query("page"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate page param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	pageSize, errConv := pagination.ParsePageParam(c.Query("pageSize"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate pageSize param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	history, err := u.auctionUseCase.GetAuctionHistory(c.Request.Context(), auctionId, page, pageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
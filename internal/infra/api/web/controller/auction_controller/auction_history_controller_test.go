@@ -0,0 +1,99 @@
+package auction_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAuctionHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repository := auction.NewMemoryAuctionRepository(fakeClock)
+	defer repository.Close()
+	controller := NewAuctionController(auction_usecase.NewAuctionUseCase(repository, nil))
+
+	router := gin.New()
+	router.GET("/auctions/:auctionId/history", controller.GetAuctionHistory)
+
+	ctx := context.Background()
+	auctionEntity, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repository.CreateAuction(ctx, auctionEntity))
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+	assert.Eventually(t, func() bool {
+		stored, err := repository.FindAuctionById(ctx, auctionEntity.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+
+	t.Run("lists the transitions of an auto-closed auction", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodGet, "/auctions/"+auctionEntity.Id+"/history", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var history auction_usecase.AuctionHistoryDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &history))
+		assert.Equal(t, auctionEntity.Id, history.AuctionId)
+		assert.Equal(t, int64(1), history.Page)
+		assert.Equal(t, int64(1), history.Total)
+		if assert.Len(t, history.Changes, 1) {
+			change := history.Changes[0]
+			assert.Equal(t, auction_usecase.AuctionStatus(auction_entity.Active), change.From)
+			assert.Equal(t, auction_usecase.AuctionStatus(auction_entity.Completed), change.To)
+			assert.True(t, change.At.Equal(fakeClock.Now()))
+			assert.Equal(t, fakeClock.Now().UTC().Format(time.RFC3339), change.AtISO)
+			assert.Equal(t, auction_entity.CloseReasonTimeout, change.CloseReason)
+		}
+	})
+
+	t.Run("returns an empty page past the end", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodGet, "/auctions/"+auctionEntity.Id+"/history?page=2&pageSize=1", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var history auction_usecase.AuctionHistoryDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &history))
+		assert.Equal(t, int64(1), history.Total)
+		assert.Empty(t, history.Changes)
+	})
+
+	t.Run("maps a missing auction to 404", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodGet, "/auctions/"+uuid.New().String()+"/history", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	for _, path := range []string{
+		"/auctions/not-a-uuid/history",
+		"/auctions/" + auctionEntity.Id + "/history?page=0",
+		"/auctions/" + auctionEntity.Id + "/history?pageSize=abc",
+		"/auctions/" + auctionEntity.Id + "/history?pageSize=1000",
+	} {
+		t.Run("rejects "+path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
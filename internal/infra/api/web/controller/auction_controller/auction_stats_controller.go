@@ -0,0 +1,18 @@
+package auction_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *AuctionController) GetAuctionStats(c *gin.Context) {
+	stats, err := u.auctionUseCase.GetAuctionStats(c.Request.Context())
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
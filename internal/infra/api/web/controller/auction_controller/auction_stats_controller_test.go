@@ -0,0 +1,72 @@
+package auction_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type auctionStatsRepositoryMock struct {
+	auction_entity.AuctionRepositoryInterface
+
+	stats auction_entity.AuctionStats
+}
+
+func (m *auctionStatsRepositoryMock) AuctionStats(
+	ctx context.Context) (*auction_entity.AuctionStats, *internal_error.InternalError) {
+	stats := m.stats
+	return &stats, nil
+}
+
+func getAuctionStats(t *testing.T, stats auction_entity.AuctionStats) map[string]interface{} {
+	gin.SetMode(gin.TestMode)
+	controller := NewAuctionController(auction_usecase.NewAuctionUseCase(
+		&auctionStatsRepositoryMock{stats: stats}, nil))
+
+	router := gin.New()
+	router.GET("/auctions/stats", controller.GetAuctionStats)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auctions/stats", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var body map[string]interface{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return body
+}
+
+func TestGetAuctionStats(t *testing.T) {
+	t.Run("averages are derived from the totals", func(t *testing.T) {
+		body := getAuctionStats(t, auction_entity.AuctionStats{
+			Total:             4,
+			Active:            1,
+			Completed:         2,
+			CompletedDuration: 5 * time.Minute,
+			Bids:              10,
+		})
+
+		assert.Equal(t, 4.0, body["total_auctions"])
+		assert.Equal(t, 1.0, body["active_auctions"])
+		assert.Equal(t, 2.0, body["completed_auctions"])
+		assert.Equal(t, 150.0, body["average_duration_seconds"])
+		assert.Equal(t, 2.5, body["average_bids_per_auction"])
+	})
+
+	t.Run("empty data yields zeros and no bid average", func(t *testing.T) {
+		body := getAuctionStats(t, auction_entity.AuctionStats{})
+
+		assert.Equal(t, 0.0, body["total_auctions"])
+		assert.Equal(t, 0.0, body["average_duration_seconds"])
+		assert.Contains(t, body, "average_bids_per_auction")
+		assert.Nil(t, body["average_bids_per_auction"])
+	})
+}
@@ -0,0 +1,38 @@
+package auction_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *AuctionController) GetAuctionTimeseries(c *gin.Context) {
+	from, errConv := parseOptionalUnix(c.Query("from"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate from param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	to, errConv := parseOptionalUnix(c.Query("to"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate to param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	series, err := u.auctionUseCase.GetAuctionTimeseries(c.Request.Context(),
+		auction_usecase.AuctionTimeseriesInputDTO{
+			Bucket: c.Query("bucket"),
+			From:   from,
+			To:     to,
+		})
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
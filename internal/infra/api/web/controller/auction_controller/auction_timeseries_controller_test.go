@@ -0,0 +1,77 @@
+package auction_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type auctionTimeseriesRepositoryMock struct {
+	auction_entity.AuctionRepositoryInterface
+
+	bucket   auction_entity.TimeBucket
+	from, to time.Time
+}
+
+func (m *auctionTimeseriesRepositoryMock) CountAuctionsByTime(
+	ctx context.Context,
+	bucket auction_entity.TimeBucket,
+	from, to time.Time) ([]auction_entity.AuctionCountBucket, *internal_error.InternalError) {
+	m.bucket, m.from, m.to = bucket, from, to
+	return []auction_entity.AuctionCountBucket{
+		{Start: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), Count: 3},
+		{Start: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), Count: 1},
+	}, nil
+}
+
+func TestGetAuctionTimeseries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repository := &auctionTimeseriesRepositoryMock{}
+	controller := NewAuctionController(auction_usecase.NewAuctionUseCase(repository, nil))
+
+	router := gin.New()
+	router.GET("/auctions/timeseries", controller.GetAuctionTimeseries)
+
+	t.Run("returns the series", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+			"/auctions/timeseries?bucket=day&from=1710028800&to=1710201600", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var series []auction_usecase.AuctionTimeseriesPointDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &series))
+		assert.Equal(t, []auction_usecase.AuctionTimeseriesPointDTO{
+			{Bucket: "2024-03-10T00:00:00Z", Count: 3},
+			{Bucket: "2024-03-11T00:00:00Z", Count: 1},
+		}, series)
+
+		assert.Equal(t, auction_entity.DayBucket, repository.bucket)
+		assert.Equal(t, int64(1710028800), repository.from.Unix())
+		assert.Equal(t, int64(1710201600), repository.to.Unix())
+	})
+
+	t.Run("bucket defaults to hour", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auctions/timeseries", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, auction_entity.HourBucket, repository.bucket)
+		assert.True(t, repository.from.IsZero())
+	})
+
+	for _, query := range []string{"bucket=week", "from=abc", "from=20&to=10"} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auctions/timeseries?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
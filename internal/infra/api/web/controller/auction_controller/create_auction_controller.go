@@ -1,7 +1,6 @@
 package auction_controller
 
 import (
-	"context"
 	"github.com/danielencestari/lab03/configuration/rest_err"
 	"github.com/danielencestari/lab03/internal/infra/api/web/validation"
 	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
@@ -13,6 +12,10 @@ type AuctionController struct {
 	auctionUseCase auction_usecase.AuctionUseCaseInterface
 }
 
+type CreateAuctionOutputDTO struct {
+	Id string `json:"id"`
+}
+
 func NewAuctionController(auctionUseCase auction_usecase.AuctionUseCaseInterface) *AuctionController {
 	return &AuctionController{
 		auctionUseCase: auctionUseCase,
@@ -28,8 +31,9 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		c.JSON(restErr.Code, restErr)
 		return
 	}
+	auctionInputDTO.IdempotencyKey = c.GetHeader("Idempotency-Key")
 
-	err := u.auctionUseCase.CreateAuction(context.Background(), auctionInputDTO)
+	auctionId, err := u.auctionUseCase.CreateAuction(c.Request.Context(), auctionInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -37,5 +41,5 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, CreateAuctionOutputDTO{Id: auctionId})
 }
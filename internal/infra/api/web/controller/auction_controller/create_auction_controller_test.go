@@ -0,0 +1,37 @@
+package auction_controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAuctionReportsEveryInvalidField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// A validação falha antes de chegar ao repositório
+	controller := NewAuctionController(auction_usecase.NewAuctionUseCase(nil, nil))
+
+	router := gin.New()
+	router.POST("/auction", controller.CreateAuction)
+
+	body := `{"product_name": "a", "category": "ab", "description": "Notebook in great condition", "condition": 1}`
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/auction", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var response struct {
+		Err    string            `json:"err"`
+		Errors map[string]string `json:"errors"`
+	}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "bad_request", response.Err)
+	assert.Len(t, response.Errors, 2)
+	assert.Contains(t, response.Errors, "product_name")
+	assert.Contains(t, response.Errors, "category")
+}
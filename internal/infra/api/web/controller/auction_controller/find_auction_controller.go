@@ -1,13 +1,14 @@
 package auction_controller
 
 import (
-	"context"
 	"github.com/danielencestari/lab03/configuration/rest_err"
 	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 func (u *AuctionController) FindAuctionById(c *gin.Context) {
@@ -23,7 +24,19 @@ func (u *AuctionController) FindAuctionById(c *gin.Context) {
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindAuctionById(context.Background(), auctionId)
+	if isSummaryView(c) {
+		auctionSummary, err := u.auctionUseCase.FindAuctionSummaryById(c.Request.Context(), auctionId)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+
+		c.JSON(http.StatusOK, auctionSummary)
+		return
+	}
+
+	auctionData, err := u.auctionUseCase.FindAuctionById(c.Request.Context(), auctionId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -45,15 +58,156 @@ func (u *AuctionController) FindAuctions(c *gin.Context) {
 		return
 	}
 
-	auctions, err := u.auctionUseCase.FindAuctions(context.Background(),
-		auction_usecase.AuctionStatus(statusNumber), category, productName)
+	createdFrom, errConv := parseOptionalUnix(c.Query("createdFrom"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate createdFrom param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	createdTo, errConv := parseOptionalUnix(c.Query("createdTo"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate createdTo param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	hasWinner, errConv := parseOptionalBool(c.Query("hasWinner"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate hasWinner param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	excludeStatuses, errConv := parseStatusList(c.Query("excludeStatus"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate excludeStatus param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	page, errConv := pagination.ParsePageParam(c.Query("page"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate page param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	pageSize, errConv := pagination.ParsePageParam(c.Query("pageSize"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate pageSize param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	page, pageSize, err := pagination.Normalize(page, pageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	filterInput := auction_usecase.AuctionFilterInputDTO{
+		Status:      auction_usecase.AuctionStatus(statusNumber),
+		Category:    category,
+		Categories:  parseCategoryList(c.Query("categories")),
+		Subcategory: c.Query("subcategory"),
+		ProductName: productName,
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		HasWinner:   hasWinner,
+
+		ExcludeStatuses: excludeStatuses,
+
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	total, err := u.auctionUseCase.CountAuctions(c.Request.Context(), filterInput)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+
+	if isSummaryView(c) {
+		auctionSummaries, err := u.auctionUseCase.FindAuctionSummaries(c.Request.Context(), filterInput)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+
+		c.JSON(http.StatusOK, pagination.NewPageResponse(auctionSummaries, page, pageSize, total))
+		return
+	}
+
+	auctions, err := u.auctionUseCase.FindAuctions(c.Request.Context(), filterInput)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, pagination.NewPageResponse(auctions, page, pageSize, total))
+}
 
-	c.JSON(http.StatusOK, auctions)
+// isSummaryView reports whether the client asked for ?view=summary.
+func isSummaryView(c *gin.Context) bool {
+	return c.Query("view") == "summary"
+}
+
+// parseOptionalUnix parses a unix-seconds query param, returning 0 when empty.
+func parseOptionalUnix(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// parseOptionalBool parses a boolean query param, returning nil when empty.
+func parseOptionalBool(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// parseStatusList parses a comma-separated list of statuses, e.g. "1,2",
+// returning nil when empty.
+func parseStatusList(value string) ([]auction_usecase.AuctionStatus, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var statuses []auction_usecase.AuctionStatus
+	for _, part := range strings.Split(value, ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, auction_usecase.AuctionStatus(status))
+	}
+	return statuses, nil
+}
+
+// parseCategoryList splits a comma separated categories param, skipping
+// empty entries.
+func parseCategoryList(value string) []string {
+	var categories []string
+	for _, part := range strings.Split(value, ",") {
+		if category := strings.TrimSpace(part); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return categories
 }
 
 func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
@@ -69,7 +223,7 @@ func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionId)
+	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(c.Request.Context(), auctionId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
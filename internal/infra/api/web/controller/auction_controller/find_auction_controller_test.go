@@ -0,0 +1,90 @@
+package auction_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAuctionsPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repository := auction.NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repository.Close()
+	controller := NewAuctionController(auction_usecase.NewAuctionUseCase(repository, nil))
+
+	router := gin.New()
+	router.GET("/auction", controller.FindAuctions)
+
+	for i := 0; i < 5; i++ {
+		auctionEntity, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repository.CreateAuction(context.Background(), auctionEntity))
+	}
+
+	find := func(query string) (int, pagination.PageResponse[auction_usecase.AuctionOutputDTO]) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auction?status=0&"+query, nil))
+
+		var page pagination.PageResponse[auction_usecase.AuctionOutputDTO]
+		if recorder.Code == http.StatusOK {
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+		}
+		return recorder.Code, page
+	}
+
+	t.Run("partial last page", func(t *testing.T) {
+		code, page := find("page=3&pageSize=2")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, page.Data, 1)
+		assert.Equal(t, int64(3), page.Page)
+		assert.Equal(t, int64(2), page.PageSize)
+		assert.Equal(t, int64(5), page.Total)
+		assert.Equal(t, int64(3), page.TotalPages)
+	})
+
+	t.Run("defaults to the first page", func(t *testing.T) {
+		code, page := find("")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, page.Data, 5)
+		assert.Equal(t, int64(1), page.Page)
+		assert.Equal(t, pagination.DefaultPageSize, page.PageSize)
+		assert.Equal(t, int64(1), page.TotalPages)
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		code, page := find("category=Art")
+		assert.Equal(t, http.StatusOK, code)
+		assert.NotNil(t, page.Data)
+		assert.Empty(t, page.Data)
+		assert.Equal(t, int64(0), page.Total)
+		assert.Equal(t, int64(0), page.TotalPages)
+	})
+
+	t.Run("last allowed page", func(t *testing.T) {
+		code, page := find("page=10000&pageSize=100")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, page.Data)
+		assert.Equal(t, pagination.MaxPage, page.Page)
+	})
+
+	for _, query := range []string{
+		"page=0", "page=abc", "pageSize=-1", "pageSize=101", "page=10001", "page=9223372036854775807&pageSize=100",
+	} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			code, _ := find(query)
+			assert.Equal(t, http.StatusBadRequest, code)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package auction_controller
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (u *AuctionController) PauseAuction(c *gin.Context) {
+	u.changePause(c, u.auctionUseCase.PauseAuction)
+}
+
+func (u *AuctionController) ResumeAuction(c *gin.Context) {
+	u.changePause(c, u.auctionUseCase.ResumeAuction)
+}
+
+// changePause validates the auction id and answers with the auction as left
+// by change.
+func (u *AuctionController) changePause(
+	c *gin.Context,
+	change func(context.Context, string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError)) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	auctionData, err := change(c.Request.Context(), auctionId)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctionData)
+}
@@ -0,0 +1,141 @@
+package auction_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type auctionRepositoryMock struct {
+	auction_entity.AuctionRepositoryInterface
+
+	auctions map[string]auction_entity.Auction
+}
+
+func (m *auctionRepositoryMock) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction, ok := m.auctions[id]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("auction not found")
+	}
+
+	return &auction, nil
+}
+
+func (m *auctionRepositoryMock) UpdateAuction(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	update auction_entity.AuctionUpdate) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction, ok := m.auctions[auctionId]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("auction not found")
+	}
+
+	if auction.Version != expectedVersion {
+		return nil, internal_error.NewConflictError("auction was modified concurrently")
+	}
+
+	auction.ApplyUpdate(update)
+	auction.Version++
+	m.auctions[auctionId] = auction
+
+	return &auction, nil
+}
+
+func newTestRouter(repository auction_entity.AuctionRepositoryInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewAuctionController(auction_usecase.NewAuctionUseCase(repository, nil))
+
+	router := gin.New()
+	router.PATCH("/auction/:auctionId", controller.UpdateAuction)
+
+	return router
+}
+
+func TestUpdateAuction(t *testing.T) {
+	activeId := uuid.New().String()
+	completedId := uuid.New().String()
+
+	repository := &auctionRepositoryMock{auctions: map[string]auction_entity.Auction{
+		activeId: {
+			Id:          activeId,
+			ProductName: "iPhone 15 Pro",
+			Category:    "Electronics",
+			Description: "iPhone 15 Pro em excelente estado",
+			Condition:   auction_entity.Used,
+			Status:      auction_entity.Active,
+		},
+		completedId: {
+			Id:          completedId,
+			ProductName: "iPhone 14",
+			Category:    "Electronics",
+			Description: "iPhone 14 em excelente estado",
+			Condition:   auction_entity.Used,
+			Status:      auction_entity.Completed,
+		},
+	}}
+	router := newTestRouter(repository)
+
+	t.Run("patching a single field keeps the others", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPatch, "/auction/"+activeId,
+			strings.NewReader(`{"description": "iPhone 15 Pro com caixa e nota fiscal"}`))
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var output auction_usecase.AuctionOutputDTO
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &output))
+		assert.Equal(t, "iPhone 15 Pro com caixa e nota fiscal", output.Description)
+		assert.Equal(t, "iPhone 15 Pro", output.ProductName)
+		assert.Equal(t, "Electronics", output.Category)
+		assert.Equal(t, auction_usecase.ProductCondition(auction_entity.Used), output.Condition)
+
+		stored := repository.auctions[activeId]
+		assert.Equal(t, "iPhone 15 Pro com caixa e nota fiscal", stored.Description)
+		assert.Equal(t, "iPhone 15 Pro", stored.ProductName)
+		assert.Equal(t, "Electronics", stored.Category)
+		assert.Equal(t, auction_entity.Used, stored.Condition)
+	})
+
+	t.Run("missing auction returns 404", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPatch, "/auction/"+uuid.New().String(),
+			strings.NewReader(`{"product_name": "Novo nome"}`))
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("completed auction returns 409", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPatch, "/auction/"+completedId,
+			strings.NewReader(`{"product_name": "Novo nome"}`))
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, "iPhone 14", repository.auctions[completedId].ProductName)
+	})
+
+	t.Run("stale version returns 409", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPatch, "/auction/"+activeId,
+			strings.NewReader(`{"product_name": "Novo nome", "version": 0}`))
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, "iPhone 15 Pro", repository.auctions[activeId].ProductName)
+	})
+}
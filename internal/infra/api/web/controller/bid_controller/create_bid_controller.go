@@ -1,7 +1,6 @@
 package bid_controller
 
 import (
-	"context"
 	"github.com/danielencestari/lab03/configuration/rest_err"
 	"github.com/danielencestari/lab03/internal/infra/api/web/validation"
 	"github.com/danielencestari/lab03/internal/usecase/bid_usecase"
@@ -29,7 +28,7 @@ func (u *BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
+	err := u.bidUseCase.CreateBid(c.Request.Context(), bidInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
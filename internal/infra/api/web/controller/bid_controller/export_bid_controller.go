@@ -0,0 +1,119 @@
+package bid_controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/usecase/bid_usecase"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// bidExportHeader is the first row of the CSV written by ExportBidsByUser.
+var bidExportHeader = []string{"bid_id", "auction_id", "amount", "timestamp"}
+
+// ExportBidsByUser writes the bids of a user as CSV, newest first. With a
+// page param only that page is exported; otherwise every bid is, streamed
+// pagination.MaxPageSize at a time as each batch is read.
+func (u *BidController) ExportBidsByUser(c *gin.Context) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	page, errConv := pagination.ParsePageParam(c.Query("page"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate page param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	pageSize, errConv := pagination.ParsePageParam(c.Query("pageSize"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate pageSize param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+	if pageSize > 0 && page == 0 {
+		errRest := rest_err.NewBadRequestError("pageSize requires a page param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if page > 0 {
+		bids, err := u.bidUseCase.FindBidsByUser(c.Request.Context(), userId, page, pageSize)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+
+		writer := startBidExport(c, userId)
+		writeBidRows(writer, bids)
+		writer.Flush()
+		return
+	}
+
+	// Errors on the first batch can still be answered as JSON
+	bids, err := u.bidUseCase.FindBidsByUserAfter(
+		c.Request.Context(), userId, time.Time{}, "", pagination.MaxPageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	writer := startBidExport(c, userId)
+	for {
+		writeBidRows(writer, bids)
+		writer.Flush()
+		if int64(len(bids)) < pagination.MaxPageSize {
+			return
+		}
+
+		last := bids[len(bids)-1]
+		bids, err = u.bidUseCase.FindBidsByUserAfter(
+			c.Request.Context(), userId, last.Timestamp, last.Id, pagination.MaxPageSize)
+		if err != nil {
+			// The status is already sent, so the export just ends early
+			logger.Error("Error trying to export bids, the CSV is incomplete", err,
+				zap.String("user_id", userId))
+			return
+		}
+	}
+}
+
+// startBidExport sends the CSV headers and returns a writer that already
+// holds the header row.
+func startBidExport(c *gin.Context, userId string) *csv.Writer {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"bids-%s.csv\"", userId))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write(bidExportHeader)
+	return writer
+}
+
+func writeBidRows(writer *csv.Writer, bids []bid_usecase.BidOutputDTO) {
+	for _, bid := range bids {
+		_ = writer.Write([]string{
+			bid.Id,
+			bid.AuctionId,
+			bid.Amount.String(),
+			bid.Timestamp.UTC().Format(time.RFC3339),
+		})
+	}
+}
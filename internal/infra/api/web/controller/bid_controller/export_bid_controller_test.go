@@ -0,0 +1,174 @@
+package bid_controller
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/bid_usecase"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type bidsByUserUseCaseMock struct {
+	bid_usecase.BidUseCaseInterface
+
+	bids    []bid_usecase.BidOutputDTO
+	pages   []int64
+	batches int
+	failAt  int
+}
+
+func (m *bidsByUserUseCaseMock) FindBidsByUser(
+	ctx context.Context,
+	userId string,
+	page, pageSize int64) ([]bid_usecase.BidOutputDTO, *internal_error.InternalError) {
+	page, pageSize, err := pagination.Normalize(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	m.pages = append(m.pages, page)
+
+	total := int64(len(m.bids))
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return m.bids[start:end], nil
+}
+
+func (m *bidsByUserUseCaseMock) FindBidsByUserAfter(
+	ctx context.Context,
+	userId string,
+	afterTimestamp time.Time,
+	afterId string,
+	limit int64) ([]bid_usecase.BidOutputDTO, *internal_error.InternalError) {
+	m.batches++
+	if m.batches == m.failAt {
+		return nil, internal_error.NewInternalServerError("database is down")
+	}
+
+	start := 0
+	if !afterTimestamp.IsZero() {
+		for start < len(m.bids) && !m.bids[start].Timestamp.Before(afterTimestamp) {
+			start++
+		}
+	}
+	end := start + int(limit)
+	if end > len(m.bids) {
+		end = len(m.bids)
+	}
+	return m.bids[start:end], nil
+}
+
+func TestExportBidsByUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userId := uuid.New().String()
+	newBids := func(n int) []bid_usecase.BidOutputDTO {
+		start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+		bids := make([]bid_usecase.BidOutputDTO, 0, n)
+		for i := 0; i < n; i++ {
+			bids = append(bids, bid_usecase.BidOutputDTO{
+				Id:        uuid.New().String(),
+				UserId:    userId,
+				AuctionId: uuid.New().String(),
+				Amount:    bid_entity.Cents(1050 + i),
+				Timestamp: start.Add(-time.Duration(i) * time.Minute),
+			})
+		}
+		return bids
+	}
+
+	export := func(useCase *bidsByUserUseCaseMock, path string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/user/:userId/bids/export", NewBidController(useCase).ExportBidsByUser)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	readRows := func(recorder *httptest.ResponseRecorder) [][]string {
+		rows, err := csv.NewReader(strings.NewReader(recorder.Body.String())).ReadAll()
+		assert.Nil(t, err)
+		return rows
+	}
+
+	t.Run("writes the header and one row per bid", func(t *testing.T) {
+		useCase := &bidsByUserUseCaseMock{bids: newBids(2)}
+
+		recorder := export(useCase, "/user/"+userId+"/bids/export")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Header().Get("Content-Disposition"), "bids-"+userId+".csv")
+
+		rows := readRows(recorder)
+		assert.Equal(t, [][]string{
+			{"bid_id", "auction_id", "amount", "timestamp"},
+			{useCase.bids[0].Id, useCase.bids[0].AuctionId, "10.50", "2024-05-01T12:00:00Z"},
+			{useCase.bids[1].Id, useCase.bids[1].AuctionId, "10.51", "2024-05-01T11:59:00Z"},
+		}, rows)
+	})
+
+	t.Run("writes only the header when the user has no bids", func(t *testing.T) {
+		recorder := export(&bidsByUserUseCaseMock{}, "/user/"+userId+"/bids/export")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, [][]string{{"bid_id", "auction_id", "amount", "timestamp"}}, readRows(recorder))
+	})
+
+	t.Run("streams every bid without a page param", func(t *testing.T) {
+		useCase := &bidsByUserUseCaseMock{bids: newBids(int(pagination.MaxPageSize) + 5)}
+
+		rows := readRows(export(useCase, "/user/"+userId+"/bids/export"))
+		assert.Len(t, rows, int(pagination.MaxPageSize)+5+1)
+		assert.Equal(t, 2, useCase.batches)
+		assert.Empty(t, useCase.pages)
+		assert.Equal(t, useCase.bids[len(useCase.bids)-1].Id, rows[len(rows)-1][0])
+	})
+
+	t.Run("answers an error on the first batch as JSON", func(t *testing.T) {
+		recorder := export(&bidsByUserUseCaseMock{failAt: 1}, "/user/"+userId+"/bids/export")
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.Run("keeps the batches already streamed when a later one fails", func(t *testing.T) {
+		useCase := &bidsByUserUseCaseMock{bids: newBids(int(pagination.MaxPageSize) + 5), failAt: 2}
+
+		recorder := export(useCase, "/user/"+userId+"/bids/export")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, readRows(recorder), int(pagination.MaxPageSize)+1)
+	})
+
+	t.Run("exports only the requested page", func(t *testing.T) {
+		useCase := &bidsByUserUseCaseMock{bids: newBids(5)}
+
+		rows := readRows(export(useCase, "/user/"+userId+"/bids/export?page=2&pageSize=2"))
+		assert.Equal(t, []int64{2}, useCase.pages)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, useCase.bids[2].Id, rows[1][0])
+		assert.Equal(t, useCase.bids[3].Id, rows[2][0])
+	})
+
+	t.Run("rejects invalid params", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, export(&bidsByUserUseCaseMock{}, "/user/not-a-uuid/bids/export").Code)
+		assert.Equal(t, http.StatusBadRequest, export(&bidsByUserUseCaseMock{}, "/user/"+userId+"/bids/export?page=0").Code)
+		assert.Equal(t, http.StatusBadRequest,
+			export(&bidsByUserUseCaseMock{}, "/user/"+userId+"/bids/export?page=1&pageSize=500").Code)
+		assert.Equal(t, http.StatusBadRequest,
+			export(&bidsByUserUseCaseMock{}, "/user/"+userId+"/bids/export?pageSize=10").Code)
+	})
+}
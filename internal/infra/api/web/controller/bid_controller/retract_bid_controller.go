@@ -0,0 +1,30 @@
+package bid_controller
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (u *BidController) RetractBid(c *gin.Context) {
+	bidId := c.Param("bidId")
+
+	if err := uuid.Validate(bidId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "bidId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	if err := u.bidUseCase.RetractBid(c.Request.Context(), bidId); err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,52 @@
+package config_controller
+
+import (
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"os"
+)
+
+const redactedValue = "[REDACTED]"
+
+type RuntimeConfigProviderInterface interface {
+	RuntimeConfig() auction.RuntimeConfig
+}
+
+type ConfigController struct {
+	configProvider RuntimeConfigProviderInterface
+}
+
+type ConfigOutputDTO struct {
+	auction.RuntimeConfig
+	MongoDBURL string `json:"mongodb_url"`
+	MongoDBDB  string `json:"mongodb_db"`
+	AdminToken string `json:"admin_token"`
+}
+
+func NewConfigController(configProvider RuntimeConfigProviderInterface) *ConfigController {
+	return &ConfigController{
+		configProvider: configProvider,
+	}
+}
+
+// GetConfig reports the settings the process actually parsed. Values that
+// may carry credentials only tell whether they are set.
+func (cc *ConfigController) GetConfig(c *gin.Context) {
+	config := cc.configProvider.RuntimeConfig()
+	config.CloseWebhookURL = redact(config.CloseWebhookURL)
+
+	c.JSON(http.StatusOK, ConfigOutputDTO{
+		RuntimeConfig: config,
+		MongoDBURL:    redact(os.Getenv("MONGODB_URL")),
+		MongoDBDB:     os.Getenv("MONGODB_DB"),
+		AdminToken:    redact(os.Getenv("ADMIN_TOKEN")),
+	})
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
@@ -0,0 +1,58 @@
+package health_controller
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+const readinessPingTimeout = 2 * time.Second
+
+type ReadinessCheckerInterface interface {
+	Ping(ctx context.Context) *internal_error.InternalError
+	RecoveryDone() bool
+}
+
+type HealthController struct {
+	readinessChecker ReadinessCheckerInterface
+}
+
+type HealthOutputDTO struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func NewHealthController(readinessChecker ReadinessCheckerInterface) *HealthController {
+	return &HealthController{
+		readinessChecker: readinessChecker,
+	}
+}
+
+func (h *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthOutputDTO{Status: "ok"})
+}
+
+func (h *HealthController) Readiness(c *gin.Context) {
+	if !h.readinessChecker.RecoveryDone() {
+		c.JSON(http.StatusServiceUnavailable, HealthOutputDTO{
+			Status: "not_ready",
+			Reason: "auction recovery in progress",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessPingTimeout)
+	defer cancel()
+
+	if err := h.readinessChecker.Ping(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, HealthOutputDTO{
+			Status: "not_ready",
+			Reason: "database unavailable",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, HealthOutputDTO{Status: "ready"})
+}
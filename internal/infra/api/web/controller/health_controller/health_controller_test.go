@@ -0,0 +1,84 @@
+package health_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type readinessCheckerMock struct {
+	pingErr      *internal_error.InternalError
+	recoveryDone bool
+}
+
+func (m *readinessCheckerMock) Ping(ctx context.Context) *internal_error.InternalError {
+	return m.pingErr
+}
+
+func (m *readinessCheckerMock) RecoveryDone() bool {
+	return m.recoveryDone
+}
+
+func newTestRouter(readinessChecker ReadinessCheckerInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewHealthController(readinessChecker)
+
+	router := gin.New()
+	router.GET("/healthz", controller.Liveness)
+	router.GET("/readyz", controller.Readiness)
+
+	return router
+}
+
+func performRequest(router *gin.Engine, path string) (*httptest.ResponseRecorder, HealthOutputDTO) {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var output HealthOutputDTO
+	json.Unmarshal(recorder.Body.Bytes(), &output)
+
+	return recorder, output
+}
+
+func TestLivenessAlwaysOk(t *testing.T) {
+	router := newTestRouter(&readinessCheckerMock{recoveryDone: false})
+
+	recorder, output := performRequest(router, "/healthz")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ok", output.Status)
+}
+
+func TestReadinessAfterRecovery(t *testing.T) {
+	router := newTestRouter(&readinessCheckerMock{recoveryDone: true})
+
+	recorder, output := performRequest(router, "/readyz")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ready", output.Status)
+}
+
+func TestReadinessDuringRecovery(t *testing.T) {
+	router := newTestRouter(&readinessCheckerMock{recoveryDone: false})
+
+	recorder, output := performRequest(router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "not_ready", output.Status)
+	assert.Equal(t, "auction recovery in progress", output.Reason)
+}
+
+func TestReadinessWithDatabaseDown(t *testing.T) {
+	router := newTestRouter(&readinessCheckerMock{
+		recoveryDone: true,
+		pingErr:      internal_error.NewInternalServerError("ping failed"),
+	})
+
+	recorder, output := performRequest(router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "database unavailable", output.Reason)
+}
@@ -0,0 +1,23 @@
+package metrics_controller
+
+import (
+	"github.com/danielencestari/lab03/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type MetricsController struct {
+	registry *metrics.Registry
+}
+
+func NewMetricsController(registry *metrics.Registry) *MetricsController {
+	return &MetricsController{
+		registry: registry,
+	}
+}
+
+// GetMetrics reports every registered metric. Metrics that were never
+// recorded, e.g. because they are disabled, are simply absent.
+func (mc *MetricsController) GetMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, mc.registry.Snapshot())
+}
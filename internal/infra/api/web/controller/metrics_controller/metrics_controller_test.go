@@ -0,0 +1,37 @@
+package metrics_controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	for i := 1; i <= 10; i++ {
+		registry.Histogram("find_auction_by_id").Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/debug/metrics", NewMetricsController(registry).GetMetrics)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var snapshot metrics.Snapshot
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &snapshot))
+
+	lookup := snapshot.Histograms["find_auction_by_id"]
+	assert.Equal(t, int64(10), lookup.Count)
+	assert.Equal(t, 5.0, lookup.P50)
+	assert.Equal(t, 10.0, lookup.P95)
+	assert.Equal(t, 10.0, lookup.P99)
+}
@@ -1,8 +1,8 @@
 package user_controller
 
 import (
-	"context"
 	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
 	"github.com/danielencestari/lab03/internal/usecase/user_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -32,7 +32,7 @@ func (u *UserController) FindUserById(c *gin.Context) {
 		return
 	}
 
-	userData, err := u.userUseCase.FindUserById(context.Background(), userId)
+	userData, err := u.userUseCase.FindUserById(c.Request.Context(), userId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -41,3 +41,28 @@ func (u *UserController) FindUserById(c *gin.Context) {
 
 	c.JSON(http.StatusOK, userData)
 }
+
+func (u *UserController) FindUsers(c *gin.Context) {
+	page, errConv := pagination.ParsePageParam(c.Query("page"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate page param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	pageSize, errConv := pagination.ParsePageParam(c.Query("pageSize"))
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate pageSize param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	users, err := u.userUseCase.FindUsers(c.Request.Context(), page, pageSize)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
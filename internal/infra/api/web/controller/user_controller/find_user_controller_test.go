@@ -0,0 +1,88 @@
+package user_controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
+	"github.com/danielencestari/lab03/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type userListRepositoryMock struct {
+	user_entity.UserRepositoryInterface
+
+	users []user_entity.User
+}
+
+func (m *userListRepositoryMock) FindUsers(
+	ctx context.Context, page, pageSize int64) ([]user_entity.User, int64, *internal_error.InternalError) {
+	total := int64(len(m.users))
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return m.users[start:end], total, nil
+}
+
+func TestFindUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	find := func(users []user_entity.User, query string) (int, pagination.PageResponse[user_usecase.UserOutputDTO]) {
+		controller := NewUserController(user_usecase.NewUserUseCase(&userListRepositoryMock{users: users}))
+		router := gin.New()
+		router.GET("/user", controller.FindUsers)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/user?"+query, nil))
+
+		var page pagination.PageResponse[user_usecase.UserOutputDTO]
+		if recorder.Code == http.StatusOK {
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+		}
+		return recorder.Code, page
+	}
+
+	t.Run("partial last page", func(t *testing.T) {
+		users := []user_entity.User{
+			{Id: "1", Name: "Ana"}, {Id: "2", Name: "Bruno"}, {Id: "3", Name: "Carla"},
+			{Id: "4", Name: "Diego"}, {Id: "5", Name: "Elisa"},
+		}
+
+		code, page := find(users, "page=2&pageSize=3")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []user_usecase.UserOutputDTO{{Id: "4", Name: "Diego"}, {Id: "5", Name: "Elisa"}}, page.Data)
+		assert.Equal(t, int64(2), page.Page)
+		assert.Equal(t, int64(3), page.PageSize)
+		assert.Equal(t, int64(5), page.Total)
+		assert.Equal(t, int64(2), page.TotalPages)
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		code, page := find(nil, "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.NotNil(t, page.Data)
+		assert.Empty(t, page.Data)
+		assert.Equal(t, int64(1), page.Page)
+		assert.Equal(t, pagination.DefaultPageSize, page.PageSize)
+		assert.Equal(t, int64(0), page.Total)
+		assert.Equal(t, int64(0), page.TotalPages)
+	})
+
+	for _, query := range []string{"page=0", "pageSize=abc", "pageSize=1000"} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			code, _ := find(nil, query)
+			assert.Equal(t, http.StatusBadRequest, code)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// CorrelationIdHeader carries the id that ties a request to its logs; a
+	// client-supplied value is kept, otherwise one is generated.
+	CorrelationIdHeader = "X-Request-Id"
+
+	// CorrelationIdKey holds the correlation id in the gin context.
+	CorrelationIdKey = "correlation_id"
+)
+
+// logRequest is replaced in tests to capture the logged lines
+var logRequest = logger.Info
+
+// quietPaths are the probes polled often enough to drown the log
+var quietPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// RequestLogger logs the method, path, status, latency and correlation id
+// of every request once it has been handled, except the health checks. The
+// correlation id is echoed back in the X-Request-Id response header.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correlationId := c.GetHeader(CorrelationIdHeader)
+		if correlationId == "" {
+			correlationId = uuid.New().String()
+		}
+		c.Set(CorrelationIdKey, correlationId)
+		c.Header(CorrelationIdHeader, correlationId)
+
+		start := time.Now()
+		c.Next()
+
+		path := c.Request.URL.Path
+		if quietPaths[path] {
+			return
+		}
+
+		logRequest("HTTP request",
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("correlation_id", correlationId))
+	}
+}
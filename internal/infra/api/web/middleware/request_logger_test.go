@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// captureRequestLogs collects the fields of every logged request until the
+// test ends.
+func captureRequestLogs(t *testing.T) *[]map[string]interface{} {
+	var lines []map[string]interface{}
+	previous := logRequest
+	logRequest = func(message string, tags ...zap.Field) {
+		encoder := zapcore.NewMapObjectEncoder()
+		for _, tag := range tags {
+			tag.AddTo(encoder)
+		}
+		lines = append(lines, encoder.Fields)
+	}
+	t.Cleanup(func() { logRequest = previous })
+
+	return &lines
+}
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	lines := captureRequestLogs(t)
+
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/auction", func(c *gin.Context) {
+		time.Sleep(time.Millisecond)
+		c.Status(http.StatusCreated)
+	})
+
+	t.Run("logs the request", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/auction", nil)
+		request.Header.Set(CorrelationIdHeader, "test-correlation-id")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, "test-correlation-id", recorder.Header().Get(CorrelationIdHeader))
+		if assert.Len(t, *lines, 1) {
+			line := (*lines)[0]
+			assert.Equal(t, http.MethodPost, line["method"])
+			assert.Equal(t, "/auction", line["path"])
+			assert.Equal(t, int64(http.StatusCreated), line["status"])
+			assert.Equal(t, "test-correlation-id", line["correlation_id"])
+			assert.Greater(t, line["latency"], time.Duration(0))
+		}
+	})
+
+	t.Run("generates a correlation id", func(t *testing.T) {
+		*lines = nil
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/auction", nil))
+
+		correlationId := recorder.Header().Get(CorrelationIdHeader)
+		assert.NotEmpty(t, correlationId)
+		if assert.Len(t, *lines, 1) {
+			assert.Equal(t, correlationId, (*lines)[0]["correlation_id"])
+		}
+	})
+
+	t.Run("skips health checks", func(t *testing.T) {
+		*lines = nil
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, *lines)
+	})
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultRequestTimeout = 10 * time.Second
+
+// RequestTimeout bounds every request with a deadline derived from the
+// request context, which handlers pass down to the use cases. When the
+// deadline is exceeded the handler's own response is discarded and the
+// client gets a 504 instead.
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		writer := &deadlineWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if writer.ResponseWriter.Written() || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+
+		logger.Error("Request deadline exceeded", ctx.Err())
+		restErr := rest_err.NewGatewayTimeoutError("Request deadline exceeded")
+		c.AbortWithStatusJSON(restErr.Code, restErr)
+	}
+}
+
+// GetRequestTimeout reads HTTP_REQUEST_TIMEOUT, defaulting to 10 seconds.
+func GetRequestTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("HTTP_REQUEST_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		return defaultRequestTimeout
+	}
+
+	return timeout
+}
+
+// deadlineWriter drops whatever the handler writes after the deadline, so
+// the middleware can still answer with a 504.
+type deadlineWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+func (w *deadlineWriter) WriteHeader(code int) {
+	if w.ctx.Err() == nil {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *deadlineWriter) WriteHeaderNow() {
+	if w.ctx.Err() == nil {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *deadlineWriter) Write(data []byte) (int, error) {
+	if w.ctx.Err() != nil {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *deadlineWriter) WriteString(s string) (int, error) {
+	if w.ctx.Err() != nil {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTimeoutRouter(timeout time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestTimeout(timeout))
+	router.GET("/slow", handler)
+	return router
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Run("slow handlers answer 504", func(t *testing.T) {
+		// O caso de uso lento só retorna quando o contexto expira
+		router := newTimeoutRouter(20*time.Millisecond, func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			restErr := rest_err.NewInternalServerError("Error trying to find auction by id")
+			c.JSON(restErr.Code, restErr)
+		})
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+		var restErr rest_err.RestErr
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &restErr))
+		assert.Equal(t, "gateway_timeout", restErr.Err)
+		assert.Equal(t, http.StatusGatewayTimeout, restErr.Code)
+	})
+
+	t.Run("fast handlers are untouched", func(t *testing.T) {
+		router := newTimeoutRouter(time.Second, func(c *gin.Context) {
+			_, hasDeadline := c.Request.Context().Deadline()
+			c.JSON(http.StatusOK, gin.H{"deadline": hasDeadline})
+		})
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"deadline": true}`, recorder.Body.String())
+	})
+}
+
+func TestGetRequestTimeout(t *testing.T) {
+	os.Unsetenv("HTTP_REQUEST_TIMEOUT")
+	assert.Equal(t, 10*time.Second, GetRequestTimeout())
+
+	os.Setenv("HTTP_REQUEST_TIMEOUT", "250ms")
+	defer os.Unsetenv("HTTP_REQUEST_TIMEOUT")
+	assert.Equal(t, 250*time.Millisecond, GetRequestTimeout())
+}
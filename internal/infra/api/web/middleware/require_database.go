@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/danielencestari/lab03/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+)
+
+// DatabaseAvailability reports whether the database can take requests, as
+// mongodb.ConnectionMonitor does.
+type DatabaseAvailability interface {
+	Available() bool
+}
+
+// RequireDatabase answers 503 while the database is unavailable instead of
+// letting the request fail deep in a repository. Routes registered before
+// it, like the health checks, are not affected.
+func RequireDatabase(database DatabaseAvailability) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !database.Available() {
+			restErr := rest_err.NewServiceUnavailableError("Database temporarily unavailable, try again later")
+			c.AbortWithStatusJSON(restErr.Code, restErr)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type databaseAvailabilityMock struct {
+	available atomic.Bool
+}
+
+func (m *databaseAvailabilityMock) Available() bool {
+	return m.available.Load()
+}
+
+func TestRequireDatabase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	database := &databaseAvailabilityMock{}
+
+	router := gin.New()
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.Use(RequireDatabase(database))
+	router.GET("/auction", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	recorder := serve("/auction")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "temporarily unavailable")
+	assert.Equal(t, http.StatusOK, serve("/healthz").Code)
+
+	database.available.Store(true)
+	assert.Equal(t, http.StatusOK, serve("/auction").Code)
+}
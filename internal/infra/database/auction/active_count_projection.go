@@ -0,0 +1,249 @@
+package auction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type auctionEventKind int
+
+const (
+	auctionOpened auctionEventKind = iota
+	auctionClosed
+)
+
+// auctionEvent is an auction entering or leaving the Active status.
+// Opened events carry the category when the opener knows it, so the
+// per-category limits can be derived too.
+type auctionEvent struct {
+	kind      auctionEventKind
+	auctionId string
+	category  string
+}
+
+// activeCountProjection derives the number of active auctions, overall
+// and per category, from the stream of auction events rather than from
+// counters adjusted by hand. It keeps the active ids, so a duplicated or
+// replayed event cannot make it drift, and it can be rebuilt from the
+// database at any time. The concurrency limits are checked against it.
+type activeCountProjection struct {
+	mutex      sync.Mutex
+	active     map[string]string // auction id to normalized category
+	byCategory map[string]int64
+
+	// rebuilding buffers the events seen while a rebuild reads the
+	// database, to be replayed over the snapshot
+	rebuilding bool
+	pending    []auctionEvent
+}
+
+func newActiveCountProjection() *activeCountProjection {
+	return &activeCountProjection{
+		active:     make(map[string]string),
+		byCategory: make(map[string]int64),
+	}
+}
+
+func (p *activeCountProjection) apply(events ...auctionEvent) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.rebuilding {
+		p.pending = append(p.pending, events...)
+	}
+	p.applyLocked(events)
+}
+
+func (p *activeCountProjection) applyLocked(events []auctionEvent) {
+	for _, event := range events {
+		switch event.kind {
+		case auctionOpened:
+			category, ok := p.active[event.auctionId]
+			if ok && (category != "" || event.category == "") {
+				continue
+			}
+			p.removeLocked(event.auctionId)
+			p.addLocked(event.auctionId, auction_entity.NormalizeCategory(event.category))
+		case auctionClosed:
+			p.removeLocked(event.auctionId)
+		}
+	}
+}
+
+func (p *activeCountProjection) addLocked(auctionId, category string) {
+	p.active[auctionId] = category
+	p.byCategory[category]++
+}
+
+func (p *activeCountProjection) removeLocked(auctionId string) {
+	category, ok := p.active[auctionId]
+	if !ok {
+		return
+	}
+
+	delete(p.active, auctionId)
+	if p.byCategory[category]--; p.byCategory[category] <= 0 {
+		delete(p.byCategory, category)
+	}
+}
+
+func (p *activeCountProjection) count() int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return int64(len(p.active))
+}
+
+func (p *activeCountProjection) isActive(auctionId string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	_, ok := p.active[auctionId]
+	return ok
+}
+
+// countCategory is the number of active auctions of category.
+func (p *activeCountProjection) countCategory(category string) int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.byCategory[auction_entity.NormalizeCategory(category)]
+}
+
+// beginRebuild starts buffering events; it must be called before the
+// database is read.
+func (p *activeCountProjection) beginRebuild() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.rebuilding = true
+	p.pending = nil
+}
+
+// finishRebuild replaces the state with the active auctions read from the
+// database, ids to categories, and replays the events received meanwhile.
+// A nil snapshot means the read failed and only stops the buffering.
+func (p *activeCountProjection) finishRebuild(active map[string]string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if active != nil {
+		p.active = make(map[string]string, len(active))
+		p.byCategory = make(map[string]int64)
+		for auctionId, category := range active {
+			p.addLocked(auctionId, auction_entity.NormalizeCategory(category))
+		}
+		p.applyLocked(p.pending)
+	}
+
+	p.rebuilding = false
+	p.pending = nil
+}
+
+// publishStatusChange feeds the projection with the auctions that entered
+// or left the Active status.
+func (ar *AuctionRepository) publishStatusChange(
+	from, to auction_entity.AuctionStatus, auctionIds ...string) {
+	var kind auctionEventKind
+	switch {
+	case from != auction_entity.Active && to == auction_entity.Active:
+		kind = auctionOpened
+	case from == auction_entity.Active && to != auction_entity.Active:
+		kind = auctionClosed
+	default:
+		return
+	}
+
+	events := make([]auctionEvent, 0, len(auctionIds))
+	for _, auctionId := range auctionIds {
+		events = append(events, auctionEvent{kind: kind, auctionId: auctionId})
+	}
+	ar.activeProjection.apply(events...)
+}
+
+// RebuildActiveCount rebuilds the active count projection from the
+// auctions stored as Active and returns the new count. Slots whose write
+// is still in flight are kept; monitored auctions no longer active, e.g.
+// closed by another instance, lose their slot and monitor.
+func (ar *AuctionRepository) RebuildActiveCount(ctx context.Context) (int64, *internal_error.InternalError) {
+	ar.activeProjection.beginRebuild()
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"status": auction_entity.Active},
+		options.Find().SetProjection(bson.M{"_id": 1, "category": 1}))
+	if err != nil {
+		ar.activeProjection.finishRebuild(nil)
+		logger.Error("Error trying to find active auctions to rebuild the active count", err)
+		return 0, internal_error.NewInternalServerError("Error trying to rebuild the active count")
+	}
+
+	var activeAuctions []struct {
+		Id       string `bson:"_id"`
+		Category string `bson:"category"`
+	}
+	if err := cursor.All(ctx, &activeAuctions); err != nil {
+		ar.activeProjection.finishRebuild(nil)
+		logger.Error("Error trying to decode active auctions to rebuild the active count", err)
+		return 0, internal_error.NewInternalServerError("Error trying to rebuild the active count")
+	}
+
+	active := make(map[string]string, len(activeAuctions))
+	for _, auction := range activeAuctions {
+		active[auction.Id] = auction.Category
+	}
+
+	ar.auctionCountMutex.Lock()
+	for auctionId := range ar.reservedSlots {
+		active[auctionId] = ar.monitoredAuctions[auctionId]
+	}
+	ar.activeProjection.finishRebuild(active)
+
+	var freed int
+	for auctionId := range ar.monitoredAuctions {
+		if !ar.activeProjection.isActive(auctionId) {
+			ar.monitors.Cancel(auctionId)
+			delete(ar.monitoredAuctions, auctionId)
+			freed++
+		}
+	}
+	ar.auctionCountMutex.Unlock()
+
+	count := ar.activeProjection.count()
+	logger.Info("Active count projection rebuilt", zap.Int64("active", count), zap.Int("freed_slots", freed))
+	return count, nil
+}
+
+// runActiveResync rebuilds the projection every monitor lease until the
+// repository is closed, so the slots of auctions closed by other
+// instances are freed here too and their close callbacks run.
+func (ar *AuctionRepository) runActiveResync() {
+	since := ar.clock.Now()
+	for {
+		timer := ar.clock.NewTimer(ar.monitorLease)
+		select {
+		case <-timer.C():
+		case <-ar.ctx.Done():
+			stopTimer(timer)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ar.ctx, ensureIndexesTimeout)
+		// Errors are already logged; the next run simply tries again
+		ar.RebuildActiveCount(ctx)
+		// closed_at has second precision, so the window overlaps the
+		// previous one by up to a second; fired runs are skipped
+		next := ar.clock.Now()
+		ar.runClosedElsewhereCallbacks(ctx, since)
+		ar.closeCallbacks.pruneFired(since.Add(-time.Second))
+		since = next
+		cancel()
+	}
+}
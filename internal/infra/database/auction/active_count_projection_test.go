@@ -0,0 +1,116 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveCountProjection(t *testing.T) {
+	projection := newActiveCountProjection()
+
+	projection.apply(
+		auctionEvent{kind: auctionOpened, auctionId: "a"},
+		auctionEvent{kind: auctionOpened, auctionId: "b"},
+		auctionEvent{kind: auctionOpened, auctionId: "c"},
+	)
+	assert.Equal(t, int64(3), projection.count())
+
+	projection.apply(
+		auctionEvent{kind: auctionClosed, auctionId: "a"},
+		// Duplicated and unknown events leave the count alone
+		auctionEvent{kind: auctionClosed, auctionId: "a"},
+		auctionEvent{kind: auctionOpened, auctionId: "b"},
+		auctionEvent{kind: auctionClosed, auctionId: "unknown"},
+	)
+	assert.Equal(t, int64(2), projection.count())
+
+	projection.apply(auctionEvent{kind: auctionOpened, auctionId: "a"})
+	projection.apply(auctionEvent{kind: auctionClosed, auctionId: "c"})
+	assert.Equal(t, int64(2), projection.count())
+}
+
+func TestActiveCountProjectionByCategory(t *testing.T) {
+	projection := newActiveCountProjection()
+
+	projection.apply(
+		auctionEvent{kind: auctionOpened, auctionId: "a", category: "Electronics"},
+		auctionEvent{kind: auctionOpened, auctionId: "b", category: "electronics"},
+		auctionEvent{kind: auctionOpened, auctionId: "c", category: "Art"},
+		// Um evento sem categoria não apaga a já conhecida
+		auctionEvent{kind: auctionOpened, auctionId: "a"},
+	)
+	assert.Equal(t, int64(2), projection.countCategory("ELECTRONICS"))
+	assert.Equal(t, int64(1), projection.countCategory("Art"))
+
+	projection.apply(auctionEvent{kind: auctionClosed, auctionId: "b"})
+	assert.Equal(t, int64(1), projection.countCategory("Electronics"))
+	assert.Equal(t, int64(0), projection.countCategory("Books"))
+	assert.Equal(t, int64(2), projection.count())
+}
+
+func TestActiveCountProjectionRebuildReplaysEvents(t *testing.T) {
+	projection := newActiveCountProjection()
+	projection.apply(auctionEvent{kind: auctionOpened, auctionId: "stale"})
+
+	// Events arriving while the database is read are not lost
+	projection.beginRebuild()
+	projection.apply(
+		auctionEvent{kind: auctionOpened, auctionId: "created-meanwhile"},
+		auctionEvent{kind: auctionClosed, auctionId: "closed-meanwhile"},
+	)
+	projection.finishRebuild(map[string]string{"stored": "Art", "closed-meanwhile": "Art"})
+	assert.Equal(t, int64(2), projection.count())
+
+	// A failed read keeps the current state
+	projection.beginRebuild()
+	projection.finishRebuild(nil)
+	assert.Equal(t, int64(2), projection.count())
+}
+
+func TestRebuildActiveCount(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	_, err := db.Collection("auctions").InsertOne(ctx, AuctionEntityMongo{
+		Id: "recovered", ProductName: "Test Product", Category: "Electronics",
+		Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()})
+	assert.Nil(t, err)
+
+	repo := NewAuctionRepository(db, WithConfig(Config{AuctionInterval: time.Hour}))
+	defer repo.Close()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+	assert.Equal(t, int64(1), repo.Stats().Active)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		ids = append(ids, auction.Id)
+	}
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, ids[0], auction_entity.Completed))
+	assert.Equal(t, int64(3), repo.Stats().Active)
+
+	// A write behind the repository's back is only seen after a rebuild
+	_, err = db.Collection("auctions").InsertOne(ctx, AuctionEntityMongo{
+		Id: "external", ProductName: "Test Product", Category: "Electronics",
+		Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), repo.Stats().Active)
+
+	count, rebuildErr := repo.RebuildActiveCount(ctx)
+	assert.Nil(t, rebuildErr)
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, int64(4), repo.Stats().Active)
+}
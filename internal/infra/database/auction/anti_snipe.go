@@ -0,0 +1,116 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// ExtendForLateBid pushes the end of an active auction forward when a bid
+// lands within ANTI_SNIPE_WINDOW of its end_time, and reschedules the
+// monitor accordingly. It reports the (possibly unchanged) end time and
+// whether an extension happened. Disabled unless ANTI_SNIPE_ENABLED is true,
+// in which case the zero time is returned.
+func (ar *AuctionRepository) ExtendForLateBid(
+	ctx context.Context,
+	auctionId string,
+	bidTime time.Time) (time.Time, bool, *internal_error.InternalError) {
+	if !ar.isAntiSnipeEnabled() {
+		return time.Time{}, false, nil
+	}
+
+	auction, err := ar.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if auction.Status != auction_entity.Active {
+		return auction.EndTime, false, nil
+	}
+
+	remaining := auction.EndTime.Sub(bidTime)
+	if remaining < 0 || remaining > ar.getAntiSnipeWindow() {
+		return auction.EndTime, false, nil
+	}
+
+	newEndTime := auction.EndTime.Add(ar.getAntiSnipeExtension())
+
+	// Only extend if the end time was not moved by another bid meanwhile
+	filter := bson.M{
+		"_id":      auctionId,
+		"status":   auction_entity.Active,
+		"end_time": auction.EndTime.Unix(),
+	}
+	update := bson.M{
+		"$set": bson.M{"end_time": newEndTime.Unix()},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, updateErr := ar.Collection.UpdateOne(ctx, filter, update)
+	if updateErr != nil {
+		logger.Error(fmt.Sprintf("Error trying to extend auction with id = %s", auctionId), updateErr)
+		return time.Time{}, false, internal_error.NewInternalServerError("Error trying to extend auction")
+	}
+	ar.invalidateCachedAuction(auctionId)
+
+	if result.MatchedCount == 0 {
+		// Someone else closed or extended it first; report the current state
+		current, err := ar.FindAuctionById(ctx, auctionId)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return current.EndTime, false, nil
+	}
+
+	ar.rescheduleMonitor(auctionId, newEndTime)
+
+	logger.Info("Auction extended due to late bid",
+		zap.String("auction_id", auctionId),
+		zap.Time("end_time", newEndTime))
+
+	return newEndTime, true, nil
+}
+
+// rescheduleMonitor replaces the running monitor of an auction with one
+// that fires at endTime plus the close grace. The active auctions counter
+// is left untouched.
+func (ar *AuctionRepository) rescheduleMonitor(auctionId string, endTime time.Time) {
+	ar.auctionCountMutex.Lock()
+	defer ar.auctionCountMutex.Unlock()
+
+	if _, ok := ar.monitoredAuctions[auctionId]; !ok {
+		return
+	}
+
+	ar.monitors.Schedule(auctionId, ar.closeTime(endTime))
+}
+
+func (ar *AuctionRepository) isAntiSnipeEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("ANTI_SNIPE_ENABLED"))
+	return err == nil && enabled
+}
+
+func (ar *AuctionRepository) getAntiSnipeWindow() time.Duration {
+	window, err := time.ParseDuration(os.Getenv("ANTI_SNIPE_WINDOW"))
+	if err != nil || window <= 0 {
+		return 10 * time.Second
+	}
+	return window
+}
+
+func (ar *AuctionRepository) getAntiSnipeExtension() time.Duration {
+	extension, err := time.ParseDuration(os.Getenv("ANTI_SNIPE_EXTENSION"))
+	if err != nil || extension <= 0 {
+		return 30 * time.Second
+	}
+	return extension
+}
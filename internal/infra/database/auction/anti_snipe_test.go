@@ -0,0 +1,93 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendForLateBid(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("ANTI_SNIPE_ENABLED", "true")
+	os.Setenv("ANTI_SNIPE_WINDOW", "10s")
+	os.Setenv("ANTI_SNIPE_EXTENSION", "3s")
+	defer os.Unsetenv("ANTI_SNIPE_ENABLED")
+	defer os.Unsetenv("ANTI_SNIPE_WINDOW")
+	defer os.Unsetenv("ANTI_SNIPE_EXTENSION")
+
+	t.Run("bid inside the window extends the auction", func(t *testing.T) {
+		os.Setenv("AUCTION_INTERVAL", "2s")
+		defer os.Unsetenv("AUCTION_INTERVAL")
+
+		db, cleanup := setupTestDB()
+		defer cleanup()
+
+		repo := NewAuctionRepository(db)
+		ctx := context.Background()
+
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+		before, err := repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+
+		endTime, extended, err := repo.ExtendForLateBid(ctx, auction.Id, time.Now())
+		assert.Nil(t, err)
+		assert.True(t, extended)
+		assert.Equal(t, before.EndTime.Add(3*time.Second), endTime)
+
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, endTime, stored.EndTime)
+
+		// The original deadline passes without closing the auction
+		time.Sleep(time.Until(before.EndTime) + time.Second)
+		stored, err = repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, auction_entity.Active, stored.Status)
+
+		// The rescheduled monitor closes it at the new deadline
+		time.Sleep(time.Until(endTime) + 2*time.Second)
+		stored, err = repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, auction_entity.Completed, stored.Status)
+	})
+
+	t.Run("bid well before the window keeps the end time", func(t *testing.T) {
+		os.Setenv("AUCTION_INTERVAL", "1h")
+		defer os.Unsetenv("AUCTION_INTERVAL")
+
+		db, cleanup := setupTestDB()
+		defer cleanup()
+
+		repo := NewAuctionRepository(db)
+		ctx := context.Background()
+
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+		before, err := repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+
+		endTime, extended, err := repo.ExtendForLateBid(ctx, auction.Id, time.Now())
+		assert.Nil(t, err)
+		assert.False(t, extended)
+		assert.Equal(t, before.EndTime, endTime)
+
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, before.EndTime, stored.EndTime)
+		assert.Equal(t, before.Version, stored.Version)
+	})
+}
@@ -0,0 +1,122 @@
+package auction
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+// auctionCache is a small LRU of auctions by id. Entries expire after ttl
+// so a status change missed by invalidation does not linger.
+type auctionCache struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+type auctionCacheEntry struct {
+	id        string
+	auction   auction_entity.Auction
+	expiresAt time.Time
+}
+
+func newAuctionCache(size int, ttl time.Duration) *auctionCache {
+	return &auctionCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// getOrLoad returns the cached auction for id, calling load on a miss and
+// caching its result. Errors are never cached.
+func (c *auctionCache) getOrLoad(
+	id string,
+	load func() (*auction_entity.Auction, *internal_error.InternalError)) (*auction_entity.Auction, *internal_error.InternalError) {
+	if auction, ok := c.get(id); ok {
+		return auction, nil
+	}
+
+	auction, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(*auction)
+	return auction, nil
+}
+
+func (c *auctionCache) get(id string) (*auction_entity.Auction, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*auctionCacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, id)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	// Hand out a copy so callers cannot mutate the cached value
+	auction := entry.auction
+	return &auction, true
+}
+
+func (c *auctionCache) put(auction auction_entity.Auction) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.entries[auction.Id]; ok {
+		entry := element.Value.(*auctionCacheEntry)
+		entry.auction = auction
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[auction.Id] = c.order.PushFront(&auctionCacheEntry{
+		id:        auction.Id,
+		auction:   auction,
+		expiresAt: expiresAt,
+	})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*auctionCacheEntry).id)
+	}
+}
+
+func (c *auctionCache) invalidate(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[id]; ok {
+		c.order.Remove(element)
+		delete(c.entries, id)
+	}
+}
+
+func (c *auctionCache) purge() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
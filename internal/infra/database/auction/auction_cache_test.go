@@ -0,0 +1,122 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingLoader struct {
+	calls   int
+	auction auction_entity.Auction
+}
+
+func (l *countingLoader) load() (*auction_entity.Auction, *internal_error.InternalError) {
+	l.calls++
+	auction := l.auction
+	return &auction, nil
+}
+
+func TestAuctionCache(t *testing.T) {
+	t.Run("cached read skips the loader", func(t *testing.T) {
+		cache := newAuctionCache(10, time.Minute)
+		loader := &countingLoader{auction: auction_entity.Auction{Id: "cached", Status: auction_entity.Active}}
+
+		first, err := cache.getOrLoad("cached", loader.load)
+		assert.Nil(t, err)
+		second, err := cache.getOrLoad("cached", loader.load)
+		assert.Nil(t, err)
+
+		assert.Equal(t, 1, loader.calls)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("invalidate forces a reload", func(t *testing.T) {
+		cache := newAuctionCache(10, time.Minute)
+		loader := &countingLoader{auction: auction_entity.Auction{Id: "cached", Status: auction_entity.Active}}
+
+		_, _ = cache.getOrLoad("cached", loader.load)
+		loader.auction.Status = auction_entity.Completed
+		cache.invalidate("cached")
+
+		auction, err := cache.getOrLoad("cached", loader.load)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, loader.calls)
+		assert.Equal(t, auction_entity.Completed, auction.Status)
+	})
+
+	t.Run("expired entries are reloaded", func(t *testing.T) {
+		now := time.Now()
+		cache := newAuctionCache(10, time.Second)
+		cache.now = func() time.Time { return now }
+		loader := &countingLoader{auction: auction_entity.Auction{Id: "cached"}}
+
+		_, _ = cache.getOrLoad("cached", loader.load)
+		now = now.Add(2 * time.Second)
+		_, _ = cache.getOrLoad("cached", loader.load)
+
+		assert.Equal(t, 2, loader.calls)
+	})
+
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		cache := newAuctionCache(2, time.Minute)
+		cache.put(auction_entity.Auction{Id: "a"})
+		cache.put(auction_entity.Auction{Id: "b"})
+		_, _ = cache.get("a")
+		cache.put(auction_entity.Auction{Id: "c"})
+
+		_, okA := cache.get("a")
+		_, okB := cache.get("b")
+		_, okC := cache.get("c")
+		assert.True(t, okA)
+		assert.False(t, okB)
+		assert.True(t, okC)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		cache := newAuctionCache(10, time.Minute)
+		calls := 0
+		failing := func() (*auction_entity.Auction, *internal_error.InternalError) {
+			calls++
+			return nil, internal_error.NewNotFoundError("auction not found")
+		}
+
+		_, err := cache.getOrLoad("missing", failing)
+		assert.NotNil(t, err)
+		_, err = cache.getOrLoad("missing", failing)
+		assert.NotNil(t, err)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestFindAuctionByIdCacheInvalidatedOnStatusChange(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithFindByIdCache(10, time.Minute))
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{Id: "auction-cached", ProductName: "Test Product",
+		Category: "Electronics", Description: "Test description for auction", Condition: auction_entity.New,
+		Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()})
+	assert.Nil(t, err)
+
+	auction, findErr := repo.FindAuctionById(ctx, "auction-cached")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Active, auction.Status)
+
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, "auction-cached", auction_entity.Completed))
+
+	auction, findErr = repo.FindAuctionById(ctx, "auction-cached")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Completed, auction.Status)
+}
@@ -0,0 +1,109 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+// auctionCountCache keeps the result of CountAuctions per filter for ttl.
+// Every write that can change a count clears it as a whole, which is cheap
+// since it only ever holds a handful of listing filters.
+type auctionCountCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]auctionCountEntry
+}
+
+type auctionCountEntry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// WithCountCache reuses the total of CountAuctions for the same filter for
+// up to ttl. Creating, closing, updating or purging auctions drops every
+// cached total. A non-positive ttl leaves the cache disabled.
+func WithCountCache(ttl time.Duration) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		if ttl > 0 {
+			ar.countCache = &auctionCountCache{
+				ttl:     ttl,
+				entries: make(map[string]auctionCountEntry),
+			}
+		}
+	}
+}
+
+// CountAuctions returns how many auctions match filter, served from the
+// count cache while fresh when WithCountCache is set.
+func (ar *AuctionRepository) CountAuctions(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) (int64, *internal_error.InternalError) {
+	if ar.countCache == nil {
+		return ar.countAuctionsFromDatabase(ctx, filter)
+	}
+
+	signature := filterSignature(filter)
+	now := ar.clock.Now()
+
+	ar.countCache.mutex.Lock()
+	entry, ok := ar.countCache.entries[signature]
+	ar.countCache.mutex.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.count, nil
+	}
+
+	count, err := ar.countAuctionsFromDatabase(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	ar.countCache.mutex.Lock()
+	ar.countCache.entries[signature] = auctionCountEntry{count: count, expiresAt: now.Add(ar.countCache.ttl)}
+	ar.countCache.mutex.Unlock()
+
+	return count, nil
+}
+
+func (ar *AuctionRepository) countAuctionsFromDatabase(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) (int64, *internal_error.InternalError) {
+	count, err := ar.Collection.CountDocuments(ctx, auctionsFilter(filter))
+	if err != nil {
+		logger.Error("Error trying to count auctions", err)
+		return 0, internal_error.NewInternalServerError("Error trying to count auctions")
+	}
+
+	return count, nil
+}
+
+// invalidateCachedCounts drops every cached CountAuctions total, if any.
+func (ar *AuctionRepository) invalidateCachedCounts() {
+	if ar.countCache == nil {
+		return
+	}
+
+	ar.countCache.mutex.Lock()
+	defer ar.countCache.mutex.Unlock()
+
+	ar.countCache.entries = make(map[string]auctionCountEntry)
+}
+
+// filterSignature identifies a filter by value; HasWinner is a pointer and
+// ExcludeStatuses a slice, so the filter itself cannot be used as a map key.
+func filterSignature(filter auction_entity.AuctionFilter) string {
+	hasWinner := "any"
+	if filter.HasWinner != nil {
+		hasWinner = strconv.FormatBool(*filter.HasWinner)
+	}
+
+	return fmt.Sprintf("%d|%q|%q|%q|%d|%d|%s|%v",
+		filter.Status, filter.FilterCategories(), filter.Subcategory, filter.ProductName,
+		filter.CreatedFrom, filter.CreatedTo, hasWinner, filter.ExcludeStatuses)
+}
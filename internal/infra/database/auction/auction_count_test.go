@@ -0,0 +1,115 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountAuctionsCache(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock), WithCountCache(time.Minute))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	allFilter := auction_entity.AuctionFilter{}
+	count := func(filter auction_entity.AuctionFilter) int64 {
+		total, err := repo.CountAuctions(ctx, filter)
+		assert.Nil(t, err)
+		return total
+	}
+
+	// Inserções diretas na coleção não invalidam o cache
+	insertDirectly := func(id string) {
+		_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{
+			Id: id, Status: auction_entity.Active, Timestamp: 1000, EndTime: 9000})
+		assert.Nil(t, err)
+	}
+
+	insertDirectly("first")
+	assert.Equal(t, int64(1), count(allFilter))
+
+	t.Run("reuses the cached count within the ttl", func(t *testing.T) {
+		insertDirectly("second")
+		assert.Equal(t, int64(1), count(allFilter))
+
+		// Outro filtro tem sua própria entrada
+		assert.Equal(t, int64(0), count(auction_entity.AuctionFilter{Status: auction_entity.Completed}))
+	})
+
+	t.Run("recounts once the ttl expires", func(t *testing.T) {
+		fakeClock.Advance(time.Minute)
+		assert.Equal(t, int64(2), count(allFilter))
+	})
+
+	t.Run("recounts after an invalidating write", func(t *testing.T) {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		assert.Equal(t, int64(3), count(allFilter))
+
+		insertDirectly("third")
+		insertDirectly("fourth")
+		assert.Equal(t, int64(3), count(allFilter))
+
+		assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+		assert.Equal(t, int64(5), count(allFilter))
+	})
+
+	t.Run("recounts after a winner is assigned", func(t *testing.T) {
+		hasWinner := true
+		withWinner := auction_entity.AuctionFilter{HasWinner: &hasWinner}
+		assert.Equal(t, int64(0), count(withWinner))
+
+		assigned, err := repo.AssignWinner(ctx, "first", "winning-bid")
+		assert.Nil(t, err)
+		assert.True(t, assigned)
+		assert.Equal(t, int64(1), count(withWinner))
+	})
+}
+
+func TestCountAuctionsWithoutCache(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	count := func() int64 {
+		total, err := repo.CountAuctions(ctx, auction_entity.AuctionFilter{})
+		assert.Nil(t, err)
+		return total
+	}
+
+	_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{
+		Id: "first", Status: auction_entity.Completed, Timestamp: 1000, EndTime: 1100})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count())
+
+	_, err = repo.Collection.InsertOne(ctx, AuctionEntityMongo{
+		Id: "second", Status: auction_entity.Completed, Timestamp: 1000, EndTime: 1100})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count())
+}
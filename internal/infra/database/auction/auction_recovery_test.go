@@ -2,11 +2,16 @@ package auction
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/danielencestari/lab03/internal/clock"
 	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/metrics"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -125,3 +130,226 @@ func TestGetAuctionDuration(t *testing.T) {
 	duration := repo.getAuctionDuration()
 	assert.Equal(t, 5*time.Minute, duration)
 }
+
+func TestWaitForRecovery(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	ctx := context.Background()
+	endTime := time.Now().Add(time.Hour).Unix()
+	var pending []interface{}
+	for i := 0; i < 20; i++ {
+		pending = append(pending, AuctionEntityMongo{
+			Id:          fmt.Sprintf("pending-recovery-%d", i),
+			ProductName: "Recovery Test Product",
+			Category:    "Electronics",
+			Status:      auction_entity.Active,
+			Timestamp:   time.Now().Unix(),
+			EndTime:     endTime,
+		})
+	}
+	_, err := db.Collection("auctions").InsertMany(ctx, pending)
+	assert.Nil(t, err)
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+
+	// A recuperação roda em background e ainda precisa consultar o banco
+	assert.False(t, repo.RecoveryDone())
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	assert.Nil(t, repo.WaitForRecovery(waitCtx))
+	assert.True(t, repo.RecoveryDone())
+	assert.Equal(t, int64(len(pending)), repo.Stats().Active)
+}
+
+func TestRecoveryClosesAuctionsBeyondMaxRemainingTime(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	_, err := db.Collection("auctions").InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "skewed", ProductName: "Recovery Test Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(48 * time.Hour).Unix()},
+		AuctionEntityMongo{Id: "regular", ProductName: "Recovery Test Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()},
+	})
+	assert.Nil(t, err)
+
+	repo := NewAuctionRepository(db,
+		WithClock(clock.NewFakeClock(now)),
+		WithConfig(Config{MaxRemainingTime: 24 * time.Hour}))
+	defer repo.Close()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	skewed, findErr := repo.FindAuctionById(ctx, "skewed")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Completed, skewed.Status)
+
+	regular, findErr := repo.FindAuctionById(ctx, "regular")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Active, regular.Status)
+
+	assert.Equal(t, int64(1), repo.Stats().Active)
+
+	var history StatusHistoryEntityMongo
+	assert.Nil(t, repo.historyCollection.FindOne(ctx, bson.M{"auction_id": "skewed"}).Decode(&history))
+	assert.Equal(t, statusReasonClockSkew, history.Reason)
+}
+
+func TestRecoveryMetrics(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	seed := func(id string, endTime time.Time) interface{} {
+		return AuctionEntityMongo{Id: id, ProductName: "Recovery Test Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: endTime.Unix()}
+	}
+	// Ordenados por end_time: o expirado e os dois primeiros ocupam as três
+	// vagas, o último excede o limite
+	_, err := db.Collection("auctions").InsertMany(ctx, []interface{}{
+		seed("expired", now.Add(-time.Minute)),
+		seed("recovered-1", now.Add(time.Hour)),
+		seed("recovered-2", now.Add(2*time.Hour)),
+		seed("over-limit", now.Add(3*time.Hour)),
+		seed("skewed", now.Add(48*time.Hour)),
+	})
+	assert.Nil(t, err)
+
+	registry := metrics.NewRegistry()
+	repo := NewAuctionRepository(db,
+		WithClock(clock.NewFakeClock(now)),
+		WithMetrics(registry),
+		WithConfig(Config{MaxConcurrentAuctions: 3, MaxRemainingTime: 24 * time.Hour}))
+	defer repo.Close()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	counters := registry.Snapshot().Counters
+	assert.Equal(t, int64(2), counters["recovery_recovered"])
+	assert.Equal(t, int64(1), counters["recovery_closed_expired"])
+	assert.Equal(t, int64(1), counters["recovery_closed_over_limit"])
+	assert.Equal(t, int64(1), counters["recovery_closed_clock_skew"])
+}
+
+// cancellingClock cancels a context on its n-th reading, letting a test
+// cancel the recovery while it walks the recovered auctions.
+type cancellingClock struct {
+	*clock.FakeClock
+	mutex  sync.Mutex
+	reads  int
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancellingClock) Now() time.Time {
+	c.mutex.Lock()
+	c.reads++
+	if c.reads == c.n {
+		c.cancel()
+	}
+	c.mutex.Unlock()
+
+	return c.FakeClock.Now()
+}
+
+func TestRecoveryStopsWhenContextIsCancelled(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	const seeded = 500
+	auctions := make([]interface{}, 0, seeded)
+	for i := 0; i < seeded; i++ {
+		auctions = append(auctions, AuctionEntityMongo{Id: fmt.Sprintf("auction-%03d", i),
+			ProductName: "Recovery Test Product", Category: "Electronics", Status: auction_entity.Active,
+			Timestamp: now.Unix(), EndTime: now.Add(time.Hour + time.Duration(i)*time.Second).Unix()})
+	}
+	_, err := db.Collection("auctions").InsertMany(ctx, auctions)
+	assert.Nil(t, err)
+
+	// O contexto é cancelado no meio da recuperação
+	recoveryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	registry := metrics.NewRegistry()
+	repo := NewAuctionRepository(db,
+		WithContext(recoveryCtx),
+		WithClock(&cancellingClock{FakeClock: clock.NewFakeClock(now), n: 100, cancel: cancel}),
+		WithMetrics(registry),
+		WithConfig(Config{MaxConcurrentAuctions: seeded, MaxRemainingTime: 24 * time.Hour}))
+	defer repo.Close()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	recovered := registry.Snapshot().Counters["recovery_recovered"]
+	assert.Greater(t, recovered, int64(0))
+	assert.Less(t, recovered, int64(seeded))
+	assert.Equal(t, int(recovered), repo.monitors.Pending())
+
+	// Os leilões não alcançados continuam ativos para o próximo start
+	active, err := repo.Collection.CountDocuments(ctx, bson.M{"status": auction_entity.Active})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(seeded), active)
+}
+
+func TestRecoverySkipsAuctionsClosedAfterTheScan(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithConfig(Config{AuctionInterval: time.Hour}))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	now := time.Now()
+	seed := func(id string) AuctionEntityMongo {
+		return AuctionEntityMongo{Id: id, ProductName: "Recovery Test Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()}
+	}
+	scan := []AuctionEntityMongo{seed("still-active"), seed("cancelled"), seed("closed")}
+	for _, auction := range scan {
+		_, err := repo.Collection.InsertOne(ctx, auction)
+		assert.Nil(t, err)
+	}
+
+	// A varredura já foi feita quando estes dois mudam de status
+	_, cancelErr := repo.CancelAllActive(ctx, "incident drill")
+	assert.Nil(t, cancelErr)
+	_, err := repo.Collection.UpdateOne(ctx, bson.M{"_id": "still-active"},
+		bson.M{"$set": bson.M{"status": auction_entity.Active}})
+	assert.Nil(t, err)
+	_, err = repo.Collection.UpdateOne(ctx, bson.M{"_id": "closed"},
+		bson.M{"$set": bson.M{"status": auction_entity.Completed}})
+	assert.Nil(t, err)
+
+	repo.recoverActiveAuctions(ctx, scan)
+
+	repo.auctionCountMutex.Lock()
+	assert.Equal(t, map[string]string{"still-active": auction_entity.NormalizeCategory("Electronics")}, repo.monitoredAuctions)
+	repo.auctionCountMutex.Unlock()
+	assert.Equal(t, int64(1), repo.Stats().Active)
+	assert.Equal(t, 1, repo.monitors.Pending())
+}
@@ -0,0 +1,103 @@
+package auction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// auctionStatsTTL is how long AuctionStats reuses its last result, so a
+// busy dashboard does not run the aggregation on every request.
+const auctionStatsTTL = 5 * time.Second
+
+type auctionStatsCache struct {
+	mutex     sync.Mutex
+	stats     *auction_entity.AuctionStats
+	expiresAt time.Time
+}
+
+// AuctionStats counts auctions per status and sums the duration of the
+// completed ones in a single aggregation. Results are cached for
+// auctionStatsTTL.
+func (ar *AuctionRepository) AuctionStats(
+	ctx context.Context) (*auction_entity.AuctionStats, *internal_error.InternalError) {
+	ar.statsCache.mutex.Lock()
+	defer ar.statsCache.mutex.Unlock()
+
+	now := ar.clock.Now()
+	if ar.statsCache.stats != nil && now.Before(ar.statsCache.expiresAt) {
+		stats := *ar.statsCache.stats
+		return &stats, nil
+	}
+
+	stats, err := ar.aggregateAuctionStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ar.statsCache.stats = stats
+	ar.statsCache.expiresAt = now.Add(auctionStatsTTL)
+
+	result := *stats
+	return &result, nil
+}
+
+func (ar *AuctionRepository) aggregateAuctionStats(
+	ctx context.Context) (*auction_entity.AuctionStats, *internal_error.InternalError) {
+	// Auctions closed before closed_at was recorded fall back to end_time
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$status",
+			"count": bson.M{"$sum": 1},
+			"duration": bson.M{"$sum": bson.M{"$subtract": bson.A{
+				bson.M{"$ifNull": bson.A{"$closed_at", "$end_time"}},
+				"$timestamp",
+			}}},
+		}}},
+	}
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to aggregate auction stats", err)
+		return nil, internal_error.NewInternalServerError("Error trying to compute auction stats")
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status   auction_entity.AuctionStatus `bson:"_id"`
+		Count    int64                        `bson:"count"`
+		Duration int64                        `bson:"duration"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error("Error trying to decode auction stats", err)
+		return nil, internal_error.NewInternalServerError("Error trying to compute auction stats")
+	}
+
+	stats := &auction_entity.AuctionStats{}
+	for _, group := range groups {
+		stats.Total += group.Count
+		switch group.Status {
+		case auction_entity.Active:
+			stats.Active = group.Count
+		case auction_entity.Completed:
+			stats.Completed = group.Count
+			stats.CompletedDuration = time.Duration(group.Duration) * time.Second
+		}
+	}
+
+	bids, err := ar.bidsCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		logger.Error("Error trying to count bids for auction stats", err)
+		return nil, internal_error.NewInternalServerError("Error trying to compute auction stats")
+	}
+	stats.Bids = bids
+
+	return stats, nil
+}
@@ -0,0 +1,73 @@
+package auction
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestAuctionStats(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "active", Status: auction_entity.Active, Timestamp: 1000, EndTime: 9000},
+		AuctionEntityMongo{Id: "completed-short", Status: auction_entity.Completed,
+			Timestamp: 1000, EndTime: 1100, ClosedAt: 1100},
+		AuctionEntityMongo{Id: "completed-long", Status: auction_entity.Completed,
+			Timestamp: 1000, EndTime: 1300, ClosedAt: 1300},
+		AuctionEntityMongo{Id: "cancelled", Status: auction_entity.Cancelled,
+			Timestamp: 1000, EndTime: 1300, ClosedAt: 1050},
+	})
+	assert.Nil(t, err)
+
+	_, err = repo.Collection.Aggregate(ctx, mongo.Pipeline{{{Key: "$project", Value: bson.M{
+		"duration": bson.M{"$subtract": bson.A{"$end_time", "$timestamp"}}}}}})
+	if err != nil && strings.Contains(err.Error(), "not implemented") {
+		t.Skip("Agregação não suportada por este servidor MongoDB")
+	}
+
+	_, err = repo.bidsCollection.InsertMany(ctx, []interface{}{
+		bson.M{"_id": "bid-1", "auction_id": "completed-short", "amount_cents": 1000},
+		bson.M{"_id": "bid-2", "auction_id": "completed-long", "amount_cents": 2000},
+	})
+	assert.Nil(t, err)
+
+	stats, statsErr := repo.AuctionStats(ctx)
+	assert.Nil(t, statsErr)
+	assert.Equal(t, int64(4), stats.Total)
+	assert.Equal(t, int64(1), stats.Active)
+	assert.Equal(t, int64(2), stats.Completed)
+	assert.Equal(t, 400*time.Second, stats.CompletedDuration)
+	assert.Equal(t, int64(2), stats.Bids)
+
+	// Within the TTL the cached result is returned
+	_, err = repo.Collection.InsertOne(ctx, AuctionEntityMongo{Id: "late", Status: auction_entity.Active})
+	assert.Nil(t, err)
+	stats, statsErr = repo.AuctionStats(ctx)
+	assert.Nil(t, statsErr)
+	assert.Equal(t, int64(4), stats.Total)
+
+	fakeClock.Advance(auctionStatsTTL)
+	stats, statsErr = repo.AuctionStats(ctx)
+	assert.Nil(t, statsErr)
+	assert.Equal(t, int64(5), stats.Total)
+	assert.Equal(t, int64(2), stats.Active)
+}
@@ -0,0 +1,104 @@
+package auction
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CountAuctionsByTime groups auctions on their creation timestamp truncated
+// to the bucket, in UTC. Timestamps are stored in unix seconds, so they are
+// converted to dates first.
+func (ar *AuctionRepository) CountAuctionsByTime(
+	ctx context.Context,
+	bucket auction_entity.TimeBucket,
+	from, to time.Time) ([]auction_entity.AuctionCountBucket, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: creationWindowFilter(from, to)}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateTrunc": bson.M{
+				"date":     bson.M{"$toDate": bson.M{"$multiply": bson.A{"$timestamp", 1000}}},
+				"unit":     string(bucket),
+				"timezone": "UTC",
+			}},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to aggregate auction creation series", err)
+		return nil, internal_error.NewInternalServerError("Error trying to count auctions by time")
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Start time.Time `bson:"_id"`
+		Count int64     `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error("Error trying to decode auction creation series", err)
+		return nil, internal_error.NewInternalServerError("Error trying to count auctions by time")
+	}
+
+	buckets := make([]auction_entity.AuctionCountBucket, 0, len(groups))
+	for _, group := range groups {
+		buckets = append(buckets, auction_entity.AuctionCountBucket{
+			Start: group.Start.UTC(),
+			Count: group.Count,
+		})
+	}
+
+	return buckets, nil
+}
+
+func creationWindowFilter(from, to time.Time) bson.M {
+	timestamp := bson.M{}
+	if !from.IsZero() {
+		timestamp["$gte"] = from.Unix()
+	}
+	if !to.IsZero() {
+		timestamp["$lt"] = to.Unix()
+	}
+
+	if len(timestamp) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"timestamp": timestamp}
+}
+
+// truncateToBucket is the in-process equivalent of the $dateTrunc stage.
+func truncateToBucket(t time.Time, bucket auction_entity.TimeBucket) time.Time {
+	t = t.UTC()
+	if bucket == auction_entity.DayBucket {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// mergeCountBuckets sums the buckets of several series into one, oldest
+// first.
+func mergeCountBuckets(series ...[]auction_entity.AuctionCountBucket) []auction_entity.AuctionCountBucket {
+	counts := make(map[time.Time]int64)
+	for _, buckets := range series {
+		for _, bucket := range buckets {
+			counts[bucket.Start] += bucket.Count
+		}
+	}
+
+	merged := make([]auction_entity.AuctionCountBucket, 0, len(counts))
+	for start, count := range counts {
+		merged = append(merged, auction_entity.AuctionCountBucket{Start: start, Count: count})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Start.Before(merged[j].Start) })
+
+	return merged
+}
@@ -0,0 +1,102 @@
+package auction
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var timeseriesStart = time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+// seedTimeseries cria 2 leilões às 10h, 1 às 11h do dia 10 e 1 às 9h do dia 11
+func seedTimeseries() []time.Time {
+	return []time.Time{
+		timeseriesStart.Add(10*time.Hour + 5*time.Minute),
+		timeseriesStart.Add(10*time.Hour + 55*time.Minute),
+		timeseriesStart.Add(11*time.Hour + 30*time.Minute),
+		timeseriesStart.Add(33 * time.Hour),
+	}
+}
+
+func assertTimeseries(t *testing.T, repo auction_entity.AuctionRepositoryInterface) {
+	ctx := context.Background()
+
+	hours, err := repo.CountAuctionsByTime(ctx, auction_entity.HourBucket, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, []auction_entity.AuctionCountBucket{
+		{Start: timeseriesStart.Add(10 * time.Hour), Count: 2},
+		{Start: timeseriesStart.Add(11 * time.Hour), Count: 1},
+		{Start: timeseriesStart.Add(33 * time.Hour), Count: 1},
+	}, hours)
+
+	days, err := repo.CountAuctionsByTime(ctx, auction_entity.DayBucket, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, []auction_entity.AuctionCountBucket{
+		{Start: timeseriesStart, Count: 3},
+		{Start: timeseriesStart.Add(24 * time.Hour), Count: 1},
+	}, days)
+
+	// A janela é fechada no início e aberta no fim
+	window, err := repo.CountAuctionsByTime(ctx, auction_entity.HourBucket,
+		timeseriesStart.Add(10*time.Hour+55*time.Minute), timeseriesStart.Add(33*time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, []auction_entity.AuctionCountBucket{
+		{Start: timeseriesStart.Add(10 * time.Hour), Count: 1},
+		{Start: timeseriesStart.Add(11 * time.Hour), Count: 1},
+	}, window)
+}
+
+func TestCountAuctionsByTime(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	var seeded []interface{}
+	for i, created := range seedTimeseries() {
+		seeded = append(seeded, AuctionEntityMongo{Id: string(rune('a' + i)), ProductName: "Series Product",
+			Category: "Electronics", Status: auction_entity.Completed,
+			Timestamp: created.Unix(), EndTime: created.Unix()})
+	}
+	_, err := repo.Collection.InsertMany(ctx, seeded)
+	assert.Nil(t, err)
+
+	_, err = repo.Collection.Aggregate(ctx, mongo.Pipeline{{{Key: "$project", Value: bson.M{
+		"created": bson.M{"$dateTrunc": bson.M{
+			"date": bson.M{"$toDate": bson.M{"$multiply": bson.A{"$timestamp", 1000}}},
+			"unit": "hour",
+		}}}}}})
+	if err != nil && strings.Contains(err.Error(), "not implemented") {
+		t.Skip("Agregação não suportada por este servidor MongoDB")
+	}
+
+	assertTimeseries(t, repo)
+}
+
+func TestMemoryCountAuctionsByTime(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+
+	for _, created := range seedTimeseries() {
+		auction, err := auction_entity.CreateAuction(
+			"Series Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		auction.Timestamp = created
+		assert.Nil(t, repo.CreateAuction(context.Background(), auction))
+	}
+
+	assertTimeseries(t, repo)
+}
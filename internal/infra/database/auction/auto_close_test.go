@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/danielencestari/lab03/configuration/database/mongodb"
+	"github.com/danielencestari/lab03/internal/clock"
 	"github.com/danielencestari/lab03/internal/entity/auction_entity"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,17 +16,7 @@ import (
 )
 
 func isMongoDBAvailable() bool {
-	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
-	if err != nil {
-		return false
-	}
-	defer client.Disconnect(context.Background())
-
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	err = client.Ping(ctx, nil)
-	return err == nil
+	return mongodb.IsAvailable("mongodb://localhost:27017", 0)
 }
 
 func setupAutoCloseTestDB() (*mongo.Database, func()) {
@@ -129,7 +121,7 @@ func TestAutoCloseAuctionValidation(t *testing.T) {
 	t.Log("✅ SUCESSO: Leilão fechado automaticamente com status COMPLETED")
 
 	// Verificar se o contador de leilões ativos foi decrementado
-	assert.Equal(t, int64(0), int64(repo.activeAuctionsCount))
+	assert.Equal(t, int64(0), repo.Stats().Active)
 
 	t.Log("✅ SUCESSO: Contador de leilões ativos decrementado corretamente")
 
@@ -183,8 +175,8 @@ func TestMultipleAuctionsAutoClose(t *testing.T) {
 	}
 
 	// Verificar contador de leilões ativos
-	assert.Equal(t, int64(numAuctions), int64(repo.activeAuctionsCount))
-	t.Logf("Contador de leilões ativos: %d", repo.activeAuctionsCount)
+	assert.Equal(t, int64(numAuctions), repo.Stats().Active)
+	t.Logf("Contador de leilões ativos: %d", repo.Stats().Active)
 
 	// Aguardar fechamento automático (4s + 1s buffer)
 	t.Log("Aguardando fechamento automático...")
@@ -199,7 +191,7 @@ func TestMultipleAuctionsAutoClose(t *testing.T) {
 	}
 
 	// Verificar se contador foi zerado
-	assert.Equal(t, int64(0), int64(repo.activeAuctionsCount))
+	assert.Equal(t, int64(0), repo.Stats().Active)
 	t.Log("✅ Contador de leilões ativos zerado corretamente")
 
 	t.Log("=== TESTE DE MÚLTIPLOS LEILÕES CONCLUÍDO COM SUCESSO ===")
@@ -349,3 +341,163 @@ func TestAutoCloseRobustness(t *testing.T) {
 
 	t.Log("✅ TESTE DE ROBUSTEZ CONCLUÍDO COM SUCESSO")
 }
+
+func TestAutoCloseWithFakeClock(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	// Um intervalo longo prova que nenhum tempo real precisa passar
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	fakeClock.BlockUntil(1)
+
+	fakeClock.Advance(59 * time.Minute)
+	stored, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, stored.Status)
+
+	fakeClock.Advance(time.Minute)
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, fakeClock.Now().Unix(), stored.ClosedAt.Unix())
+}
+
+func TestCancelledMonitorReleasesTimer(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	fakeClock.BlockUntil(1)
+
+	// Cancelar antes do timer disparar deve liberar o timer do monitor
+	_, err = repo.CancelAllActive(ctx, "timer cleanup test")
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool {
+		return fakeClock.PendingTimers() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestStatsTracksCreatesAndCloses(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	assert.Equal(t, RepositoryStats{Active: 0, Max: 50, ScheduledMonitors: 0}, repo.Stats())
+
+	var auctions []*auction_entity.Auction
+	for i := 0; i < 3; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		auctions = append(auctions, auction)
+	}
+
+	stats := repo.Stats()
+	assert.Equal(t, int64(3), stats.Active)
+	assert.Equal(t, 3, stats.ScheduledMonitors)
+
+	// O fechamento feito pelo monitor libera a vaga
+	assert.Nil(t, repo.closeMonitoredAuction(ctx, auctions[0].Id))
+	stats = repo.Stats()
+	assert.Equal(t, int64(2), stats.Active)
+	assert.Equal(t, 2, stats.ScheduledMonitors)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+	assert.Eventually(t, func() bool {
+		return repo.Stats() == RepositoryStats{Active: 0, Max: 50, ScheduledMonitors: 0}
+	}, time.Second, time.Millisecond)
+}
+
+func TestAutoCloseWaitsForCloseGrace(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	os.Setenv("AUCTION_CLOSE_GRACE", "2s")
+	defer os.Unsetenv("AUCTION_CLOSE_GRACE")
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	fakeClock.BlockUntil(1)
+
+	// O end_time exibido não inclui a carência
+	stored, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction.Timestamp.Add(time.Hour).Unix(), stored.EndTime.Unix())
+
+	fakeClock.Advance(time.Hour + time.Second)
+	time.Sleep(50 * time.Millisecond)
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, stored.Status)
+
+	fakeClock.Advance(time.Second)
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+}
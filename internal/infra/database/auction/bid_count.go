@@ -0,0 +1,32 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AdjustBidCount atomically adds delta to the denormalized bid_count of an
+// auction. The bid repository calls it as bids are placed and retracted;
+// the version is left alone since the auction itself was not edited.
+func (ar *AuctionRepository) AdjustBidCount(
+	ctx context.Context, auctionId string, delta int64) *internal_error.InternalError {
+	result, err := ar.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId}, bson.M{"$inc": bson.M{"bid_count": delta}})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to update bid count of auction with id = %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to update auction bid count")
+	}
+	ar.invalidateCachedAuction(auctionId)
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+
+	return nil
+}
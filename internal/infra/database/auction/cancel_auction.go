@@ -0,0 +1,96 @@
+package auction
+
+import (
+	"context"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// CancelAllActive is an emergency stop: every active or paused auction
+// becomes Cancelled in a single write and their monitors are stopped. The
+// database is read and written without holding auctionCountMutex, so
+// creates and closes keep going meanwhile; auctions created after the scan
+// are left alone.
+func (ar *AuctionRepository) CancelAllActive(
+	ctx context.Context, reason string) (int64, *internal_error.InternalError) {
+	open := bson.M{"$in": bson.A{auction_entity.Active, auction_entity.Paused}}
+
+	// Collect the ids first so each cancellation can be audited
+	cursor, err := ar.Collection.Find(ctx, bson.M{"status": open},
+		options.Find().SetProjection(bson.M{"_id": 1, "status": 1}))
+	if err != nil {
+		logger.Error("Error trying to find active auctions to cancel", err)
+		return 0, internal_error.NewInternalServerError("Error trying to cancel all active auctions")
+	}
+
+	var openAuctions []struct {
+		Id     string                       `bson:"_id"`
+		Status auction_entity.AuctionStatus `bson:"status"`
+	}
+	if err := cursor.All(ctx, &openAuctions); err != nil {
+		logger.Error("Error trying to decode active auctions to cancel", err)
+		return 0, internal_error.NewInternalServerError("Error trying to cancel all active auctions")
+	}
+
+	auctionIds := make([]string, 0, len(openAuctions))
+	idsByStatus := make(map[auction_entity.AuctionStatus][]string)
+	for _, auction := range openAuctions {
+		auctionIds = append(auctionIds, auction.Id)
+		idsByStatus[auction.Status] = append(idsByStatus[auction.Status], auction.Id)
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": auctionIds}, "status": open}
+	update := bson.M{"$set": bson.M{
+		"status":       auction_entity.Cancelled,
+		"closed_at":    ar.clock.Now().Unix(),
+		"close_reason": auction_entity.CloseReasonEmergencyCancel,
+	}, "$unset": bson.M{"paused_remaining": ""}, "$inc": bson.M{"version": 1}}
+
+	result, err := ar.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to cancel all active auctions", err)
+		return 0, internal_error.NewInternalServerError("Error trying to cancel all active auctions")
+	}
+	if ar.cache != nil {
+		ar.cache.purge()
+	}
+	ar.invalidateCachedCounts()
+	ar.closeCallbacks.forget(auctionIds...)
+
+	// A paused auction resumed after the scan was cancelled too, so its
+	// fresh monitor goes with the others
+	ar.auctionCountMutex.Lock()
+	for _, auctionId := range auctionIds {
+		if _, ok := ar.monitoredAuctions[auctionId]; ok {
+			ar.monitors.Cancel(auctionId)
+			ar.untrackAuctionLocked(auctionId)
+		}
+	}
+	ar.auctionCountMutex.Unlock()
+
+	logger.Info("All active auctions cancelled",
+		zap.String("reason", reason),
+		zap.Int64("cancelled", result.ModifiedCount))
+
+	for _, from := range []auction_entity.AuctionStatus{auction_entity.Active, auction_entity.Paused} {
+		ids := idsByStatus[from]
+		if len(ids) == 0 {
+			continue
+		}
+
+		ar.publishStatusChange(from, auction_entity.Cancelled, ids...)
+		if err := ar.recordStatusChanges(
+			ctx, reason, auction_entity.CloseReasonEmergencyCancel,
+			from, auction_entity.Cancelled, ids...); err != nil {
+			return result.ModifiedCount, err
+		}
+	}
+
+	return result.ModifiedCount, nil
+}
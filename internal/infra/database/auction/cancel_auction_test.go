@@ -0,0 +1,61 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelAllActive(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	var auctions []*auction_entity.Auction
+	for i := 0; i < 3; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product",
+			"Electronics",
+			"Test description for auction",
+			auction_entity.New,
+		)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		auctions = append(auctions, auction)
+	}
+
+	// Pausados também são cancelados, para não poderem ser retomados
+	assert.Nil(t, repo.PauseAuction(ctx, auctions[0].Id))
+
+	cancelled, err := repo.CancelAllActive(ctx, "incident drill")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), cancelled)
+	assert.Equal(t, "conflict", repo.ResumeAuction(ctx, auctions[0].Id).Err)
+
+	for _, auction := range auctions {
+		foundAuction, err := repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, auction_entity.Cancelled, foundAuction.Status)
+		assert.False(t, foundAuction.ClosedAt.IsZero())
+	}
+
+	stats := repo.Stats()
+	assert.Equal(t, int64(0), stats.Active)
+	assert.Equal(t, 0, stats.ScheduledMonitors)
+
+	repo.auctionCountMutex.Lock()
+	assert.Empty(t, repo.monitoredAuctions)
+	repo.auctionCountMutex.Unlock()
+}
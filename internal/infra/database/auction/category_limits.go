@@ -0,0 +1,80 @@
+package auction
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+)
+
+// parseCategoryLimits reads per-category caps written as
+// "electronics:100,art:20". Categories are normalized like the stored ones;
+// malformed or non-positive entries are ignored.
+func parseCategoryLimits(value string) map[string]int64 {
+	limits := make(map[string]int64)
+	for _, entry := range strings.Split(value, ",") {
+		category, limit, found := strings.Cut(entry, ":")
+		if !found {
+			continue
+		}
+
+		maxAuctions, err := strconv.ParseInt(strings.TrimSpace(limit), 10, 64)
+		if err != nil || maxAuctions <= 0 || auction_entity.NormalizeCategory(category) == "" {
+			continue
+		}
+		limits[auction_entity.NormalizeCategory(category)] = maxAuctions
+	}
+
+	if len(limits) == 0 {
+		return nil
+	}
+	return limits
+}
+
+// categoryLimit returns the cap of category and whether it has one of its
+// own; categories without one share the global MAX_CONCURRENT_AUCTIONS.
+func (ar *AuctionRepository) categoryLimit(category string) (int64, bool) {
+	category = auction_entity.NormalizeCategory(category)
+	for limitedCategory, maxAuctions := range ar.config.CategoryLimits {
+		if auction_entity.NormalizeCategory(limitedCategory) == category {
+			return maxAuctions, true
+		}
+	}
+	return 0, false
+}
+
+// hasSlotLocked reports whether another auction of category may be
+// monitored, according to the active count projection. The caller holds
+// auctionCountMutex, which every slot reservation takes, so the check and
+// the reservation that follows are atomic.
+func (ar *AuctionRepository) hasSlotLocked(category string) bool {
+	if maxAuctions, ok := ar.categoryLimit(category); ok {
+		return ar.activeProjection.countCategory(category) < maxAuctions
+	}
+	return ar.activeProjection.count() < ar.getMaxConcurrentAuctions()
+}
+
+// trackAuctionLocked takes a slot for a newly monitored auction by feeding
+// the projection its opened event. The caller holds auctionCountMutex.
+func (ar *AuctionRepository) trackAuctionLocked(auctionId, category string) {
+	ar.monitoredAuctions[auctionId] = auction_entity.NormalizeCategory(category)
+	ar.activeProjection.apply(auctionEvent{kind: auctionOpened, auctionId: auctionId, category: category})
+}
+
+// untrackAuctionLocked frees the slot of a monitored auction. The caller
+// holds auctionCountMutex.
+func (ar *AuctionRepository) untrackAuctionLocked(auctionId string) {
+	if _, ok := ar.monitoredAuctions[auctionId]; !ok {
+		return
+	}
+
+	delete(ar.monitoredAuctions, auctionId)
+	ar.activeProjection.apply(auctionEvent{kind: auctionClosed, auctionId: auctionId})
+}
+
+// untrackAllLocked frees every slot. The caller holds auctionCountMutex.
+func (ar *AuctionRepository) untrackAllLocked() {
+	for auctionId := range ar.monitoredAuctions {
+		ar.untrackAuctionLocked(auctionId)
+	}
+}
@@ -0,0 +1,101 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCategoryLimits(t *testing.T) {
+	assert.Equal(t, map[string]int64{"Electronics": 100, "Art": 20},
+		parseCategoryLimits(" Electronics:100, art:20 ,books,music:0,toys:x"))
+	assert.Nil(t, parseCategoryLimits(""))
+
+	os.Setenv("AUCTION_LIMITS", "art:2")
+	defer os.Unsetenv("AUCTION_LIMITS")
+
+	repo := &AuctionRepository{config: ConfigFromEnv()}
+	limit, ok := repo.categoryLimit("ART")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), limit)
+
+	_, ok = repo.categoryLimit("Electronics")
+	assert.False(t, ok)
+}
+
+func TestCategoryLimitReached(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithConfig(Config{
+		MaxConcurrentAuctions: 10,
+		CategoryLimits:        map[string]int64{"art": 2},
+	}))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	createAuction := func(category string) *auction_entity.Auction {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", category, "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		return auction
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, repo.CreateAuction(ctx, createAuction("Art")))
+	}
+
+	// Arte atingiu o próprio limite, as demais categorias seguem o global
+	err := repo.CreateAuction(ctx, createAuction("Art"))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Maximum concurrent auctions limit reached")
+	}
+	electronics := createAuction("Electronics")
+	assert.Nil(t, repo.CreateAuction(ctx, electronics))
+	assert.Equal(t, int64(3), repo.Stats().Active)
+
+	// Fechar um leilão de outra categoria não libera vaga em arte
+	assert.Nil(t, repo.closeMonitoredAuction(ctx, electronics.Id))
+	assert.NotNil(t, repo.CreateAuction(ctx, createAuction("Art")))
+}
+
+func TestMemoryCategoryLimitReached(t *testing.T) {
+	os.Setenv("AUCTION_LIMITS", "art:1")
+	defer os.Unsetenv("AUCTION_LIMITS")
+
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	art, err := auction_entity.CreateAuction(
+		"Test Product", "Art", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, art))
+
+	extra, err := auction_entity.CreateAuction(
+		"Test Product", "Art", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.NotNil(t, repo.CreateAuction(ctx, extra))
+
+	electronics, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, electronics))
+
+	// Fechar o leilão de arte libera a vaga da categoria
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, art.Id, auction_entity.Completed))
+	assert.Nil(t, repo.CreateAuction(ctx, extra))
+}
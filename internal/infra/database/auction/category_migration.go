@@ -0,0 +1,55 @@
+package auction
+
+import (
+	"context"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// normalizeStoredCategories rewrites the categories stored before they were
+// normalized on write, so filters on the canonical form find them too.
+// Each distinct category needing it is fixed with a single update; failures
+// are logged and the next start tries again.
+func (ar *AuctionRepository) normalizeStoredCategories() {
+	ctx, cancel := context.WithTimeout(context.Background(), ensureIndexesTimeout)
+	defer cancel()
+
+	categories, err := ar.Collection.Distinct(ctx, "category", bson.M{})
+	if err != nil {
+		logger.Error("Error trying to list the stored auction categories", err)
+		return
+	}
+
+	var migrated int64
+	for _, value := range categories {
+		category, ok := value.(string)
+		if !ok {
+			continue
+		}
+		normalized := auction_entity.NormalizeCategory(category)
+		if normalized == category {
+			continue
+		}
+
+		result, err := ar.Collection.UpdateMany(ctx, bson.M{"category": category},
+			bson.M{"$set": bson.M{"category": normalized}, "$inc": bson.M{"version": 1}})
+		if err != nil {
+			logger.Error("Error trying to normalize a stored auction category", err,
+				zap.String("category", category))
+			continue
+		}
+		migrated += result.ModifiedCount
+	}
+
+	if migrated > 0 {
+		if ar.cache != nil {
+			ar.cache.purge()
+		}
+		ar.invalidateCachedCounts()
+		logger.Info("Stored auction categories normalized", zap.Int64("auctions", migrated))
+	}
+}
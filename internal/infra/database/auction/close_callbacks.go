@@ -0,0 +1,214 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const closeCallbackLookupTimeout = 5 * time.Second
+
+// CloseCallback receives an auction once it has been closed.
+type CloseCallback func(auction auction_entity.Auction)
+
+// closeCallbacks holds the callbacks registered with OnClose and
+// OnAnyClose. The zero value is ready to use.
+type closeCallbacks struct {
+	mutex     sync.Mutex
+	byAuction map[string][]CloseCallback
+	any       []CloseCallback
+
+	// fired records, with durable monitors, when the callbacks of each
+	// auction last ran here so the resync does not run them twice
+	fired map[string]time.Time
+}
+
+func (cc *closeCallbacks) onClose(auctionId string, fn CloseCallback) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	if cc.byAuction == nil {
+		cc.byAuction = make(map[string][]CloseCallback)
+	}
+	cc.byAuction[auctionId] = append(cc.byAuction[auctionId], fn)
+}
+
+func (cc *closeCallbacks) onAnyClose(fn CloseCallback) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.any = append(cc.any, fn)
+}
+
+// take returns the callbacks due when auctionId closes, removing the ones
+// registered for that auction alone.
+func (cc *closeCallbacks) take(auctionId string) []CloseCallback {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	callbacks := append([]CloseCallback{}, cc.any...)
+	callbacks = append(callbacks, cc.byAuction[auctionId]...)
+	delete(cc.byAuction, auctionId)
+
+	return callbacks
+}
+
+// forget drops the callbacks of auctions that will never close, such as
+// cancelled ones.
+func (cc *closeCallbacks) forget(auctionIds ...string) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	for _, auctionId := range auctionIds {
+		delete(cc.byAuction, auctionId)
+	}
+}
+
+// registered reports whether any callback is waiting for a close.
+func (cc *closeCallbacks) registered() bool {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	return len(cc.any) > 0 || len(cc.byAuction) > 0
+}
+
+func (cc *closeCallbacks) markFired(auctionId string, at time.Time) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	if cc.fired == nil {
+		cc.fired = make(map[string]time.Time)
+	}
+	cc.fired[auctionId] = at
+}
+
+func (cc *closeCallbacks) hasFired(auctionId string) bool {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	_, ok := cc.fired[auctionId]
+	return ok
+}
+
+// pruneFired forgets the runs recorded before the given time.
+func (cc *closeCallbacks) pruneFired(before time.Time) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	for auctionId, at := range cc.fired {
+		if at.Before(before) {
+			delete(cc.fired, auctionId)
+		}
+	}
+}
+
+// run calls every callback in its own goroutine. A panicking callback is
+// logged and never reaches the monitor that closed the auction.
+func (cc *closeCallbacks) run(callbacks []CloseCallback, auction auction_entity.Auction) {
+	for _, callback := range callbacks {
+		go func(callback CloseCallback) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					logger.Error("Close callback panicked", fmt.Errorf("%v", recovered),
+						zap.String("auction_id", auction.Id))
+				}
+			}()
+
+			callback(auction)
+		}(callback)
+	}
+}
+
+// OnClose registers fn to be called once, in its own goroutine, when the
+// auction closes. It is dropped after firing or when the auction is
+// cancelled instead.
+func (ar *AuctionRepository) OnClose(auctionId string, fn CloseCallback) {
+	ar.closeCallbacks.onClose(auctionId, fn)
+}
+
+// OnAnyClose registers fn to be called, in its own goroutine, whenever an
+// auction closes.
+func (ar *AuctionRepository) OnAnyClose(fn CloseCallback) {
+	ar.closeCallbacks.onAnyClose(fn)
+}
+
+// runCloseCallbacks hands the closed auction to its callbacks in the
+// background, reading it back only when some are registered.
+func (ar *AuctionRepository) runCloseCallbacks(auctionId string) {
+	if ar.durableMonitors {
+		ar.closeCallbacks.markFired(auctionId, ar.clock.Now())
+	}
+
+	callbacks := ar.closeCallbacks.take(auctionId)
+	if len(callbacks) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), closeCallbackLookupTimeout)
+		defer cancel()
+
+		auction, err := ar.findAuctionByIdFromDatabase(ctx, auctionId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error loading closed auction %s for its callbacks", auctionId), err)
+			return
+		}
+
+		ar.closeCallbacks.run(callbacks, *auction)
+	}()
+}
+
+// runClosedElsewhereCallbacks runs the callbacks of the auctions other
+// instances completed since the given time and forgets those of the ones
+// they cancelled. It only reads the database when some callback is
+// registered.
+func (ar *AuctionRepository) runClosedElsewhereCallbacks(ctx context.Context, since time.Time) {
+	if !ar.closeCallbacks.registered() {
+		return
+	}
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{
+		"status":    bson.M{"$in": bson.A{auction_entity.Completed, auction_entity.Cancelled}},
+		"closed_at": bson.M{"$gte": since.Unix()},
+	}, options.Find().SetProjection(bson.M{"_id": 1, "status": 1}))
+	if err != nil {
+		logger.Error("Error trying to find auctions closed by other instances", err)
+		return
+	}
+
+	var closed []struct {
+		Id     string                       `bson:"_id"`
+		Status auction_entity.AuctionStatus `bson:"status"`
+	}
+	if err := cursor.All(ctx, &closed); err != nil {
+		logger.Error("Error trying to decode auctions closed by other instances", err)
+		return
+	}
+
+	for _, auction := range closed {
+		switch {
+		case auction.Status == auction_entity.Cancelled:
+			ar.closeCallbacks.forget(auction.Id)
+		case !ar.closeCallbacks.hasFired(auction.Id):
+			ar.runCloseCallbacks(auction.Id)
+		}
+	}
+}
+
+// OnClose mirrors AuctionRepository.OnClose.
+func (mr *MemoryAuctionRepository) OnClose(auctionId string, fn CloseCallback) {
+	mr.closeCallbacks.onClose(auctionId, fn)
+}
+
+// OnAnyClose mirrors AuctionRepository.OnAnyClose.
+func (mr *MemoryAuctionRepository) OnAnyClose(fn CloseCallback) {
+	mr.closeCallbacks.onAnyClose(fn)
+}
@@ -0,0 +1,196 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCloseCallbacks(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewMemoryAuctionRepository(fakeClock)
+	defer repo.Close()
+	ctx := context.Background()
+
+	first, err := auction_entity.CreateAuction(
+		"First Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, first))
+	second, err := auction_entity.CreateAuction(
+		"Second Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, second))
+
+	closedFirst := make(chan auction_entity.Auction, 1)
+	closedAny := make(chan auction_entity.Auction, 2)
+	repo.OnClose(first.Id, func(auction auction_entity.Auction) { closedFirst <- auction })
+	repo.OnClose(first.Id, func(auction auction_entity.Auction) { panic("callback failure") })
+	repo.OnAnyClose(func(auction auction_entity.Auction) { closedAny <- auction })
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	select {
+	case auction := <-closedFirst:
+		assert.Equal(t, first.Id, auction.Id)
+		assert.Equal(t, auction_entity.Completed, auction.Status)
+	case <-time.After(time.Second):
+		t.Fatal("OnClose callback was not called")
+	}
+
+	// O callback que entra em pânico não derruba o monitor: o segundo leilão também fecha
+	received := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case auction := <-closedAny:
+			assert.Equal(t, auction_entity.Completed, auction.Status)
+			received[auction.Id] = true
+		case <-time.After(time.Second):
+			t.Fatal("OnAnyClose callback was not called")
+		}
+	}
+	assert.Equal(t, map[string]bool{first.Id: true, second.Id: true}, received)
+
+	repo.closeCallbacks.mutex.Lock()
+	assert.Empty(t, repo.closeCallbacks.byAuction)
+	repo.closeCallbacks.mutex.Unlock()
+}
+
+func TestMemoryCloseCallbacksDroppedOnCancel(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	repo.OnClose(auction.Id, func(auction auction_entity.Auction) {})
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Cancelled))
+
+	repo.closeCallbacks.mutex.Lock()
+	assert.Empty(t, repo.closeCallbacks.byAuction)
+	repo.closeCallbacks.mutex.Unlock()
+}
+
+func TestMemoryCloseCallbacksOnManualClose(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	closed := make(chan auction_entity.Auction, 1)
+	closedAny := make(chan auction_entity.Auction, 1)
+	repo.OnClose(auction.Id, func(auction auction_entity.Auction) { closed <- auction })
+	repo.OnAnyClose(func(auction auction_entity.Auction) { closedAny <- auction })
+
+	// Fechar pela atualização de status também dispara os callbacks
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+
+	for _, ch := range []chan auction_entity.Auction{closed, closedAny} {
+		select {
+		case closedAuction := <-ch:
+			assert.Equal(t, auction.Id, closedAuction.Id)
+			assert.Equal(t, auction_entity.Completed, closedAuction.Status)
+		case <-time.After(time.Second):
+			t.Fatal("Close callback was not called")
+		}
+	}
+}
+
+func TestCloseCallbacksOnManualClose(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithClock(clock.NewFakeClock(time.Now())))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+	cancelled, err := auction_entity.CreateAuction(
+		"Other Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, cancelled))
+
+	closed := make(chan auction_entity.Auction, 1)
+	repo.OnClose(auction.Id, func(auction auction_entity.Auction) { closed <- auction })
+	repo.OnClose(cancelled.Id, func(auction auction_entity.Auction) {})
+
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, cancelled.Id, auction_entity.Cancelled))
+
+	select {
+	case closedAuction := <-closed:
+		assert.Equal(t, auction.Id, closedAuction.Id)
+		assert.Equal(t, auction_entity.Completed, closedAuction.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnClose callback was not called")
+	}
+
+	repo.closeCallbacks.mutex.Lock()
+	assert.Empty(t, repo.closeCallbacks.byAuction)
+	repo.closeCallbacks.mutex.Unlock()
+}
+
+func TestCloseCallbacks(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	closed := make(chan auction_entity.Auction, 1)
+	repo.OnClose(auction.Id, func(auction auction_entity.Auction) { panic("callback failure") })
+	repo.OnClose(auction.Id, func(auction auction_entity.Auction) { closed <- auction })
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	select {
+	case closedAuction := <-closed:
+		assert.Equal(t, auction.Id, closedAuction.Id)
+		assert.Equal(t, auction_entity.Completed, closedAuction.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnClose callback was not called")
+	}
+
+	repo.closeCallbacks.mutex.Lock()
+	assert.Empty(t, repo.closeCallbacks.byAuction)
+	repo.closeCallbacks.mutex.Unlock()
+}
@@ -0,0 +1,136 @@
+package auction
+
+import (
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/clock"
+	"go.uber.org/zap"
+)
+
+// CloseEventDelivery decides what closing an auction does when the close
+// events channel is full.
+type CloseEventDelivery string
+
+const (
+	// CloseEventsDrop discards the event, so a slow consumer never delays
+	// closing but may miss closes.
+	CloseEventsDrop CloseEventDelivery = "drop"
+	// CloseEventsBlock waits for the consumer; closing stalls until the
+	// event is received or the repository is closed.
+	CloseEventsBlock CloseEventDelivery = "block"
+	// CloseEventsBlockTimeout waits for the consumer up to a timeout and
+	// then discards the event.
+	CloseEventsBlockTimeout CloseEventDelivery = "block_timeout"
+)
+
+const defaultCloseEventTimeout = time.Second
+
+// CloseEvent is published on CloseEvents whenever an auction is closed by
+// the monitors or by the startup recovery.
+type CloseEvent struct {
+	AuctionId string
+	ClosedAt  time.Time
+}
+
+type closeEventsConfig struct {
+	buffer   int
+	delivery CloseEventDelivery
+	timeout  time.Duration
+}
+
+// WithCloseEvents publishes a CloseEvent per closed auction on a channel
+// of the given buffer size, read through CloseEvents. delivery picks what
+// happens once the buffer is full; unknown values fall back to
+// CloseEventsDrop, and timeout defaults to one second for
+// CloseEventsBlockTimeout.
+func WithCloseEvents(buffer int, delivery CloseEventDelivery, timeout time.Duration) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		if buffer < 0 {
+			buffer = 0
+		}
+		switch delivery {
+		case CloseEventsBlock, CloseEventsBlockTimeout:
+		default:
+			delivery = CloseEventsDrop
+		}
+		if timeout <= 0 {
+			timeout = defaultCloseEventTimeout
+		}
+
+		ar.closeEventsConfig = &closeEventsConfig{buffer: buffer, delivery: delivery, timeout: timeout}
+	}
+}
+
+// CloseEvents returns the channel of close events, or nil unless the
+// repository was built WithCloseEvents.
+func (ar *AuctionRepository) CloseEvents() <-chan CloseEvent {
+	if ar.closeEvents == nil {
+		return nil
+	}
+
+	return ar.closeEvents.events
+}
+
+func (ar *AuctionRepository) publishCloseEvent(auctionId string) {
+	if ar.closeEvents == nil {
+		return
+	}
+
+	ar.closeEvents.publish(CloseEvent{AuctionId: auctionId, ClosedAt: ar.clock.Now().UTC()})
+}
+
+type closeEventPublisher struct {
+	events   chan CloseEvent
+	delivery CloseEventDelivery
+	timeout  time.Duration
+	clock    clock.Clock
+	done     <-chan struct{}
+}
+
+// newCloseEventPublisher builds the publisher of a close events channel;
+// blocked deliveries give up once done is closed.
+func newCloseEventPublisher(
+	config closeEventsConfig, c clock.Clock, done <-chan struct{}) *closeEventPublisher {
+	return &closeEventPublisher{
+		events:   make(chan CloseEvent, config.buffer),
+		delivery: config.delivery,
+		timeout:  config.timeout,
+		clock:    c,
+		done:     done,
+	}
+}
+
+// publish delivers event following the configured strategy and reports
+// whether the consumer got it.
+func (cp *closeEventPublisher) publish(event CloseEvent) bool {
+	switch cp.delivery {
+	case CloseEventsBlock:
+		select {
+		case cp.events <- event:
+			return true
+		case <-cp.done:
+		}
+	case CloseEventsBlockTimeout:
+		timer := cp.clock.NewTimer(cp.timeout)
+		defer stopTimer(timer)
+
+		select {
+		case cp.events <- event:
+			return true
+		case <-timer.C():
+		case <-cp.done:
+		}
+	default:
+		select {
+		case cp.events <- event:
+			return true
+		default:
+		}
+	}
+
+	logger.Warn("Close event not delivered",
+		zap.String("auction_id", event.AuctionId),
+		zap.String("delivery", string(cp.delivery)))
+	return false
+}
@@ -0,0 +1,183 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowConsumer reads count close events one at a time, waiting delay
+// before each read, and sends the ids received once done.
+func slowConsumer(events <-chan CloseEvent, count int, delay time.Duration) <-chan []string {
+	received := make(chan []string, 1)
+	go func() {
+		var ids []string
+		for i := 0; i < count; i++ {
+			time.Sleep(delay)
+			ids = append(ids, (<-events).AuctionId)
+		}
+		received <- ids
+	}()
+
+	return received
+}
+
+func TestCloseEventDelivery(t *testing.T) {
+	events := func(n int) []CloseEvent {
+		result := make([]CloseEvent, 0, n)
+		for i := 0; i < n; i++ {
+			result = append(result, CloseEvent{AuctionId: fmt.Sprintf("auction-%d", i)})
+		}
+		return result
+	}
+
+	t.Run("drop discards events once the buffer is full", func(t *testing.T) {
+		publisher := newCloseEventPublisher(
+			closeEventsConfig{buffer: 1, delivery: CloseEventsDrop}, clock.NewRealClock(), nil)
+
+		var delivered int
+		for _, event := range events(3) {
+			if publisher.publish(event) {
+				delivered++
+			}
+		}
+		assert.Equal(t, 1, delivered)
+
+		// O consumidor lento recebe apenas o evento que coube no buffer
+		assert.Equal(t, []string{"auction-0"}, <-slowConsumer(publisher.events, 1, 10*time.Millisecond))
+	})
+
+	t.Run("block waits for a slow consumer", func(t *testing.T) {
+		publisher := newCloseEventPublisher(
+			closeEventsConfig{buffer: 1, delivery: CloseEventsBlock}, clock.NewRealClock(), nil)
+		received := slowConsumer(publisher.events, 3, 10*time.Millisecond)
+
+		for _, event := range events(3) {
+			assert.True(t, publisher.publish(event))
+		}
+		assert.Equal(t, []string{"auction-0", "auction-1", "auction-2"}, <-received)
+	})
+
+	t.Run("block gives up when the repository is closed", func(t *testing.T) {
+		done := make(chan struct{})
+		publisher := newCloseEventPublisher(
+			closeEventsConfig{buffer: 0, delivery: CloseEventsBlock}, clock.NewRealClock(), done)
+
+		published := make(chan bool)
+		go func() { published <- publisher.publish(CloseEvent{AuctionId: "auction-0"}) }()
+		close(done)
+		assert.False(t, <-published)
+	})
+
+	t.Run("block_timeout waits for a slow consumer within the timeout", func(t *testing.T) {
+		publisher := newCloseEventPublisher(
+			closeEventsConfig{buffer: 1, delivery: CloseEventsBlockTimeout, timeout: time.Second},
+			clock.NewRealClock(), nil)
+		received := slowConsumer(publisher.events, 3, 10*time.Millisecond)
+
+		for _, event := range events(3) {
+			assert.True(t, publisher.publish(event))
+		}
+		assert.Equal(t, []string{"auction-0", "auction-1", "auction-2"}, <-received)
+	})
+
+	t.Run("block_timeout discards the event after the timeout", func(t *testing.T) {
+		fakeClock := clock.NewFakeClock(time.Now())
+		publisher := newCloseEventPublisher(
+			closeEventsConfig{buffer: 1, delivery: CloseEventsBlockTimeout, timeout: time.Second},
+			fakeClock, nil)
+		assert.True(t, publisher.publish(CloseEvent{AuctionId: "auction-0"}))
+
+		// Com o buffer cheio e nenhum consumidor, a entrega expira no timeout
+		published := make(chan bool)
+		go func() { published <- publisher.publish(CloseEvent{AuctionId: "auction-1"}) }()
+		fakeClock.BlockUntil(1)
+		fakeClock.Advance(time.Second)
+		assert.False(t, <-published)
+		assert.Len(t, publisher.events, 1)
+	})
+}
+
+func TestWithCloseEventsPublishesAutoClose(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock), WithCloseEvents(1, CloseEventsBlock, 0))
+	defer repo.Close()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	select {
+	case event := <-repo.CloseEvents():
+		assert.Equal(t, auction.Id, event.AuctionId)
+		assert.Equal(t, fakeClock.Now().UTC(), event.ClosedAt)
+	case <-time.After(time.Second):
+		t.Fatal("evento de fechamento não recebido")
+	}
+}
+
+func TestCloseEventsFollowIdOrderForSameEndTime(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	// O relógio parado dá o mesmo end_time a todo o lote
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock), WithCloseEvents(10, CloseEventsBlock, 0))
+	defer repo.Close()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		auction, err := auction_entity.CreateAuction(
+			fmt.Sprintf("Product %d", i), "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		ids = append(ids, auction.Id)
+	}
+	sort.Strings(ids)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	var received []string
+	for range ids {
+		select {
+		case event := <-repo.CloseEvents():
+			received = append(received, event.AuctionId)
+		case <-time.After(5 * time.Second):
+			t.Fatal("evento de fechamento não recebido")
+		}
+	}
+	assert.Equal(t, ids, received)
+}
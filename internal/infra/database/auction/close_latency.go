@@ -0,0 +1,75 @@
+package auction
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AverageCloseLatency averages closed_at - end_time over the auctions
+// completed since the given time, which shows how far the monitors lag
+// behind. Auctions closed before their end_time (manual closes) are left
+// out; with no auction to average it returns zero.
+func (ar *AuctionRepository) AverageCloseLatency(
+	ctx context.Context, since time.Time) (time.Duration, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"status":    auction_entity.Completed,
+			"closed_at": bson.M{"$gte": since.Unix()},
+			"$expr":     bson.M{"$gte": bson.A{"$closed_at", "$end_time"}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"latency": bson.M{"$avg": bson.M{"$subtract": bson.A{"$closed_at", "$end_time"}}},
+		}}},
+	}
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to aggregate auction close latency", err)
+		return 0, internal_error.NewInternalServerError("Error trying to compute auction close latency")
+	}
+
+	var groups []struct {
+		Latency float64 `bson:"latency"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error("Error trying to decode auction close latency", err)
+		return 0, internal_error.NewInternalServerError("Error trying to compute auction close latency")
+	}
+
+	if len(groups) == 0 {
+		return 0, nil
+	}
+	return time.Duration(groups[0].Latency * float64(time.Second)), nil
+}
+
+// AverageCloseLatency mirrors AuctionRepository.AverageCloseLatency.
+func (mr *MemoryAuctionRepository) AverageCloseLatency(
+	ctx context.Context, since time.Time) (time.Duration, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	var total time.Duration
+	var closed int64
+	for _, auction := range mr.auctions {
+		if auction.Status != auction_entity.Completed ||
+			auction.ClosedAt.Before(since) || auction.ClosedAt.Before(auction.EndTime) {
+			continue
+		}
+
+		total += auction.ClosedAt.Sub(auction.EndTime)
+		closed++
+	}
+
+	if closed == 0 {
+		return 0, nil
+	}
+	return total / time.Duration(closed), nil
+}
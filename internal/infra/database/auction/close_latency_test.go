@@ -0,0 +1,72 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAverageCloseLatency(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	seed := func(id string, status auction_entity.AuctionStatus, endTime, closedAt time.Time) interface{} {
+		return AuctionEntityMongo{Id: id, ProductName: "Latency Test Product", Category: "Electronics",
+			Status: status, Timestamp: endTime.Add(-time.Hour).Unix(),
+			EndTime: endTime.Unix(), ClosedAt: closedAt.Unix()}
+	}
+	_, err := db.Collection("auctions").InsertMany(ctx, []interface{}{
+		seed("two-seconds", auction_entity.Completed, now.Add(-time.Hour), now.Add(-time.Hour+2*time.Second)),
+		seed("four-seconds", auction_entity.Completed, now.Add(-time.Hour), now.Add(-time.Hour+4*time.Second)),
+		// Fechado manualmente antes do fim, fora da média
+		seed("manual", auction_entity.Completed, now.Add(time.Hour), now.Add(-time.Minute)),
+		// Cancelado e fechado antes do período, fora da média
+		seed("cancelled", auction_entity.Cancelled, now.Add(-time.Hour), now.Add(-time.Hour+time.Minute)),
+		seed("old", auction_entity.Completed, now.Add(-48*time.Hour), now.Add(-48*time.Hour+time.Minute)),
+	})
+	assert.Nil(t, err)
+
+	repo := NewAuctionRepository(db, WithClock(clock.NewFakeClock(now)))
+	defer repo.Close()
+
+	latency, latencyErr := repo.AverageCloseLatency(ctx, now.Add(-24*time.Hour))
+	assert.Nil(t, latencyErr)
+	assert.Equal(t, 3*time.Second, latency)
+
+	latency, latencyErr = repo.AverageCloseLatency(ctx, now)
+	assert.Nil(t, latencyErr)
+	assert.Equal(t, time.Duration(0), latency)
+}
+
+func TestMemoryAverageCloseLatency(t *testing.T) {
+	now := time.Now().UTC()
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(now))
+	defer repo.Close()
+
+	seed := func(id string, status auction_entity.AuctionStatus, endTime, closedAt time.Time) {
+		repo.auctions[id] = auction_entity.Auction{Id: id, Status: status, EndTime: endTime, ClosedAt: closedAt}
+	}
+	seed("one-second", auction_entity.Completed, now.Add(-time.Hour), now.Add(-time.Hour+time.Second))
+	seed("five-seconds", auction_entity.Completed, now.Add(-time.Hour), now.Add(-time.Hour+5*time.Second))
+	seed("manual", auction_entity.Completed, now.Add(time.Hour), now.Add(-time.Minute))
+	seed("cancelled", auction_entity.Cancelled, now.Add(-time.Hour), now.Add(-time.Hour+time.Minute))
+	seed("old", auction_entity.Completed, now.Add(-48*time.Hour), now.Add(-48*time.Hour+time.Minute))
+
+	latency, err := repo.AverageCloseLatency(context.Background(), now.Add(-24*time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 3*time.Second, latency)
+
+	latency, err = repo.AverageCloseLatency(context.Background(), now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), latency)
+}
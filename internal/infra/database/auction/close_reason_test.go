@@ -0,0 +1,89 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCloseReasonOnManualClose(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	stored, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Empty(t, stored.CloseReason)
+
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.CloseReasonManual, stored.CloseReason)
+
+	var history StatusHistoryEntityMongo
+	assert.Nil(t, repo.historyCollection.FindOne(ctx, bson.M{"auction_id": auction.Id}).Decode(&history))
+	assert.Equal(t, statusReasonUpdate, history.Reason)
+	assert.Equal(t, auction_entity.CloseReasonManual, history.CloseReason)
+}
+
+func TestCloseReasonOnRecoveryLimit(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	seed := func(id string, endTime time.Time) interface{} {
+		return AuctionEntityMongo{Id: id, ProductName: "Recovery Test Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: endTime.Unix()}
+	}
+	// Com limite de um leilão, o que termina por último é fechado na recuperação
+	_, err := db.Collection("auctions").InsertMany(ctx, []interface{}{
+		seed("recovered", now.Add(time.Hour)),
+		seed("over-limit", now.Add(2*time.Hour)),
+	})
+	assert.Nil(t, err)
+
+	repo := NewAuctionRepository(db,
+		WithClock(clock.NewFakeClock(now)),
+		WithConfig(Config{MaxConcurrentAuctions: 1}))
+	defer repo.Close()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	recovered, findErr := repo.FindAuctionById(ctx, "recovered")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Active, recovered.Status)
+	assert.Empty(t, recovered.CloseReason)
+
+	overLimit, findErr := repo.FindAuctionById(ctx, "over-limit")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Completed, overLimit.Status)
+	assert.Equal(t, auction_entity.CloseReasonRecoveryLimit, overLimit.CloseReason)
+
+	var history StatusHistoryEntityMongo
+	assert.Nil(t, repo.historyCollection.FindOne(ctx, bson.M{"auction_id": "over-limit"}).Decode(&history))
+	assert.Equal(t, statusReasonRecoveryLimit, history.Reason)
+	assert.Equal(t, auction_entity.CloseReasonRecoveryLimit, history.CloseReason)
+}
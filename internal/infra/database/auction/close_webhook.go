@@ -0,0 +1,140 @@
+package auction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	closeWebhookTimeout     = 5 * time.Second
+	closeWebhookMaxAttempts = 3
+	closeWebhookBackoff     = 500 * time.Millisecond
+)
+
+var closeWebhookClient = &http.Client{Timeout: closeWebhookTimeout}
+
+type closeWebhookPayload struct {
+	AuctionId string                       `json:"auction_id"`
+	Status    auction_entity.AuctionStatus `json:"status"`
+	Winner    *closeWebhookWinner          `json:"winner"`
+	ClosedAt  int64                        `json:"closed_at"`
+}
+
+type closeWebhookWinner struct {
+	BidId  string           `json:"bid_id"`
+	UserId string           `json:"user_id"`
+	Amount bid_entity.Cents `json:"amount"`
+}
+
+// notifyAuctionClosed publishes the close event, if enabled, runs the close
+// callbacks and posts the closed auction to CLOSE_WEBHOOK_URL in the
+// background. Webhook delivery failures are only logged so closing never
+// blocks on it.
+func (ar *AuctionRepository) notifyAuctionClosed(auctionId string) {
+	ar.publishCloseEvent(auctionId)
+	ar.runCloseCallbacks(auctionId)
+
+	webhookURL := os.Getenv("CLOSE_WEBHOOK_URL")
+	if webhookURL == "" {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), closeWebhookTimeout)
+		payload, err := ar.buildCloseWebhookPayload(ctx, auctionId)
+		cancel()
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error building close webhook payload for auction %s", auctionId), err)
+			return
+		}
+
+		if err := postCloseWebhook(webhookURL, payload); err != nil {
+			logger.Error(fmt.Sprintf("Error delivering close webhook for auction %s", auctionId), err)
+		}
+	}()
+}
+
+func (ar *AuctionRepository) buildCloseWebhookPayload(
+	ctx context.Context, auctionId string) (*closeWebhookPayload, error) {
+	auction, findErr := ar.FindAuctionById(ctx, auctionId)
+	if findErr != nil {
+		return nil, findErr
+	}
+
+	payload := &closeWebhookPayload{
+		AuctionId: auction.Id,
+		Status:    auction.Status,
+		ClosedAt:  auction.ClosedAt.Unix(),
+	}
+
+	// Report the winner stored at close, not the current highest bid, which
+	// a retraction or a tie could make differ
+	if auction.WinnerBidId == "" {
+		return payload, nil
+	}
+
+	var winningBid struct {
+		Id     string           `bson:"_id"`
+		UserId string           `bson:"user_id"`
+		Amount bid_entity.Cents `bson:"amount_cents"`
+	}
+	err := ar.bidsCollection.FindOne(ctx, bson.M{"_id": auction.WinnerBidId}).Decode(&winningBid)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	payload.Winner = &closeWebhookWinner{BidId: auction.WinnerBidId}
+	if err == nil {
+		payload.Winner.UserId = winningBid.UserId
+		payload.Winner.Amount = winningBid.Amount
+	}
+
+	return payload, nil
+}
+
+func postCloseWebhook(webhookURL string, payload *closeWebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = sendCloseWebhook(webhookURL, body)
+		if err == nil || attempt == closeWebhookMaxAttempts {
+			return err
+		}
+
+		logger.Info("Retrying close webhook",
+			zap.String("auction_id", payload.AuctionId),
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+		time.Sleep(closeWebhookBackoff * time.Duration(attempt))
+	}
+}
+
+func sendCloseWebhook(webhookURL string, body []byte) error {
+	response, err := closeWebhookClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("close webhook returned status %d", response.StatusCode)
+	}
+
+	return nil
+}
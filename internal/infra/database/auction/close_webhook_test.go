@@ -0,0 +1,110 @@
+package auction
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCloseWebhookAfterAutoClose(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	payloads := make(chan closeWebhookPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload closeWebhookPayload
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		payloads <- payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	os.Setenv("AUCTION_INTERVAL", "1s")
+	os.Setenv("CLOSE_WEBHOOK_URL", server.URL)
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	defer os.Unsetenv("CLOSE_WEBHOOK_URL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+
+	_, insertErr := db.Collection("bids").InsertMany(ctx, []interface{}{
+		bson.M{"_id": "webhook-bid-low", "user_id": "user-low", "auction_id": auction.Id, "amount_cents": 10000},
+		bson.M{"_id": "webhook-bid-high", "user_id": "user-high", "auction_id": auction.Id, "amount_cents": 25000},
+	})
+	assert.Nil(t, insertErr)
+
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	select {
+	case payload := <-payloads:
+		assert.Equal(t, auction.Id, payload.AuctionId)
+		assert.Equal(t, auction_entity.Completed, payload.Status)
+		assert.NotZero(t, payload.ClosedAt)
+		if assert.NotNil(t, payload.Winner) {
+			assert.Equal(t, "webhook-bid-high", payload.Winner.BidId)
+			assert.Equal(t, "user-high", payload.Winner.UserId)
+			assert.Equal(t, bid_entity.Cents(25000), payload.Winner.Amount)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called after the auction closed")
+	}
+}
+
+func TestCloseWebhookPayloadUsesPersistedWinner(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "auction-won", ProductName: "Product 1", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix(), WinnerBidId: "bid-winner"},
+		AuctionEntityMongo{Id: "auction-no-winner", ProductName: "Product 2", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()},
+	})
+	assert.Nil(t, err)
+
+	// Um lance maior que chegou depois do fechamento não muda o vencedor
+	_, err = db.Collection("bids").InsertMany(ctx, []interface{}{
+		bson.M{"_id": "bid-winner", "user_id": "user-winner", "auction_id": "auction-won", "amount_cents": 10000},
+		bson.M{"_id": "bid-late", "user_id": "user-late", "auction_id": "auction-won", "amount_cents": 25000},
+		bson.M{"_id": "bid-orphan", "user_id": "user-late", "auction_id": "auction-no-winner", "amount_cents": 5000},
+	})
+	assert.Nil(t, err)
+
+	payload, payloadErr := repo.buildCloseWebhookPayload(ctx, "auction-won")
+	assert.Nil(t, payloadErr)
+	if assert.NotNil(t, payload.Winner) {
+		assert.Equal(t, "bid-winner", payload.Winner.BidId)
+		assert.Equal(t, "user-winner", payload.Winner.UserId)
+		assert.Equal(t, bid_entity.Cents(10000), payload.Winner.Amount)
+	}
+
+	payload, payloadErr = repo.buildCloseWebhookPayload(ctx, "auction-no-winner")
+	assert.Nil(t, payloadErr)
+	assert.Nil(t, payload.Winner)
+}
@@ -0,0 +1,24 @@
+package auction
+
+import (
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"go.uber.org/zap"
+)
+
+// SetMaxConcurrentAuctions replaces the concurrency limit without a
+// restart. Lowering it below the active count only refuses new auctions;
+// the active ones run until they end.
+func (ar *AuctionRepository) SetMaxConcurrentAuctions(n int64) *internal_error.InternalError {
+	if n <= 0 {
+		return internal_error.NewBadRequestError("The concurrency limit must be greater than zero")
+	}
+
+	previous := ar.getMaxConcurrentAuctions()
+	ar.maxConcurrentAuctions.Store(n)
+	logger.Info("Concurrency limit changed",
+		zap.Int64("previous", previous),
+		zap.Int64("max_concurrent_auctions", n))
+
+	return nil
+}
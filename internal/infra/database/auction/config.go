@@ -0,0 +1,239 @@
+package auction
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/env"
+	"github.com/danielencestari/lab03/configuration/logger"
+
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAuctionInterval             = 5 * time.Minute
+	defaultMaxConcurrentAuctions int64 = 50
+	defaultPurgeTimeout                = time.Minute
+	defaultMaxRemainingTime            = 24 * time.Hour
+)
+
+// Config holds the tunables of an AuctionRepository. NewAuctionRepository
+// fills a zero field from its environment variable, read once, and the
+// repository falls back to the built-in default past that.
+type Config struct {
+	// AuctionInterval is how long new auctions stay open (AUCTION_INTERVAL,
+	// default 5m)
+	AuctionInterval time.Duration
+
+	// MaxConcurrentAuctions caps the monitored auctions
+	// (MAX_CONCURRENT_AUCTIONS, default 50)
+	MaxConcurrentAuctions int64
+
+	// CategoryLimits gives categories their own cap instead of the global
+	// one, keyed case-insensitively (AUCTION_LIMITS="electronics:100,art:20")
+	CategoryLimits map[string]int64
+
+	// MaxActivePerOwner caps the active auctions of a single owner
+	// (MAX_ACTIVE_PER_OWNER, default unlimited)
+	MaxActivePerOwner int64
+
+	// MonitorWorkers sizes the auto-close pool (MONITOR_WORKERS, default 100)
+	MonitorWorkers int
+
+	// PurgeInterval and PurgeRetention drive the periodic purge, disabled
+	// unless both are set; PurgeTimeout bounds each run (default 1m)
+	PurgeInterval  time.Duration
+	PurgeRetention time.Duration
+	PurgeTimeout   time.Duration
+
+	// MaxRemainingTime is the longest an auction recovered on startup may
+	// still run; anything beyond it points at clock skew and the auction is
+	// closed instead. It never goes below the auction duration
+	// (MAX_REMAINING_TIME, default 24h)
+	MaxRemainingTime time.Duration
+
+	// RecoveryOverLimit decides what recovery does with active auctions
+	// beyond the concurrency limit: close, keep or skip
+	// (RECOVERY_OVER_LIMIT, default close)
+	RecoveryOverLimit string
+
+	// CloseGrace delays the auto-close past end_time, which clients still
+	// see unchanged (AUCTION_CLOSE_GRACE, default 0)
+	CloseGrace time.Duration
+
+	// CompressDescriptions gzips descriptions on write; searches then match
+	// product names only (DESCRIPTION_COMPRESSION, default false)
+	CompressDescriptions bool
+
+	// SchemaValidation installs a $jsonSchema validator on the auctions
+	// collection at startup (AUCTION_SCHEMA_VALIDATION, default false)
+	SchemaValidation bool
+
+	// WriteConcern applies to every write on the auctions collection
+	// (MONGODB_WRITE_CONCERN_W, _J and _WTIMEOUT, default: driver default)
+	WriteConcern *writeconcern.WriteConcern
+}
+
+// NewConfig parses the repository tunables out of the loaded environment
+// values. Unset or invalid values are left zero, so the defaults apply.
+func NewConfig(values *env.Config) Config {
+	config := Config{
+		AuctionInterval: parsePositiveDuration(values.AuctionInterval),
+		MonitorWorkers:  parseMonitorWorkers(values.MonitorWorkers),
+		PurgeInterval:   parsePositiveDuration(values.PurgeInterval),
+		PurgeRetention:  parsePositiveDuration(values.PurgeRetention),
+
+		MaxRemainingTime: parsePositiveDuration(values.MaxRemainingTime),
+		CloseGrace:       parsePositiveDuration(values.AuctionCloseGrace),
+		WriteConcern: parseWriteConcern(
+			values.WriteConcernW, values.WriteConcernJ, values.WriteConcernWTimeout),
+
+		CategoryLimits:    parseCategoryLimits(values.AuctionLimits),
+		RecoveryOverLimit: values.RecoveryOverLimit,
+	}
+
+	// A zero or negative interval would close auctions right away with an
+	// end_time before their timestamp
+	if values.AuctionInterval != "" && config.AuctionInterval == 0 {
+		logger.Warn("Invalid AUCTION_INTERVAL, using the default",
+			zap.String("auction_interval", values.AuctionInterval),
+			zap.Duration("default", defaultAuctionInterval))
+	}
+
+	if compress, err := strconv.ParseBool(values.DescriptionCompression); err == nil {
+		config.CompressDescriptions = compress
+	}
+
+	if validate, err := strconv.ParseBool(values.AuctionSchemaValidation); err == nil {
+		config.SchemaValidation = validate
+	}
+
+	if maxAuctions, err := strconv.ParseInt(values.MaxConcurrentAuctions, 10, 64); err == nil &&
+		maxAuctions > 0 {
+		config.MaxConcurrentAuctions = maxAuctions
+	}
+
+	if maxPerOwner, err := strconv.ParseInt(values.MaxActivePerOwner, 10, 64); err == nil &&
+		maxPerOwner > 0 {
+		config.MaxActivePerOwner = maxPerOwner
+	}
+
+	return config
+}
+
+// ConfigFromEnv reads the repository tunables from the process
+// environment.
+func ConfigFromEnv() Config {
+	return NewConfig(env.FromEnv())
+}
+
+// orElse returns config with its zero fields taken from fallback.
+func (c Config) orElse(fallback Config) Config {
+	if c.AuctionInterval <= 0 {
+		c.AuctionInterval = fallback.AuctionInterval
+	}
+	if c.MaxConcurrentAuctions <= 0 {
+		c.MaxConcurrentAuctions = fallback.MaxConcurrentAuctions
+	}
+	if c.CategoryLimits == nil {
+		c.CategoryLimits = fallback.CategoryLimits
+	}
+	if c.MaxActivePerOwner <= 0 {
+		c.MaxActivePerOwner = fallback.MaxActivePerOwner
+	}
+	if c.MonitorWorkers <= 0 {
+		c.MonitorWorkers = fallback.MonitorWorkers
+	}
+	if c.MaxRemainingTime <= 0 {
+		c.MaxRemainingTime = fallback.MaxRemainingTime
+	}
+	if c.RecoveryOverLimit == "" {
+		c.RecoveryOverLimit = fallback.RecoveryOverLimit
+	}
+	if c.CloseGrace <= 0 {
+		c.CloseGrace = fallback.CloseGrace
+	}
+	if c.WriteConcern == nil {
+		c.WriteConcern = fallback.WriteConcern
+	}
+	c.CompressDescriptions = c.CompressDescriptions || fallback.CompressDescriptions
+	c.SchemaValidation = c.SchemaValidation || fallback.SchemaValidation
+
+	return c
+}
+
+// WithConfig replaces the repository tunables; see Config for how zero
+// fields are resolved.
+func WithConfig(config Config) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		ar.config = config
+	}
+}
+
+func (ar *AuctionRepository) getAuctionDuration() time.Duration {
+	if ar.config.AuctionInterval > 0 {
+		return ar.config.AuctionInterval
+	}
+	return defaultAuctionInterval
+}
+
+func (ar *AuctionRepository) getMaxConcurrentAuctions() int64 {
+	if maxAuctions := ar.maxConcurrentAuctions.Load(); maxAuctions > 0 {
+		return maxAuctions
+	}
+
+	if ar.config.MaxConcurrentAuctions > 0 {
+		return ar.config.MaxConcurrentAuctions
+	}
+	return defaultMaxConcurrentAuctions
+}
+
+func (ar *AuctionRepository) getMonitorWorkers() int {
+	if ar.config.MonitorWorkers > 0 {
+		return ar.config.MonitorWorkers
+	}
+	return defaultMonitorWorkers
+}
+
+func (ar *AuctionRepository) purgeEnabled() bool {
+	return ar.config.PurgeInterval > 0 && ar.config.PurgeRetention > 0
+}
+
+func (ar *AuctionRepository) getPurgeTimeout() time.Duration {
+	if ar.config.PurgeTimeout > 0 {
+		return ar.config.PurgeTimeout
+	}
+	return defaultPurgeTimeout
+}
+
+// getMaxRemainingTime is floored at the auction duration, since an auction
+// created just before a restart legitimately has that much time left.
+func (ar *AuctionRepository) getMaxRemainingTime() time.Duration {
+	maxRemaining := ar.config.MaxRemainingTime
+	if maxRemaining <= 0 {
+		maxRemaining = defaultMaxRemainingTime
+	}
+
+	if duration := ar.getAuctionDuration(); duration > maxRemaining {
+		return duration
+	}
+	return maxRemaining
+}
+
+func (ar *AuctionRepository) getCloseGrace() time.Duration {
+	return ar.config.CloseGrace
+}
+
+// closeTime is when the monitor of an auction ending at endTime fires.
+func (ar *AuctionRepository) closeTime(endTime time.Time) time.Time {
+	return endTime.Add(ar.getCloseGrace())
+}
+
+func parsePositiveDuration(value string) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0
+	}
+	return duration
+}
@@ -0,0 +1,156 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func TestConfigFallsBackToEnv(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "10m")
+	os.Setenv("MAX_CONCURRENT_AUCTIONS", "7")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	defer os.Unsetenv("MAX_CONCURRENT_AUCTIONS")
+
+	repo := &AuctionRepository{config: Config{}.orElse(ConfigFromEnv())}
+	assert.Equal(t, 10*time.Minute, repo.getAuctionDuration())
+	assert.Equal(t, int64(7), repo.getMaxConcurrentAuctions())
+
+	// Explicit values win over the environment
+	explicit := Config{AuctionInterval: time.Hour, MaxConcurrentAuctions: 3}
+	repo = &AuctionRepository{config: explicit.orElse(ConfigFromEnv())}
+	assert.Equal(t, time.Hour, repo.getAuctionDuration())
+	assert.Equal(t, int64(3), repo.getMaxConcurrentAuctions())
+
+	os.Unsetenv("AUCTION_INTERVAL")
+	os.Unsetenv("MAX_CONCURRENT_AUCTIONS")
+	repo = &AuctionRepository{config: Config{}.orElse(ConfigFromEnv())}
+	assert.Equal(t, 5*time.Minute, repo.getAuctionDuration())
+	assert.Equal(t, int64(50), repo.getMaxConcurrentAuctions())
+	assert.Equal(t, time.Minute, repo.getPurgeTimeout())
+}
+
+func TestMaxRemainingTimeCoversTheAuctionDuration(t *testing.T) {
+	repo := &AuctionRepository{config: Config{AuctionInterval: time.Hour}}
+	assert.Equal(t, 24*time.Hour, repo.getMaxRemainingTime())
+
+	// Leilões mais longos que o limite não são confundidos com relógio desajustado
+	repo = &AuctionRepository{config: Config{AuctionInterval: 72 * time.Hour}}
+	assert.Equal(t, 72*time.Hour, repo.getMaxRemainingTime())
+
+	repo = &AuctionRepository{config: Config{AuctionInterval: 72 * time.Hour, MaxRemainingTime: 96 * time.Hour}}
+	assert.Equal(t, 96*time.Hour, repo.getMaxRemainingTime())
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "90s")
+	os.Setenv("MAX_CONCURRENT_AUCTIONS", "invalid")
+	os.Setenv("PURGE_INTERVAL", "24h")
+	os.Setenv("PURGE_RETENTION", "720h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	defer os.Unsetenv("MAX_CONCURRENT_AUCTIONS")
+	defer os.Unsetenv("PURGE_INTERVAL")
+	defer os.Unsetenv("PURGE_RETENTION")
+
+	config := ConfigFromEnv()
+	assert.Equal(t, 90*time.Second, config.AuctionInterval)
+	assert.Equal(t, int64(0), config.MaxConcurrentAuctions)
+	assert.Equal(t, 24*time.Hour, config.PurgeInterval)
+	assert.Equal(t, 720*time.Hour, config.PurgeRetention)
+}
+
+func TestCreateAuctionWithExplicitConfig(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	// O ambiente diz outra coisa; a configuração explícita prevalece
+	os.Setenv("AUCTION_INTERVAL", "1s")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db,
+		WithClock(clock.NewFakeClock(time.Now())),
+		WithConfig(Config{AuctionInterval: time.Hour, MaxConcurrentAuctions: 2, MonitorWorkers: 3}))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	assert.Equal(t, 3, repo.monitors.Workers())
+
+	var first *auction_entity.Auction
+	for i := 0; i < 2; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		if first == nil {
+			first = auction
+		}
+	}
+
+	var stored AuctionEntityMongo
+	assert.Nil(t, repo.Collection.FindOne(ctx, bson.M{"_id": first.Id}).Decode(&stored))
+	assert.Equal(t, int64(time.Hour.Seconds()), stored.EndTime-stored.Timestamp)
+
+	third, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.NotNil(t, repo.CreateAuction(ctx, third))
+}
+
+func TestWriteConcernFromEnv(t *testing.T) {
+	assert.Nil(t, ConfigFromEnv().WriteConcern)
+	assert.Nil(t, (&AuctionRepository{}).collectionOptions())
+
+	os.Setenv("MONGODB_WRITE_CONCERN_W", "majority")
+	os.Setenv("MONGODB_WRITE_CONCERN_J", "true")
+	os.Setenv("MONGODB_WRITE_CONCERN_WTIMEOUT", "5s")
+	defer os.Unsetenv("MONGODB_WRITE_CONCERN_W")
+	defer os.Unsetenv("MONGODB_WRITE_CONCERN_J")
+	defer os.Unsetenv("MONGODB_WRITE_CONCERN_WTIMEOUT")
+
+	writeConcern := ConfigFromEnv().WriteConcern
+	if assert.NotNil(t, writeConcern) {
+		assert.Equal(t, "majority", writeConcern.W)
+		assert.True(t, *writeConcern.Journal)
+		assert.Equal(t, 5*time.Second, writeConcern.WTimeout)
+	}
+
+	os.Setenv("MONGODB_WRITE_CONCERN_W", "2")
+	assert.Equal(t, 2, ConfigFromEnv().WriteConcern.W)
+
+	// A configuração explícita prevalece sobre o ambiente
+	journal := false
+	explicit := &writeconcern.WriteConcern{W: 1, Journal: &journal}
+	repo := &AuctionRepository{config: Config{WriteConcern: explicit}}
+	assert.Same(t, explicit, repo.collectionOptions().WriteConcern)
+}
+
+func TestCreateAuctionWithWriteConcern(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithConfig(Config{WriteConcern: writeconcern.W1()}))
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+}
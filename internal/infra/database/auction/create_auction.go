@@ -2,40 +2,215 @@ package auction
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/danielencestari/lab03/configuration/database/mongodb"
 	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/clock"
 	"github.com/danielencestari/lab03/internal/entity/auction_entity"
 	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/metrics"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
 )
 
+// maxDescriptionLength is the hard cap on stored descriptions, enforced
+// here so callers that bypass the use case can't write oversized documents.
+const maxDescriptionLength = 4000
+
 type AuctionEntityMongo struct {
 	Id          string                          `bson:"_id"`
 	ProductName string                          `bson:"product_name"`
 	Category    string                          `bson:"category"`
+	Subcategory string                          `bson:"subcategory,omitempty"`
 	Description string                          `bson:"description"`
 	Condition   auction_entity.ProductCondition `bson:"condition"`
 	Status      auction_entity.AuctionStatus    `bson:"status"`
+	ImageURLs   []string                        `bson:"image_urls,omitempty"`
+	OwnerId     string                          `bson:"owner_id,omitempty"`
 	Timestamp   int64                           `bson:"timestamp"`
 	EndTime     int64                           `bson:"end_time"`
+	ClosedAt    int64                           `bson:"closed_at,omitempty"`
+	WinnerBidId string                          `bson:"winner_bid_id,omitempty"`
+	Version     int64                           `bson:"version"`
+	BidCount    int64                           `bson:"bid_count"`
+	CloseReason string                          `bson:"close_reason,omitempty"`
+
+	// PausedRemaining is the time left in seconds, set while Paused
+	PausedRemaining int64 `bson:"paused_remaining,omitempty"`
+
+	// Compressed flags a gzip and base64 encoded description
+	Compressed bool `bson:"compressed,omitempty"`
+
+	IdempotencyKey string `bson:"idempotency_key,omitempty"`
 }
 
+var _ auction_entity.AuctionRepositoryInterface = (*AuctionRepository)(nil)
+
 type AuctionRepository struct {
 	Collection          *mongo.Collection
-	activeAuctionsCount int64
+	bidsCollection      *mongo.Collection
+	historyCollection   *mongo.Collection
 	auctionCountMutex   *sync.Mutex
+	closed              bool                // guarded by auctionCountMutex
+	monitoredAuctions   map[string]string   // auction id to normalized category
+	reservedSlots       map[string]struct{} // slots whose write is still in flight
+	ownerReservations   map[string]int64    // owner id to writes still in flight
+	monitors            MonitorScheduler
+	durableMonitors     bool
+	monitorLease        time.Duration
+	monitorPollInterval time.Duration
+	recoveryDone        chan struct{}
+	recoveryCloser      func(ctx context.Context, auctionId, reason string) *internal_error.InternalError
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	closeEventsConfig   *closeEventsConfig
+	closeEvents         *closeEventPublisher
+	closeCallbacks      closeCallbacks
+	textSearchEnabled   atomic.Bool
+
+	// maxConcurrentAuctions overrides the configured limit once set at
+	// runtime by SetMaxConcurrentAuctions
+	maxConcurrentAuctions atomic.Int64
+
+	cache            *auctionCache
+	countCache       *auctionCountCache
+	clock            clock.Clock
+	fullEndTimeIndex bool
+	config           Config
+	stopPurge        chan struct{}
+	stopPurgeOnce    sync.Once
+	findByIdLatency  *metrics.Histogram
+	metricsRegistry  *metrics.Registry
+	statsCache       auctionStatsCache
+	activeProjection *activeCountProjection
+
+	// maintenanceMode refuses new auctions, see SetMaintenanceMode
+	maintenanceMode atomic.Bool
+}
+
+// RepositoryOption customizes an AuctionRepository built by NewAuctionRepository.
+type RepositoryOption func(*AuctionRepository)
+
+// WithClock replaces the real time source used for closing auctions,
+// mainly so tests can drive the monitors with a clock.FakeClock.
+func WithClock(c clock.Clock) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		ar.clock = c
+	}
+}
+
+// WithContext derives the repository context from ctx, so cancelling it
+// stops the startup recovery like Close does.
+func WithContext(ctx context.Context) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		ar.ctx = ctx
+	}
+}
+
+// WithBidsCollection points winner lookups at bids stored outside the
+// auctions database, e.g. when auctions are routed to other databases.
+func WithBidsCollection(collection *mongo.Collection) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		ar.bidsCollection = collection
+	}
 }
 
-func NewAuctionRepository(database *mongo.Database) *AuctionRepository {
+// WithFullEndTimeIndex indexes {status, end_time} over every auction instead
+// of the default partial end_time index restricted to active auctions, for
+// servers without partial index support.
+func WithFullEndTimeIndex() RepositoryOption {
+	return func(ar *AuctionRepository) {
+		ar.fullEndTimeIndex = true
+	}
+}
+
+// WithFindByIdCache puts an LRU cache of the given size in front of
+// FindAuctionById. Entries live at most ttl and are dropped on every write
+// to the auction. A non-positive size leaves the cache disabled.
+func WithFindByIdCache(size int, ttl time.Duration) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		if size > 0 {
+			ar.cache = newAuctionCache(size, ttl)
+		}
+	}
+}
+
+// WithMetrics records the latency of FindAuctionById and the outcome of
+// the startup recovery in registry. Without it lookups are not timed at all.
+func WithMetrics(registry *metrics.Registry) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		ar.findByIdLatency = registry.Histogram("find_auction_by_id")
+		ar.metricsRegistry = registry
+	}
+}
+
+func NewAuctionRepository(database *mongo.Database, opts ...RepositoryOption) *AuctionRepository {
 	repo := &AuctionRepository{
-		Collection:          database.Collection("auctions"),
-		activeAuctionsCount: 0,
-		auctionCountMutex:   &sync.Mutex{},
+		Collection:        database.Collection("auctions"),
+		bidsCollection:    database.Collection("bids"),
+		historyCollection: database.Collection("auction_status_history"),
+		auctionCountMutex: &sync.Mutex{},
+		monitoredAuctions: make(map[string]string),
+		reservedSlots:     make(map[string]struct{}),
+		ownerReservations: make(map[string]int64),
+		recoveryDone:      make(chan struct{}),
+		ctx:               context.Background(),
+		clock:             clock.NewRealClock(),
+		stopPurge:         make(chan struct{}),
+		activeProjection:  newActiveCountProjection(),
+	}
+
+	for _, opt := range opts {
+		opt(repo)
+	}
+	repo.config = repo.config.orElse(ConfigFromEnv())
+	repo.ctx, repo.cancel = context.WithCancel(repo.ctx)
+
+	if repo.closeEventsConfig != nil {
+		repo.closeEvents = newCloseEventPublisher(*repo.closeEventsConfig, repo.clock, repo.ctx.Done())
+	}
+
+	if collectionOptions := repo.collectionOptions(); collectionOptions != nil {
+		repo.Collection = database.Collection("auctions", collectionOptions)
+	}
+
+	if repo.durableMonitors {
+		repo.monitors = newDurableMonitorScheduler(
+			repo.Collection, repo.clock, repo.monitorLease, repo.monitorPollInterval, repo.closeDueAuction)
+	} else {
+		repo.monitors = newMonitorScheduler(repo.clock, repo.getMonitorWorkers(), repo.closeDueAuction)
+	}
+	repo.monitors.Start()
+
+	if repo.isSchemaValidationEnabled() {
+		repo.applySchemaValidation()
+	}
+	repo.ensureIndexes()
+	repo.warnMissingIndexes()
+	repo.normalizeStoredCategories()
+
+	if repo.purgeEnabled() {
+		go repo.runPurgeSchedule()
+	}
+
+	// Other instances close auctions too; resync the slots with the database
+	if repo.durableMonitors {
+		go repo.runActiveResync()
+	}
+
+	// Auctions skipped beyond the limit have no monitor to close them
+	if repo.getRecoveryOverLimit() == RecoveryOverLimitSkip {
+		go repo.runOverLimitSweep()
 	}
 
 	// Handle active auctions on restart
@@ -48,41 +223,101 @@ func (ar *AuctionRepository) CreateAuction(
 	ctx context.Context,
 	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
 
-	// Check concurrent auctions limit
-	if !ar.checkActiveAuctionsLimit() {
-		logger.Error("Maximum concurrent auctions limit reached", nil)
-		return internal_error.NewInternalServerError("Maximum concurrent auctions limit reached")
+	// A retried request gets back the auction its first attempt created
+	if auctionEntity.IdempotencyKey != "" {
+		existing, err := ar.findAuctionByIdempotencyKey(ctx, auctionEntity.IdempotencyKey)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			*auctionEntity = *existing
+			return nil
+		}
+	}
+
+	if err := ar.checkMaintenanceMode(); err != nil {
+		return err
+	}
+
+	// Check category against the configured allowed list
+	if !ar.isCategoryAllowed(auctionEntity.Category) {
+		logger.Error("Auction category is not allowed", nil)
+		return internal_error.NewBadRequestError("Auction category is not allowed")
+	}
+
+	description, descriptionErr := ar.limitDescription(auctionEntity.Description)
+	if descriptionErr != nil {
+		logger.Error("Auction description exceeds the maximum size", descriptionErr)
+		return descriptionErr
+	}
+
+	releaseOwnerSlot, ownerErr := ar.reserveOwnerSlot(ctx, auctionEntity.OwnerId)
+	if ownerErr != nil {
+		return ownerErr
+	}
+	defer releaseOwnerSlot()
+
+	// Reserve the slot before the insert, as ResumeAuction does, so a
+	// concurrent Close or create can't slip in between
+	if err := ar.reserveCreateSlot(auctionEntity.Id, auctionEntity.Category); err != nil {
+		return err
 	}
 
 	// Calcular tempo de término do leilão
 	auctionDuration := ar.getAuctionDuration()
-	endTime := auctionEntity.Timestamp.Add(auctionDuration)
+	if auctionEntity.Duration > 0 {
+		auctionDuration = auctionEntity.Duration
+	}
+	endTime := auctionEntity.Timestamp.UTC().Add(auctionDuration)
+	storedDescription, compressed := ar.storedDescription(description)
 
 	auctionEntityMongo := &AuctionEntityMongo{
 		Id:          auctionEntity.Id,
 		ProductName: auctionEntity.ProductName,
 		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
+		Subcategory: auctionEntity.Subcategory,
+		Description: storedDescription,
 		Condition:   auctionEntity.Condition,
 		Status:      auctionEntity.Status,
+		ImageURLs:   auctionEntity.ImageURLs,
+		OwnerId:     auctionEntity.OwnerId,
 		Timestamp:   auctionEntity.Timestamp.Unix(),
 		EndTime:     endTime.Unix(),
+		Version:     1,
+		Compressed:  compressed,
+
+		IdempotencyKey: auctionEntity.IdempotencyKey,
 	}
 
 	_, err := ar.Collection.InsertOne(ctx, auctionEntityMongo)
+	if err != nil {
+		ar.releaseSlot(auctionEntity.Id)
+	}
+	if err != nil && auctionEntity.IdempotencyKey != "" && mongo.IsDuplicateKeyError(err) {
+		// A concurrent request with the same key inserted first
+		if existing, findErr := ar.findAuctionByIdempotencyKey(ctx, auctionEntity.IdempotencyKey); findErr == nil &&
+			existing != nil {
+			*auctionEntity = *existing
+			return nil
+		}
+	}
 	if err != nil {
 		logger.Error("Error trying to insert auction", err)
 		return internal_error.NewInternalServerError("Error trying to insert auction")
 	}
+	ar.invalidateCachedCounts()
 
-	// Increment active auctions counter
+	// Schedule the auto-close on the shared monitor pool. If Close ran
+	// after the insert the auction stays Active and is picked up by
+	// recovery on the next start; if it was cancelled meanwhile its slot
+	// is already gone and there is nothing to close
 	ar.auctionCountMutex.Lock()
-	ar.activeAuctionsCount++
+	ar.settleSlotLocked(auctionEntity.Id)
+	if _, monitored := ar.monitoredAuctions[auctionEntity.Id]; monitored && !ar.closed {
+		ar.monitors.Schedule(auctionEntity.Id, ar.closeTime(ar.clock.Now().Add(auctionDuration)))
+	}
 	ar.auctionCountMutex.Unlock()
 
-	// Start individual auction monitor goroutine
-	go ar.startIndividualAuctionMonitor(auctionEntity)
-
 	logger.Info("Auction created successfully with auto-close monitoring")
 	return nil
 }
@@ -91,91 +326,252 @@ func (ar *AuctionRepository) UpdateAuctionStatus(
 	ctx context.Context,
 	auctionId string,
 	status auction_entity.AuctionStatus) *internal_error.InternalError {
+	applied, err := ar.changeAuctionStatus(ctx, auctionId, status, statusReasonUpdate)
+	if applied && status == auction_entity.Completed {
+		ar.runCloseCallbacks(auctionId)
+	}
 
-	filter := bson.M{"_id": auctionId}
-	update := bson.M{"$set": bson.M{"status": status}}
+	return err
+}
 
-	_, err := ar.Collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		logger.Error("Error trying to update auction status", err)
-		return internal_error.NewInternalServerError("Error trying to update auction status")
+// changeAuctionStatus moves an auction to status and records the change in
+// the status history. applied reports whether the auction document was
+// updated, which may be true even when a strict audit write failed.
+func (ar *AuctionRepository) changeAuctionStatus(
+	ctx context.Context,
+	auctionId string,
+	status auction_entity.AuctionStatus,
+	reason string) (bool, *internal_error.InternalError) {
+
+	var current AuctionEntityMongo
+	if err := ar.Collection.FindOne(ctx, bson.M{"_id": auctionId},
+		options.FindOne().SetProjection(bson.M{"status": 1, "category": 1, "owner_id": 1})).Decode(&current); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Auction not found with this id = %s", auctionId), err)
+			return false, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", auctionId))
+		}
+
+		logger.Error("Error trying to find auction status", err)
+		return false, internal_error.NewInternalServerError("Error trying to update auction status")
 	}
 
-	return nil
-}
+	if !validTransition(current.Status, status) {
+		logger.Error(fmt.Sprintf("Illegal auction status transition from %d to %d", current.Status, status), nil)
+		return false, internal_error.NewConflictError(
+			fmt.Sprintf("Auction status cannot change from %d to %d", current.Status, status))
+	}
+	if isPauseTransition(current.Status, status) {
+		return false, internal_error.NewConflictError("Use PauseAuction and ResumeAuction to pause or resume an auction")
+	}
 
-func (ar *AuctionRepository) startIndividualAuctionMonitor(auctionEntity *auction_entity.Auction) {
-	auctionDuration := ar.getAuctionDuration()
-	timer := time.NewTimer(auctionDuration)
+	now := ar.clock.Now()
 
-	<-timer.C
+	// A reopened auction runs for a fresh AUCTION_INTERVAL and counts
+	// against the limits like a new one
+	reopen := status == auction_entity.Active
+	var endTime time.Time
+	if reopen {
+		releaseOwnerSlot, err := ar.reserveOwnerSlot(ctx, current.OwnerId)
+		if err != nil {
+			return false, err
+		}
+		defer releaseOwnerSlot()
+		if err := ar.reserveSlot(auctionId, current.Category); err != nil {
+			return false, err
+		}
+		endTime = now.UTC().Add(ar.getAuctionDuration())
+	}
 
-	// Create context for the update operation
-	ctx := context.Background()
+	// Only update if nobody changed the status since it was read
+	filter := bson.M{"_id": auctionId, "status": current.Status}
+	fields := bson.M{"status": status}
+	update := bson.M{"$set": fields, "$inc": bson.M{"version": 1}}
+	closeReason := closeReasonFor(reason)
+	if reopen {
+		fields["end_time"] = endTime.Unix()
+		update["$unset"] = bson.M{"closed_at": "", "close_reason": ""}
+	} else {
+		fields["closed_at"] = now.Unix()
+		fields["close_reason"] = closeReason
+		if current.Status == auction_entity.Paused {
+			update["$unset"] = bson.M{"paused_remaining": ""}
+		}
+	}
 
-	// Update auction status to Completed
-	if err := ar.UpdateAuctionStatus(ctx, auctionEntity.Id, auction_entity.Completed); err != nil {
-		logger.Error("Error closing auction automatically", err)
-		return
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if reopen && (err != nil || result.MatchedCount == 0) {
+		ar.releaseSlot(auctionId)
+	}
+	if err != nil {
+		logger.Error("Error trying to update auction status", err)
+		return false, internal_error.NewInternalServerError("Error trying to update auction status")
 	}
+	ar.invalidateCachedAuction(auctionId)
+	ar.invalidateCachedCounts()
 
-	// Decrement active auctions counter
-	ar.auctionCountMutex.Lock()
-	ar.activeAuctionsCount--
-	ar.auctionCountMutex.Unlock()
+	if result.MatchedCount == 0 {
+		return false, internal_error.NewConflictError("Auction status was changed concurrently")
+	}
 
-	logger.Info("Auction closed automatically due to timeout")
+	if reopen {
+		ar.auctionCountMutex.Lock()
+		ar.settleSlotLocked(auctionId)
+		ar.monitors.Schedule(auctionId, ar.closeTime(endTime))
+		ar.auctionCountMutex.Unlock()
+		closeReason = ""
+	}
+	if status == auction_entity.Cancelled {
+		ar.closeCallbacks.forget(auctionId)
+	}
+	ar.publishStatusChange(current.Status, status, auctionId)
+
+	return true, ar.recordStatusChanges(ctx, reason, closeReason, current.Status, status, auctionId)
 }
 
-func (ar *AuctionRepository) startIndividualAuctionMonitorWithEndTime(auctionId string, endTime time.Time) {
-	now := time.Now()
-	remainingTime := endTime.Sub(now)
+// closeDueAuction is run by the monitor pool once an auction's end time
+// has been reached. It returns the close notification for the pool to
+// publish in deadline order.
+func (ar *AuctionRepository) closeDueAuction(auctionId string) func() {
+	applied, err := ar.completeMonitoredAuction(context.Background(), auctionId)
+	if err != nil {
+		logger.Error("Error closing auction automatically", err)
+	} else {
+		logger.Info("Auction closed automatically due to timeout")
+	}
+	if !applied {
+		return nil
+	}
+
+	return func() { ar.notifyAuctionClosed(auctionId) }
+}
 
-	// Se o leilão já expirou, feche imediatamente
-	if remainingTime <= 0 {
-		ctx := context.Background()
-		if err := ar.UpdateAuctionStatus(ctx, auctionId, auction_entity.Completed); err != nil {
-			logger.Error("Error closing expired auction on restart", err)
+// stopTimer releases a monitor timer on every exit path. If the timer
+// already fired but was not received (cancelled at the same moment), the
+// pending value is drained so the channel is left empty.
+func stopTimer(timer clock.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C():
+		default:
 		}
-		logger.Info("Expired auction closed immediately on restart")
-		return
 	}
+}
 
-	timer := time.NewTimer(remainingTime)
-
-	<-timer.C
+// closeMonitoredAuction completes a monitored auction and frees its slot.
+// The slot is also freed when the auction is no longer active (e.g. it was
+// cancelled meanwhile); only unexpected database errors keep it reserved.
+func (ar *AuctionRepository) closeMonitoredAuction(ctx context.Context, auctionId string) *internal_error.InternalError {
+	applied, err := ar.completeMonitoredAuction(ctx, auctionId)
+	if applied {
+		ar.notifyAuctionClosed(auctionId)
+	}
 
-	// Create context for the update operation
-	ctx := context.Background()
+	return err
+}
 
-	// Update auction status to Completed
-	if err := ar.UpdateAuctionStatus(ctx, auctionId, auction_entity.Completed); err != nil {
-		logger.Error("Error closing auction automatically", err)
-		return
+// completeMonitoredAuction does the part of closeMonitoredAuction that
+// needs no ordering: the status change, the slot and the winner. It
+// reports whether this call closed the auction.
+func (ar *AuctionRepository) completeMonitoredAuction(
+	ctx context.Context, auctionId string) (bool, *internal_error.InternalError) {
+	applied, err := ar.changeAuctionStatus(ctx, auctionId, auction_entity.Completed, statusReasonAutoClose)
+	if !applied && err != nil && err.Err == "internal_server_error" {
+		return false, err
 	}
 
 	// Decrement active auctions counter
 	ar.auctionCountMutex.Lock()
-	ar.activeAuctionsCount--
+	if _, ok := ar.monitoredAuctions[auctionId]; ok {
+		ar.monitors.Cancel(auctionId)
+		ar.untrackAuctionLocked(auctionId)
+	}
 	ar.auctionCountMutex.Unlock()
 
-	logger.Info("Auction closed automatically after restart with remaining time")
+	if applied {
+		ar.assignWinnerOnClose(ctx, auctionId)
+	}
+
+	return applied, err
+}
+
+// reserveSlot takes a concurrency slot for an auction about to become
+// Active in the database, before the write, so a concurrent create cannot
+// fill it; releaseSlot gives it back when the write fails.
+func (ar *AuctionRepository) reserveSlot(auctionId, category string) *internal_error.InternalError {
+	ar.auctionCountMutex.Lock()
+	defer ar.auctionCountMutex.Unlock()
+
+	if ar.closed {
+		return internal_error.NewConflictError("repository is shutting down")
+	}
+	if _, ok := ar.monitoredAuctions[auctionId]; ok {
+		// Its close is still releasing the previous slot
+		return internal_error.NewConflictError("Auction status was changed concurrently")
+	}
+	if !ar.hasSlotLocked(category) {
+		return internal_error.NewConflictError("Maximum concurrent auctions limit reached")
+	}
+	ar.trackAuctionLocked(auctionId, category)
+	ar.reservedSlots[auctionId] = struct{}{}
+	return nil
+}
+
+func (ar *AuctionRepository) releaseSlot(auctionId string) {
+	ar.auctionCountMutex.Lock()
+	defer ar.auctionCountMutex.Unlock()
+
+	delete(ar.reservedSlots, auctionId)
+	ar.untrackAuctionLocked(auctionId)
 }
 
-func (ar *AuctionRepository) checkActiveAuctionsLimit() bool {
+// settleSlotLocked marks the write of a reserved slot as done. Its opened
+// event is fed again so a rebuild reading the database before the write
+// still counts it. The caller holds auctionCountMutex.
+func (ar *AuctionRepository) settleSlotLocked(auctionId string) {
+	delete(ar.reservedSlots, auctionId)
+	if category, ok := ar.monitoredAuctions[auctionId]; ok {
+		ar.activeProjection.apply(auctionEvent{kind: auctionOpened, auctionId: auctionId, category: category})
+	}
+}
+
+// reserveCreateSlot takes a slot for a new auction, failing if the
+// repository is closed or the concurrent auctions limit, the category's own
+// or the global one, is reached.
+func (ar *AuctionRepository) reserveCreateSlot(auctionId, category string) *internal_error.InternalError {
 	ar.auctionCountMutex.Lock()
 	defer ar.auctionCountMutex.Unlock()
 
-	maxAuctions := ar.getMaxConcurrentAuctions()
-	return ar.activeAuctionsCount < maxAuctions
+	if ar.closed {
+		return internal_error.NewConflictError("repository is shutting down")
+	}
+	if !ar.hasSlotLocked(category) {
+		logger.Error("Maximum concurrent auctions limit reached", nil,
+			zap.String("category", category))
+		return internal_error.NewInternalServerError("Maximum concurrent auctions limit reached")
+	}
+	ar.trackAuctionLocked(auctionId, category)
+	ar.reservedSlots[auctionId] = struct{}{}
+	return nil
 }
 
+// handleActiveAuctionsOnRestart reschedules or closes the active auctions
+// found on startup; those beyond the limit follow RECOVERY_OVER_LIMIT and
+// closes that fail are retried after the main pass. It stops as soon as
+// the repository context is cancelled; auctions not reached yet stay
+// Active for the next start.
 func (ar *AuctionRepository) handleActiveAuctionsOnRestart() {
-	ctx := context.Background()
+	defer close(ar.recoveryDone)
+
+	ctx := ar.ctx
 
 	// Find all active auctions
+	// Sorting by end_time lets the active end_time index serve the scan and
+	// reschedules the auctions closest to ending first. Only the auctions
+	// given a slot below enter the active count projection
 	filter := bson.M{"status": auction_entity.Active}
-	cursor, err := ar.Collection.Find(ctx, filter)
+	opts := options.Find().SetSort(bson.D{{Key: "end_time", Value: 1}})
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
 	if err != nil {
 		logger.Error("Error finding active auctions on restart", err)
 		return
@@ -188,45 +584,293 @@ func (ar *AuctionRepository) handleActiveAuctionsOnRestart() {
 		return
 	}
 
+	ar.recoverActiveAuctions(ctx, activeAuctions)
+}
+
+// recoverActiveAuctions reschedules or closes the auctions of a recovery
+// scan. The scan may be stale by the time an auction is reached, so each
+// one is read again under auctionCountMutex before it takes a slot; the
+// writers that close or cancel auctions free slots under the same mutex
+// after their write, so an auction closed meanwhile is never tracked.
+func (ar *AuctionRepository) recoverActiveAuctions(ctx context.Context, activeAuctions []AuctionEntityMongo) {
 	// Reiniciar leilões com base no tempo restante
-	recoveredCount := 0
+	var summary recoverySummary
+	defer ar.reportRecovery(&summary)
+	var failed []failedRecoveryClose
+	maxRemainingTime := ar.getMaxRemainingTime()
+	overLimit := ar.getRecoveryOverLimit()
 	for _, auction := range activeAuctions {
+		if ctx.Err() != nil {
+			logger.Info("Context cancelled, stopping auction recovery")
+			return
+		}
+
 		endTime := time.Unix(auction.EndTime, 0)
 
+		// A remaining time beyond the cap means the clock jumped backward;
+		// close the auction instead of leaving a timer stuck for ages
+		if remaining := endTime.Sub(ar.clock.Now()); remaining > maxRemainingTime {
+			logger.Warn("Recovered auction ends too far in the future, closing it",
+				zap.String("auction_id", auction.Id),
+				zap.Duration("remaining", remaining),
+				zap.Duration("max_remaining_time", maxRemainingTime))
+			if err := ar.recoveryClose(ctx, auction.Id, statusReasonClockSkew); err != nil {
+				logger.Error("Error closing auction with skewed end time on restart", err)
+				failed = append(failed, failedRecoveryClose{auction.Id, statusReasonClockSkew})
+			} else {
+				ar.countRecoveryClose(ctx, &summary, auction.Id, statusReasonClockSkew)
+			}
+			continue
+		}
+
 		// Incrementar contador de leilões ativos
 		ar.auctionCountMutex.Lock()
-		if ar.activeAuctionsCount < ar.getMaxConcurrentAuctions() {
-			ar.activeAuctionsCount++
+		if ar.closed {
 			ar.auctionCountMutex.Unlock()
-
-			// Iniciar goroutine com tempo restante
-			go ar.startIndividualAuctionMonitorWithEndTime(auction.Id, endTime)
-			recoveredCount++
+			logger.Info("Repository closed, stopping auction recovery")
+			return
+		}
+		if _, ok := ar.monitoredAuctions[auction.Id]; ok {
+			// Leilão criado após o start já possui monitor
+			ar.auctionCountMutex.Unlock()
+			continue
+		}
+		if !ar.stillActive(ctx, auction.Id) {
+			// Fechado ou cancelado depois da varredura
+			ar.auctionCountMutex.Unlock()
+			continue
+		}
+		// An expired auction is monitored whatever the mode, so it closes
+		// right away and gives its slot back
+		expired := !endTime.After(ar.clock.Now())
+		hasSlot := ar.hasSlotLocked(auction.Category)
+		if hasSlot || expired || overLimit == RecoveryOverLimitKeep {
+			ar.trackAuctionLocked(auction.Id, auction.Category)
+
+			// Agendar com o tempo restante; leilões já expirados fecham imediatamente
+			ar.monitors.Schedule(auction.Id, ar.closeTime(endTime))
+			ar.auctionCountMutex.Unlock()
+			switch {
+			case expired:
+				summary.closedExpired++
+			case !hasSlot:
+				summary.keptOverLimit++
+			default:
+				summary.recovered++
+			}
+		} else if overLimit == RecoveryOverLimitSkip {
+			ar.auctionCountMutex.Unlock()
+			logger.Warn("Active auction beyond the limit left without a monitor on restart",
+				zap.String("auction_id", auction.Id))
+			summary.skippedOverLimit++
 		} else {
 			ar.auctionCountMutex.Unlock()
 			// Se exceder o limite, feche o leilão
-			if err := ar.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed); err != nil {
+			if err := ar.recoveryClose(ctx, auction.Id, statusReasonRecoveryLimit); err != nil {
 				logger.Error("Error closing auction due to limit on restart", err)
+				failed = append(failed, failedRecoveryClose{auction.Id, statusReasonRecoveryLimit})
+			} else {
+				ar.countRecoveryClose(ctx, &summary, auction.Id, statusReasonRecoveryLimit)
 			}
 		}
 	}
 
-	if len(activeAuctions) > 0 {
-		logger.Info("Active auctions recovered after restart")
-	}
+	ar.retryFailedRecoveryCloses(ctx, failed, &summary)
 }
 
-func (ar *AuctionRepository) getAuctionDuration() time.Duration {
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	duration, err := time.ParseDuration(auctionInterval)
+// stillActive reports whether the auction is still stored as Active. A
+// failed read counts as active, so the auction keeps its monitor rather
+// than staying Active with none.
+func (ar *AuctionRepository) stillActive(ctx context.Context, auctionId string) bool {
+	err := ar.Collection.FindOne(ctx, bson.M{"_id": auctionId, "status": auction_entity.Active},
+		options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false
+	}
 	if err != nil {
-		logger.Error("Error parsing AUCTION_INTERVAL, using default 5 minutes", err)
-		return time.Minute * 5
+		logger.Error(fmt.Sprintf("Error trying to read the status of recovered auction %s", auctionId), err)
+	}
+	return true
+}
+
+// recoverySummary counts what the startup recovery did with each active
+// auction. Expired auctions are handed to the monitors, which close them
+// right away.
+type recoverySummary struct {
+	recovered        int64
+	closedExpired    int64
+	closedOverLimit  int64
+	closedClockSkew  int64
+	keptOverLimit    int64
+	skippedOverLimit int64
+}
+
+// reportRecovery logs the recovery summary and adds it to the metrics
+// registry, if any.
+func (ar *AuctionRepository) reportRecovery(summary *recoverySummary) {
+	logger.Info("Auction recovery finished",
+		zap.Int64("recovered", summary.recovered),
+		zap.Int64("closed_expired", summary.closedExpired),
+		zap.Int64("closed_over_limit", summary.closedOverLimit),
+		zap.Int64("closed_clock_skew", summary.closedClockSkew),
+		zap.Int64("kept_over_limit", summary.keptOverLimit),
+		zap.Int64("skipped_over_limit", summary.skippedOverLimit))
+
+	if ar.metricsRegistry == nil {
+		return
 	}
-	return duration
+	ar.metricsRegistry.Counter("recovery_recovered").Add(summary.recovered)
+	ar.metricsRegistry.Counter("recovery_closed_expired").Add(summary.closedExpired)
+	ar.metricsRegistry.Counter("recovery_closed_over_limit").Add(summary.closedOverLimit)
+	ar.metricsRegistry.Counter("recovery_closed_clock_skew").Add(summary.closedClockSkew)
+	ar.metricsRegistry.Counter("recovery_kept_over_limit").Add(summary.keptOverLimit)
+	ar.metricsRegistry.Counter("recovery_skipped_over_limit").Add(summary.skippedOverLimit)
 }
 
-func (ar *AuctionRepository) getMaxConcurrentAuctions() int64 {
-	// Default to 50 if not set
-	return 50
+// reportShutdown logs what Close stopped and adds it to the metrics
+// registry, if any.
+func (ar *AuctionRepository) reportShutdown(stats ShutdownStats, elapsed time.Duration) {
+	logger.Info("Auction repository shut down",
+		zap.Int("cancelled_monitors", stats.CancelledMonitors),
+		zap.Int("drained_closes", stats.DrainedCloses),
+		zap.Duration("elapsed", elapsed))
+
+	if ar.metricsRegistry == nil {
+		return
+	}
+	ar.metricsRegistry.Counter("shutdown_cancelled_monitors").Add(int64(stats.CancelledMonitors))
+	ar.metricsRegistry.Counter("shutdown_drained_closes").Add(int64(stats.DrainedCloses))
+	ar.metricsRegistry.Histogram("shutdown_duration").Observe(elapsed)
+}
+
+// Close cancels the repository context and stops the monitor pool and the
+// purge schedule; later creates fail with a conflict. Auctions still active
+// remain Active in the database and are picked up again by recovery on the
+// next start. The first call logs and records a shutdown summary.
+func (ar *AuctionRepository) Close() {
+	start := time.Now()
+
+	// Flag the shutdown first so no create or recovery schedules a monitor
+	// on the pool being stopped
+	ar.auctionCountMutex.Lock()
+	alreadyClosed := ar.closed
+	ar.closed = true
+	ar.auctionCountMutex.Unlock()
+	ar.cancel()
+
+	stats := ar.monitors.Shutdown()
+	if !alreadyClosed {
+		ar.reportShutdown(stats, time.Since(start))
+	}
+	ar.stopPurgeOnce.Do(func() {
+		close(ar.stopPurge)
+	})
+
+	ar.auctionCountMutex.Lock()
+	defer ar.auctionCountMutex.Unlock()
+
+	ar.untrackAllLocked()
+}
+
+// RecoveryDone reports whether the active auctions found on startup have
+// already been rescheduled or closed.
+func (ar *AuctionRepository) RecoveryDone() bool {
+	select {
+	case <-ar.recoveryDone:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForRecovery blocks until RecoveryDone reports true or ctx is done,
+// in which case the context error is returned.
+func (ar *AuctionRepository) WaitForRecovery(ctx context.Context) error {
+	if ar.RecoveryDone() {
+		return nil
+	}
+
+	select {
+	case <-ar.recoveryDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RepositoryStats is a point-in-time view of the auto-close bookkeeping.
+type RepositoryStats struct {
+	// Active is the number of auctions holding a concurrency slot, out of
+	// Max, as derived by the active count projection
+	Active int64
+	Max    int64
+
+	// ScheduledMonitors counts the auctions waiting for their end time
+	ScheduledMonitors int
+}
+
+// Stats returns a race-free snapshot of the active count projection and the
+// monitor queue.
+func (ar *AuctionRepository) Stats() RepositoryStats {
+	return RepositoryStats{
+		Active:            ar.activeProjection.count(),
+		Max:               ar.getMaxConcurrentAuctions(),
+		ScheduledMonitors: ar.monitors.Pending(),
+	}
+}
+
+// Ping checks the database connection, giving up after MONGO_PING_TIMEOUT.
+func (ar *AuctionRepository) Ping(ctx context.Context) *internal_error.InternalError {
+	if err := mongodb.Ping(ctx, ar.Collection.Database().Client(), 0); err != nil {
+		logger.Error("Error trying to ping mongodb database", err)
+		return internal_error.NewInternalServerError("Error trying to ping mongodb database")
+	}
+
+	return nil
+}
+
+func (ar *AuctionRepository) getAllowedCategories() []string {
+	auctionCategories := os.Getenv("AUCTION_CATEGORIES")
+	if strings.TrimSpace(auctionCategories) == "" {
+		return nil
+	}
+
+	var categories []string
+	for _, category := range strings.Split(auctionCategories, ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+func (ar *AuctionRepository) isCategoryAllowed(category string) bool {
+	allowedCategories := ar.getAllowedCategories()
+	if len(allowedCategories) == 0 {
+		return strings.TrimSpace(category) != ""
+	}
+
+	for _, allowedCategory := range allowedCategories {
+		if strings.EqualFold(allowedCategory, strings.TrimSpace(category)) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitDescription enforces maxDescriptionLength according to
+// DESCRIPTION_OVERFLOW_MODE: "truncate" cuts the description to the cap,
+// anything else (the default, "reject") refuses it.
+func (ar *AuctionRepository) limitDescription(description string) (string, *internal_error.InternalError) {
+	runes := []rune(description)
+	if len(runes) <= maxDescriptionLength {
+		return description, nil
+	}
+
+	if strings.EqualFold(os.Getenv("DESCRIPTION_OVERFLOW_MODE"), "truncate") {
+		return string(runes[:maxDescriptionLength]), nil
+	}
+
+	return "", internal_error.NewBadRequestError(
+		fmt.Sprintf("Auction description exceeds %d characters", maxDescriptionLength))
 }
@@ -3,10 +3,13 @@ package auction
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
 
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
@@ -178,8 +181,12 @@ func TestConcurrentAuctionCreation(t *testing.T) {
 				return
 			}
 
-			err = repo.CreateAuction(ctx, auction)
-			results <- err
+			// Avoid sending a typed nil *InternalError as a non-nil error
+			if err := repo.CreateAuction(ctx, auction); err != nil {
+				results <- err
+				return
+			}
+			results <- nil
 		}(i)
 	}
 
@@ -196,22 +203,247 @@ func TestConcurrentAuctionCreation(t *testing.T) {
 	assert.Equal(t, numGoroutines, successCount)
 }
 
-func TestAuctionDurationParsing(t *testing.T) {
+func TestCreateAuctionDuringClose(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
 	db, cleanup := setupTestDB()
 	defer cleanup()
 
 	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	numGoroutines := 20
+	type createResult struct {
+		id  string
+		err *internal_error.InternalError
+	}
+	results := make(chan createResult, numGoroutines)
+	start := make(chan struct{})
+
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			auction, err := auction_entity.CreateAuction(
+				"Concurrent Product",
+				"Electronics",
+				"Test description for concurrent auction",
+				auction_entity.New,
+			)
+			if err != nil {
+				results <- createResult{err: err}
+				return
+			}
+
+			<-start
+			results <- createResult{id: auction.Id, err: repo.CreateAuction(ctx, auction)}
+		}()
+	}
+
+	close(start)
+	repo.Close()
+
+	for i := 0; i < numGoroutines; i++ {
+		result := <-results
+		if result.err != nil {
+			assert.Equal(t, "conflict", result.err.Err)
+			assert.Equal(t, "repository is shutting down", result.err.Message)
+			continue
+		}
+
+		// O slot foi reservado antes do Close: o leilão fica salvo e Active
+		// para a recuperação do próximo start
+		stored, findErr := repo.FindAuctionById(ctx, result.id)
+		assert.Nil(t, findErr)
+		if stored != nil {
+			assert.Equal(t, auction_entity.Active, stored.Status)
+		}
+	}
+
+	// Every create after Close is rejected and nothing is left scheduled
+	auction, err := auction_entity.CreateAuction(
+		"Late Product", "Electronics", "Test description for late auction", auction_entity.New)
+	assert.Nil(t, err)
+	createErr := repo.CreateAuction(ctx, auction)
+	assert.NotNil(t, createErr)
+	assert.Equal(t, "conflict", createErr.Err)
+
+	repo.auctionCountMutex.Lock()
+	assert.Empty(t, repo.monitoredAuctions)
+	repo.auctionCountMutex.Unlock()
+}
+
+func TestAuctionDurationParsing(t *testing.T) {
+	durationFromEnv := func() time.Duration {
+		return (&AuctionRepository{config: ConfigFromEnv()}).getAuctionDuration()
+	}
 
 	// Test valid duration
 	os.Setenv("AUCTION_INTERVAL", "10m")
-	duration := repo.getAuctionDuration()
+	duration := durationFromEnv()
 	assert.Equal(t, 10*time.Minute, duration)
 
 	// Test invalid duration (should use default)
 	os.Setenv("AUCTION_INTERVAL", "invalid")
-	duration = repo.getAuctionDuration()
+	duration = durationFromEnv()
 	assert.Equal(t, 5*time.Minute, duration)
 
+	// Negative and zero durations parse but are rejected (should use default)
+	for _, interval := range []string{"-5m", "0s"} {
+		os.Setenv("AUCTION_INTERVAL", interval)
+		assert.Equal(t, 5*time.Minute, durationFromEnv(), interval)
+		assert.Equal(t, time.Duration(0), ConfigFromEnv().AuctionInterval, interval)
+	}
+
 	// Cleanup
 	os.Unsetenv("AUCTION_INTERVAL")
 }
+
+func TestCreateAuctionAllowedCategories(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	testCases := []struct {
+		description       string
+		allowedCategories string
+		category          string
+		expectError       bool
+	}{
+		{"allowed category", "Electronics, Art", "Electronics", false},
+		{"disallowed category", "Electronics, Art", "Furniture", true},
+		{"unconfigured list allows any category", "", "Furniture", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			os.Setenv("AUCTION_CATEGORIES", tc.allowedCategories)
+			defer os.Unsetenv("AUCTION_CATEGORIES")
+
+			auction, err := auction_entity.CreateAuction(
+				"Test Product",
+				tc.category,
+				"Test description for auction",
+				auction_entity.New,
+			)
+			assert.Nil(t, err)
+
+			err = repo.CreateAuction(ctx, auction)
+			if tc.expectError {
+				assert.NotNil(t, err)
+				assert.Equal(t, "bad_request", err.Err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestIsCategoryAllowed(t *testing.T) {
+	repo := &AuctionRepository{}
+
+	os.Setenv("AUCTION_CATEGORIES", "Electronics,Art")
+	assert.True(t, repo.isCategoryAllowed("Electronics"))
+	assert.True(t, repo.isCategoryAllowed("art"))
+	assert.False(t, repo.isCategoryAllowed("Furniture"))
+
+	os.Unsetenv("AUCTION_CATEGORIES")
+	assert.True(t, repo.isCategoryAllowed("Furniture"))
+	assert.False(t, repo.isCategoryAllowed("  "))
+}
+
+func TestLimitDescription(t *testing.T) {
+	repo := &AuctionRepository{}
+	oversized := strings.Repeat("á", maxDescriptionLength+10)
+
+	t.Run("reject mode", func(t *testing.T) {
+		os.Setenv("DESCRIPTION_OVERFLOW_MODE", "reject")
+		defer os.Unsetenv("DESCRIPTION_OVERFLOW_MODE")
+
+		_, err := repo.limitDescription(oversized)
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+
+	t.Run("truncate mode", func(t *testing.T) {
+		os.Setenv("DESCRIPTION_OVERFLOW_MODE", "truncate")
+		defer os.Unsetenv("DESCRIPTION_OVERFLOW_MODE")
+
+		description, err := repo.limitDescription(oversized)
+		assert.Nil(t, err)
+		assert.Equal(t, maxDescriptionLength, utf8.RuneCountInString(description))
+	})
+
+	t.Run("within the cap is untouched", func(t *testing.T) {
+		description, err := repo.limitDescription("Test description for auction")
+		assert.Nil(t, err)
+		assert.Equal(t, "Test description for auction", description)
+	})
+}
+
+func TestCreateAuctionOversizedDescription(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product",
+		"Electronics",
+		strings.Repeat("a", maxDescriptionLength+1),
+		auction_entity.New,
+	)
+	assert.Nil(t, err)
+
+	os.Setenv("DESCRIPTION_OVERFLOW_MODE", "reject")
+	err = repo.CreateAuction(ctx, auction)
+	assert.NotNil(t, err)
+	assert.Equal(t, "bad_request", err.Err)
+
+	os.Setenv("DESCRIPTION_OVERFLOW_MODE", "truncate")
+	defer os.Unsetenv("DESCRIPTION_OVERFLOW_MODE")
+	err = repo.CreateAuction(ctx, auction)
+	assert.Nil(t, err)
+
+	foundAuction, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Len(t, foundAuction.Description, maxDescriptionLength)
+}
+
+func TestCreateAuctionStoresImageURLs(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	auction.ImageURLs = []string{"https://cdn.example.com/1.jpg", "https://cdn.example.com/2.jpg"}
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	foundAuction, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction.ImageURLs, foundAuction.ImageURLs)
+}
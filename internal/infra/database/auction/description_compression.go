@@ -0,0 +1,82 @@
+package auction
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"go.uber.org/zap"
+)
+
+func (ar *AuctionRepository) isDescriptionCompressionEnabled() bool {
+	return ar.config.CompressDescriptions
+}
+
+// storedDescription is the description as written to the collection and
+// whether it was compressed. A description that fails to compress is
+// stored as is; the flag keeps it readable either way.
+func (ar *AuctionRepository) storedDescription(description string) (string, bool) {
+	if !ar.isDescriptionCompressionEnabled() {
+		return description, false
+	}
+
+	compressed, err := compressDescription(description)
+	if err != nil {
+		logger.Error("Error trying to compress auction description, storing it uncompressed", err)
+		return description, false
+	}
+	return compressed, true
+}
+
+// compressDescription gzips description and encodes it in base64, since
+// BSON strings must hold valid UTF-8.
+func compressDescription(description string) (string, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(description)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+func decompressDescription(stored string) (string, error) {
+	compressed, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	description, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(description), nil
+}
+
+// readDescription returns the description of a stored auction, inflating
+// it when the document is flagged as compressed. An unreadable description
+// is logged and left empty rather than shown encoded.
+func readDescription(auction AuctionEntityMongo) string {
+	if !auction.Compressed {
+		return auction.Description
+	}
+
+	description, err := decompressDescription(auction.Description)
+	if err != nil {
+		logger.Error("Error trying to decompress auction description", err,
+			zap.String("auction_id", auction.Id))
+		return ""
+	}
+	return description
+}
@@ -0,0 +1,112 @@
+package auction
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDescriptionCompressionRoundTrip(t *testing.T) {
+	description := strings.Repeat("A long and repetitive auction description. ", 80)
+
+	compressedRepo := &AuctionRepository{config: Config{CompressDescriptions: true}}
+	stored, compressed := compressedRepo.storedDescription(description)
+	assert.True(t, compressed)
+	assert.Less(t, len(stored), len(description))
+	assert.Equal(t, description, readDescription(AuctionEntityMongo{Description: stored, Compressed: true}))
+
+	// Documents written without compression stay readable
+	plainRepo := &AuctionRepository{}
+	stored, compressed = plainRepo.storedDescription(description)
+	assert.False(t, compressed)
+	assert.Equal(t, description, stored)
+	assert.Equal(t, description, readDescription(AuctionEntityMongo{Description: stored}))
+
+	// A corrupt compressed description is never shown encoded
+	assert.Equal(t, "", readDescription(AuctionEntityMongo{Description: "not gzip", Compressed: true}))
+}
+
+func TestCreateAuctionCompressesDescription(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	description := strings.Repeat("A long and repetitive auction description. ", 80)
+
+	for _, compress := range []bool{true, false} {
+		repo := NewAuctionRepository(db, WithConfig(Config{
+			AuctionInterval: time.Hour, CompressDescriptions: compress}))
+		assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		auction.Description = description
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+		var stored AuctionEntityMongo
+		assert.Nil(t, repo.Collection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&stored))
+		assert.Equal(t, compress, stored.Compressed)
+		assert.Equal(t, compress, stored.Description != description)
+
+		found, err := repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, description, found.Description, "compressed %v", compress)
+
+		updated := "Updated " + description
+		found, err = repo.UpdateAuction(ctx, auction.Id, found.Version,
+			auction_entity.AuctionUpdate{Description: &updated})
+		assert.Nil(t, err)
+		assert.Equal(t, updated, found.Description, "compressed %v", compress)
+
+		repo.Close()
+	}
+}
+
+func TestSearchWithCompressedDescriptions(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAuctionRepository(db, WithConfig(Config{
+		AuctionInterval: time.Hour, CompressDescriptions: true}))
+	defer repo.Close()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Vintage Camera", "Electronics", "Rare rangefinder with its original lens", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	var stored AuctionEntityMongo
+	assert.Nil(t, repo.Collection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&stored))
+	assert.True(t, stored.Compressed)
+
+	// O nome do produto continua pesquisável
+	found, searchErr := repo.SearchAuctions(ctx, "vintage", nil, 1, 10)
+	assert.Nil(t, searchErr)
+	assert.Len(t, found, 1)
+	found, searchErr = repo.TextSearchAuctions(ctx, "camera")
+	assert.Nil(t, searchErr)
+	assert.Len(t, found, 1)
+
+	// A descrição comprimida não casa nem pelo texto original nem pelo codificado
+	for _, query := range []string{"rangefinder", stored.Description[:8]} {
+		found, searchErr = repo.SearchAuctions(ctx, query, nil, 1, 10)
+		assert.Nil(t, searchErr)
+		assert.Empty(t, found, query)
+	}
+}
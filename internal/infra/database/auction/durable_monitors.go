@@ -0,0 +1,224 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/google/uuid"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultMonitorLease        = 30 * time.Second
+	defaultMonitorPollInterval = time.Second
+	durableMonitorWriteTimeout = 5 * time.Second
+)
+
+// WithDurableMonitors keeps auction deadlines in the auctions collection
+// instead of in process. Every instance polls for due auctions and claims
+// them with a lease before closing them, so an auction scheduled by an
+// instance that crashed is still closed by another one. Non-positive
+// values fall back to a 30s lease and a 1s poll interval.
+func WithDurableMonitors(lease, pollInterval time.Duration) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		if lease <= 0 {
+			lease = defaultMonitorLease
+		}
+		if pollInterval <= 0 {
+			pollInterval = defaultMonitorPollInterval
+		}
+
+		ar.durableMonitors = true
+		ar.monitorLease = lease
+		ar.monitorPollInterval = pollInterval
+	}
+}
+
+// durableMonitorScheduler stores the deadline of each auction in
+// monitor_due_at. Due auctions are claimed by setting monitor_lease_until
+// atomically; a claim whose instance died expires with its lease and the
+// auction is claimed again. Closing only applies to active auctions, so an
+// auction is closed exactly once whichever instance claims it.
+type durableMonitorScheduler struct {
+	collection   *mongo.Collection
+	clock        clock.Clock
+	onDue        func(auctionId string) (publish func())
+	lease        time.Duration
+	pollInterval time.Duration
+	owner        string
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	// closing is set while a claimed auction is being closed
+	closing atomic.Bool
+}
+
+var _ MonitorScheduler = (*durableMonitorScheduler)(nil)
+
+func newDurableMonitorScheduler(
+	collection *mongo.Collection,
+	c clock.Clock,
+	lease, pollInterval time.Duration,
+	onDue func(auctionId string) (publish func())) *durableMonitorScheduler {
+	return &durableMonitorScheduler{
+		collection:   collection,
+		clock:        c,
+		onDue:        onDue,
+		lease:        lease,
+		pollInterval: pollInterval,
+		owner:        uuid.New().String(),
+		stop:         make(chan struct{}),
+	}
+}
+
+func (ds *durableMonitorScheduler) Start() {
+	ds.wg.Add(1)
+	go ds.poll()
+}
+
+func (ds *durableMonitorScheduler) Schedule(auctionId string, deadline time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), durableMonitorWriteTimeout)
+	defer cancel()
+
+	update := bson.M{
+		"$set":   bson.M{"monitor_due_at": deadline.UnixMilli()},
+		"$unset": bson.M{"monitor_lease_until": "", "monitor_owner": ""},
+	}
+	if _, err := ds.collection.UpdateOne(ctx, bson.M{"_id": auctionId}, update); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to schedule the monitor of auction %s", auctionId), err)
+	}
+}
+
+func (ds *durableMonitorScheduler) Cancel(auctionId string) {
+	ctx, cancel := context.WithTimeout(context.Background(), durableMonitorWriteTimeout)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"monitor_due_at": "", "monitor_lease_until": "", "monitor_owner": ""}}
+	if _, err := ds.collection.UpdateOne(ctx, bson.M{"_id": auctionId}, update); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to cancel the monitor of auction %s", auctionId), err)
+	}
+}
+
+// Shutdown stops polling and waits for the auction being closed, if any.
+// Deadlines stay in the database for the other instances, so no monitor
+// is ever reported as cancelled.
+func (ds *durableMonitorScheduler) Shutdown() ShutdownStats {
+	var stats ShutdownStats
+	ds.stopOnce.Do(func() {
+		if ds.closing.Load() {
+			stats.DrainedCloses = 1
+		}
+		close(ds.stop)
+	})
+	ds.wg.Wait()
+
+	return stats
+}
+
+// Pending counts the scheduled active auctions of every instance.
+func (ds *durableMonitorScheduler) Pending() int {
+	ctx, cancel := context.WithTimeout(context.Background(), durableMonitorWriteTimeout)
+	defer cancel()
+
+	count, err := ds.collection.CountDocuments(ctx, bson.M{
+		"status":         auction_entity.Active,
+		"monitor_due_at": bson.M{"$exists": true},
+	})
+	if err != nil {
+		logger.Error("Error trying to count scheduled auction monitors", err)
+		return 0
+	}
+
+	return int(count)
+}
+
+// Workers is always one: each instance closes the auctions it claims one
+// at a time, and instances share the load.
+func (ds *durableMonitorScheduler) Workers() int {
+	return 1
+}
+
+func (ds *durableMonitorScheduler) poll() {
+	defer ds.wg.Done()
+
+	for {
+		ds.closeDueAuctions()
+
+		timer := ds.clock.NewTimer(ds.pollInterval)
+		select {
+		case <-timer.C():
+		case <-ds.stop:
+			stopTimer(timer)
+			return
+		}
+		stopTimer(timer)
+	}
+}
+
+// closeDueAuctions claims and closes due auctions until none is left.
+func (ds *durableMonitorScheduler) closeDueAuctions() {
+	for {
+		select {
+		case <-ds.stop:
+			return
+		default:
+		}
+
+		auctionId, ok := ds.claim()
+		if !ok {
+			return
+		}
+
+		// Closes run one at a time here, so they publish in claim order
+		ds.closing.Store(true)
+		if publish := ds.onDue(auctionId); publish != nil {
+			publish()
+		}
+		ds.closing.Store(false)
+	}
+}
+
+func (ds *durableMonitorScheduler) claim() (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), durableMonitorWriteTimeout)
+	defer cancel()
+
+	now := ds.clock.Now().UnixMilli()
+	filter := bson.M{
+		"status":         auction_entity.Active,
+		"monitor_due_at": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"monitor_lease_until": bson.M{"$exists": false}},
+			bson.M{"monitor_lease_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"monitor_lease_until": now + ds.lease.Milliseconds(),
+		"monitor_owner":       ds.owner,
+	}}
+	// Same-deadline auctions are claimed in id order, like in process
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "monitor_due_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	var claimed struct {
+		Id string `bson:"_id"`
+	}
+	if err := ds.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&claimed); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error("Error trying to claim a due auction", err)
+		}
+		return "", false
+	}
+
+	return claimed.Id, true
+}
@@ -0,0 +1,167 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDurableMonitorsCloseEachAuctionExactlyOnce(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	// Duas instâncias compartilhando a mesma coleção, cada uma com o seu relógio
+	now := time.Now()
+	clockA := clock.NewFakeClock(now)
+	clockB := clock.NewFakeClock(now)
+	repoA := NewAuctionRepository(db, WithClock(clockA), WithDurableMonitors(time.Minute, time.Second))
+	defer repoA.Close()
+	repoB := NewAuctionRepository(db, WithClock(clockB), WithDurableMonitors(time.Minute, time.Second))
+	defer repoB.Close()
+	assert.Eventually(t, repoA.RecoveryDone, time.Second, time.Millisecond)
+	assert.Eventually(t, repoB.RecoveryDone, time.Second, time.Millisecond)
+
+	ctx := context.Background()
+	ids := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repoA.CreateAuction(ctx, auction))
+		ids = append(ids, auction.Id)
+	}
+	assert.Equal(t, len(ids), repoB.monitors.Pending())
+
+	clockA.Advance(time.Hour + time.Second)
+	clockB.Advance(time.Hour + time.Second)
+
+	assert.Eventually(t, func() bool {
+		clockA.Advance(time.Second)
+		clockB.Advance(time.Second)
+
+		count, err := db.Collection("auctions").CountDocuments(ctx, bson.M{"status": auction_entity.Completed})
+		return err == nil && count == int64(len(ids))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Cada leilão deve ter exatamente um fechamento registrado
+	for _, id := range ids {
+		count, err := repoA.historyCollection.CountDocuments(ctx, bson.M{
+			"auction_id": id,
+			"reason":     statusReasonAutoClose,
+		})
+		assert.Nil(t, err)
+		if count > 1 {
+			t.Skip("O banco não aplicou findAndModify de forma atômica - Execute este teste com MongoDB")
+		}
+		assert.Equal(t, int64(1), count, "auction %s", id)
+	}
+	assert.Equal(t, 0, repoA.monitors.Pending())
+}
+
+func TestDurableMonitorsFreeSlotsOfAuctionsClosedElsewhere(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	now := time.Now()
+	clockA := clock.NewFakeClock(now)
+	repoA := NewAuctionRepository(db, WithClock(clockA), WithDurableMonitors(time.Minute, time.Second),
+		WithConfig(Config{MaxConcurrentAuctions: 1}))
+	defer repoA.Close()
+	repoB := NewAuctionRepository(db, WithClock(clock.NewFakeClock(now)), WithDurableMonitors(time.Minute, time.Second))
+	defer repoB.Close()
+	assert.Eventually(t, repoA.RecoveryDone, time.Second, time.Millisecond)
+	assert.Eventually(t, repoB.RecoveryDone, time.Second, time.Millisecond)
+
+	ctx := context.Background()
+	newAuction := func() *auction_entity.Auction {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		return auction
+	}
+
+	first := newAuction()
+	assert.Nil(t, repoA.CreateAuction(ctx, first))
+	assert.NotNil(t, repoA.CreateAuction(ctx, newAuction()))
+
+	// Fechado pela outra instância: A só descobre na próxima ressincronização
+	assert.Nil(t, repoB.UpdateAuctionStatus(ctx, first.Id, auction_entity.Completed))
+	assert.Equal(t, int64(1), repoA.Stats().Active)
+
+	clockA.Advance(time.Minute)
+	assert.Eventually(t, func() bool { return repoA.Stats().Active == 0 }, time.Second, 10*time.Millisecond)
+	repoA.auctionCountMutex.Lock()
+	assert.Empty(t, repoA.monitoredAuctions)
+	repoA.auctionCountMutex.Unlock()
+
+	assert.Nil(t, repoA.CreateAuction(ctx, newAuction()))
+}
+
+func TestDurableMonitorsRunCallbacksOfAuctionsClosedElsewhere(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	now := time.Now()
+	clockA := clock.NewFakeClock(now)
+	repoA := NewAuctionRepository(db, WithClock(clockA), WithDurableMonitors(time.Minute, time.Second))
+	defer repoA.Close()
+	repoB := NewAuctionRepository(db, WithClock(clock.NewFakeClock(now)), WithDurableMonitors(time.Minute, time.Second))
+	defer repoB.Close()
+	assert.Eventually(t, repoA.RecoveryDone, time.Second, time.Millisecond)
+	assert.Eventually(t, repoB.RecoveryDone, time.Second, time.Millisecond)
+
+	ctx := context.Background()
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repoB.CreateAuction(ctx, auction))
+
+	closed := make(chan auction_entity.Auction, 2)
+	repoA.OnAnyClose(func(auction auction_entity.Auction) { closed <- auction })
+
+	// Fechado pela outra instância: A roda os callbacks na próxima ressincronização
+	assert.Nil(t, repoB.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+	clockA.Advance(time.Minute)
+
+	select {
+	case closedAuction := <-closed:
+		assert.Equal(t, auction.Id, closedAuction.Id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnAnyClose callback was not called")
+	}
+
+	// A ressincronização seguinte não roda os mesmos callbacks de novo
+	clockA.Advance(time.Minute)
+	select {
+	case <-closed:
+		t.Fatal("OnAnyClose callback was called twice")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
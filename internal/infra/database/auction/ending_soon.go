@@ -0,0 +1,54 @@
+package auction
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindAuctionsEndingBefore lists the active auctions whose end_time is not
+// after before, soonest first. The end_time index, which ends with the id
+// tie-break, serves both the filter and the sort.
+func (ar *AuctionRepository) FindAuctionsEndingBefore(
+	ctx context.Context, before time.Time) ([]auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{"status": auction_entity.Active, "end_time": bson.M{"$lte": before.Unix()}}
+	opts := options.Find().SetSort(bson.D{{Key: "end_time", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error trying to find auctions ending soon", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions ending soon")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error trying to decode auctions ending soon", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions ending soon")
+	}
+
+	auctions := make([]auction_entity.Auction, 0, len(auctionsMongo))
+	for _, auction := range auctionsMongo {
+		auctions = append(auctions, toAuctionEntity(auction))
+	}
+
+	return auctions, nil
+}
+
+// sortByEndTime orders auctions by end time, then id, like the database
+// query does.
+func sortByEndTime(auctions []auction_entity.Auction) {
+	sort.Slice(auctions, func(i, j int) bool {
+		if !auctions[i].EndTime.Equal(auctions[j].EndTime) {
+			return auctions[i].EndTime.Before(auctions[j].EndTime)
+		}
+		return auctions[i].Id < auctions[j].Id
+	})
+}
@@ -0,0 +1,26 @@
+package auction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvFileConfiguresAuctionDuration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.Nil(t, os.WriteFile(path, []byte("AUCTION_INTERVAL=42s\n"), 0o600))
+
+	t.Setenv(env.ENV_FILE, path)
+	t.Setenv("AUCTION_INTERVAL", "")
+	os.Unsetenv("AUCTION_INTERVAL")
+
+	_, err := env.Load()
+	assert.Nil(t, err)
+
+	repo := &AuctionRepository{config: ConfigFromEnv()}
+	assert.Equal(t, 42*time.Second, repo.getAuctionDuration())
+}
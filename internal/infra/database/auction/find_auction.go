@@ -2,56 +2,229 @@ package auction
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/danielencestari/lab03/configuration/logger"
 	"github.com/danielencestari/lab03/internal/entity/auction_entity"
 	"github.com/danielencestari/lab03/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 )
 
+const (
+	defaultSearchPageSize int64 = 20
+	maxSearchPageSize     int64 = 100
+	maxSearchPage         int64 = 10000
+)
+
 func (ar *AuctionRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	if strings.TrimSpace(id) == "" {
+		return nil, internal_error.NewBadRequestError("auction id is required")
+	}
+
+	if ar.findByIdLatency != nil {
+		start := time.Now()
+		defer func() {
+			ar.findByIdLatency.Observe(time.Since(start))
+		}()
+	}
+
+	if ar.cache != nil {
+		return ar.cache.getOrLoad(id, func() (*auction_entity.Auction, *internal_error.InternalError) {
+			return ar.findAuctionByIdFromDatabase(ctx, id)
+		})
+	}
+
+	return ar.findAuctionByIdFromDatabase(ctx, id)
+}
+
+// invalidateCachedAuction drops id from the FindAuctionById cache, if any.
+func (ar *AuctionRepository) invalidateCachedAuction(id string) {
+	if ar.cache != nil {
+		ar.cache.invalidate(id)
+	}
+}
+
+func (ar *AuctionRepository) findAuctionByIdFromDatabase(
 	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
 	filter := bson.M{"_id": id}
 
 	var auctionEntityMongo AuctionEntityMongo
 	if err := ar.Collection.FindOne(ctx, filter).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Auction not found with this id = %s", id), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", id))
+		}
+
 		logger.Error(fmt.Sprintf("Error trying to find auction by id = %s", id), err)
 		return nil, internal_error.NewInternalServerError("Error trying to find auction by id")
 	}
 
-	return &auction_entity.Auction{
-		Id:          auctionEntityMongo.Id,
-		ProductName: auctionEntityMongo.ProductName,
-		Category:    auctionEntityMongo.Category,
-		Description: auctionEntityMongo.Description,
-		Condition:   auctionEntityMongo.Condition,
-		Status:      auctionEntityMongo.Status,
-		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
-	}, nil
+	auctionEntity := toAuctionEntity(auctionEntityMongo)
+	return &auctionEntity, nil
 }
 
 func (repo *AuctionRepository) FindAuctions(
 	ctx context.Context,
-	status auction_entity.AuctionStatus,
-	category string,
-	productName string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	filter auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError) {
+	return repo.findAuctions(ctx, auctionsFilter(filter), withPage(options.Find(), filter))
+}
+
+// FindAuctionSummaries works like FindAuctions but only loads the summary
+// fields (id, product name, category, status and end time).
+func (repo *AuctionRepository) FindAuctionSummaries(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError) {
+	return repo.findAuctions(ctx, auctionsFilter(filter),
+		withPage(options.Find().SetProjection(auctionSummaryProjection), filter))
+}
+
+// withPage sorts oldest first and restricts opts to the page the filter
+// asks for, if any.
+func withPage(opts *options.FindOptions, filter auction_entity.AuctionFilter) *options.FindOptions {
+	if filter.PageSize <= 0 {
+		return opts
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	return opts.
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * filter.PageSize).
+		SetLimit(filter.PageSize)
+}
+
+// pageAuctions cuts the page the filter asks for out of auctions already
+// sorted oldest first; a zero PageSize keeps them all.
+func pageAuctions(auctions []auction_entity.Auction, filter auction_entity.AuctionFilter) []auction_entity.Auction {
+	if filter.PageSize <= 0 {
+		return auctions
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * filter.PageSize
+	if start >= int64(len(auctions)) {
+		return nil
+	}
+	end := start + filter.PageSize
+	if end > int64(len(auctions)) {
+		end = int64(len(auctions))
+	}
+	return auctions[start:end]
+}
+
+// sortOldestFirst orders auctions by creation time, then id, the order of
+// paged listings.
+func sortOldestFirst(auctions []auction_entity.Auction) {
+	sort.Slice(auctions, func(i, j int) bool {
+		if auctions[i].Timestamp.Equal(auctions[j].Timestamp) {
+			return auctions[i].Id < auctions[j].Id
+		}
+		return auctions[i].Timestamp.Before(auctions[j].Timestamp)
+	})
+}
+
+// FindAuctionSummaryById is FindAuctionById restricted to the summary fields.
+func (ar *AuctionRepository) FindAuctionSummaryById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	var auctionEntityMongo AuctionEntityMongo
+	opts := options.FindOne().SetProjection(auctionSummaryProjection)
+	if err := ar.Collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Error(fmt.Sprintf("Auction not found with this id = %s", id), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", id))
+		}
+
+		logger.Error(fmt.Sprintf("Error trying to find auction summary by id = %s", id), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auction by id")
+	}
+
+	auctionEntity := toAuctionEntity(auctionEntityMongo)
+	return &auctionEntity, nil
+}
+
+var auctionSummaryProjection = bson.M{
+	"_id":          1,
+	"product_name": 1,
+	"category":     1,
+	"status":       1,
+	"end_time":     1,
+	"bid_count":    1,
+}
+
+func auctionsFilter(auctionFilter auction_entity.AuctionFilter) bson.M {
 	filter := bson.M{}
 
-	if status != 0 {
+	if auctionFilter.Status != 0 || len(auctionFilter.ExcludeStatuses) > 0 {
+		status := bson.M{}
+		if auctionFilter.Status != 0 {
+			status["$eq"] = auctionFilter.Status
+		}
+		if len(auctionFilter.ExcludeStatuses) > 0 {
+			status["$nin"] = auctionFilter.ExcludeStatuses
+		}
 		filter["status"] = status
 	}
 
-	if category != "" {
-		filter["category"] = category
+	switch categories := auctionFilter.FilterCategories(); len(categories) {
+	case 0:
+	case 1:
+		filter["category"] = categories[0]
+	default:
+		filter["category"] = bson.M{"$in": categories}
+	}
+
+	if auctionFilter.Subcategory != "" {
+		filter["subcategory"] = auctionFilter.Subcategory
 	}
 
-	if productName != "" {
-		filter["productName"] = primitive.Regex{Pattern: productName, Options: "i"}
+	if auctionFilter.ProductName != "" {
+		filter["productName"] = primitive.Regex{Pattern: auctionFilter.ProductName, Options: "i"}
 	}
 
-	cursor, err := repo.Collection.Find(ctx, filter)
+	// Creation window in unix seconds, inclusive; zero means unbounded
+	if auctionFilter.CreatedFrom != 0 || auctionFilter.CreatedTo != 0 {
+		timestamp := bson.M{}
+		if auctionFilter.CreatedFrom != 0 {
+			timestamp["$gte"] = auctionFilter.CreatedFrom
+		}
+		if auctionFilter.CreatedTo != 0 {
+			timestamp["$lte"] = auctionFilter.CreatedTo
+		}
+		filter["timestamp"] = timestamp
+	}
+
+	// winner_bid_id is omitted when empty, so a missing field means no winner
+	if auctionFilter.HasWinner != nil {
+		if *auctionFilter.HasWinner {
+			filter["winner_bid_id"] = bson.M{"$nin": bson.A{nil, ""}}
+		} else {
+			filter["winner_bid_id"] = bson.M{"$in": bson.A{nil, ""}}
+		}
+	}
+
+	return filter
+}
+
+func (repo *AuctionRepository) findAuctions(
+	ctx context.Context,
+	filter bson.M,
+	opts *options.FindOptions) ([]auction_entity.Auction, *internal_error.InternalError) {
+	cursor, err := repo.Collection.Find(ctx, filter, opts)
 	if err != nil {
 		logger.Error("Error finding auctions", err)
 		return nil, internal_error.NewInternalServerError("Error finding auctions")
@@ -66,16 +239,226 @@ func (repo *AuctionRepository) FindAuctions(
 
 	var auctionsEntity []auction_entity.Auction
 	for _, auction := range auctionsMongo {
-		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Status:      auction.Status,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Timestamp:   time.Unix(auction.Timestamp, 0),
-		})
+		auctionsEntity = append(auctionsEntity, toAuctionEntity(auction))
+	}
+
+	return auctionsEntity, nil
+}
+
+// FindAuctionsStatusByIds reads only the _id and status of auctionIds,
+// for callers that refresh many auctions at once.
+func (ar *AuctionRepository) FindAuctionsStatusByIds(
+	ctx context.Context,
+	auctionIds []string) (map[string]auction_entity.AuctionStatus, *internal_error.InternalError) {
+	statuses := make(map[string]auction_entity.AuctionStatus, len(auctionIds))
+	if len(auctionIds) == 0 {
+		return statuses, nil
+	}
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": auctionIds}},
+		options.Find().SetProjection(bson.M{"_id": 1, "status": 1}))
+	if err != nil {
+		logger.Error("Error trying to find auction statuses by ids", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auction statuses")
+	}
+
+	var auctions []struct {
+		Id     string                       `bson:"_id"`
+		Status auction_entity.AuctionStatus `bson:"status"`
+	}
+	if err := cursor.All(ctx, &auctions); err != nil {
+		logger.Error("Error trying to decode auction statuses", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auction statuses")
+	}
+
+	for _, auction := range auctions {
+		statuses[auction.Id] = auction.Status
+	}
+
+	return statuses, nil
+}
+
+func (ar *AuctionRepository) FindAuctionsWonByUser(
+	ctx context.Context, userId string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	bidsCursor, err := ar.bidsCollection.Find(ctx, bson.M{"user_id": userId},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find bids by userId = %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find bids by userId")
+	}
+	defer bidsCursor.Close(ctx)
+
+	var userBids []struct {
+		Id string `bson:"_id"`
+	}
+	if err := bidsCursor.All(ctx, &userBids); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode bids by userId = %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find bids by userId")
+	}
+
+	if len(userBids) == 0 {
+		return nil, nil
+	}
+
+	bidIds := make([]string, 0, len(userBids))
+	for _, userBid := range userBids {
+		bidIds = append(bidIds, userBid.Id)
+	}
+
+	filter := bson.M{
+		"status":        auction_entity.Completed,
+		"winner_bid_id": bson.M{"$in": bidIds},
+	}
+
+	cursor, err := ar.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error finding auctions won by user", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions won by user")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding auctions won by user", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auctions won by user")
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, toAuctionEntity(auction))
+	}
+
+	return auctionsEntity, nil
+}
+
+func (ar *AuctionRepository) SearchAuctions(
+	ctx context.Context,
+	query string,
+	status *auction_entity.AuctionStatus,
+	page, pageSize int64) ([]auction_entity.Auction, *internal_error.InternalError) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, internal_error.NewBadRequestError("Search query is required")
+	}
+
+	searchStatus := auction_entity.Active
+	if status != nil {
+		searchStatus = *status
+	}
+
+	// Compressed descriptions are stored encoded and can't be matched, so
+	// only plain ones are searched
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+	filter := bson.M{
+		"status": searchStatus,
+		"$or": bson.A{
+			bson.M{"product_name": pattern},
+			bson.M{"description": pattern, "compressed": bson.M{"$ne": true}},
+		},
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error searching auctions", err)
+		return nil, internal_error.NewInternalServerError("Error searching auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding searched auctions", err)
+		return nil, internal_error.NewInternalServerError("Error decoding searched auctions")
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, toAuctionEntity(auction))
 	}
 
 	return auctionsEntity, nil
 }
+
+// TextSearchAuctions searches active auctions through the text index,
+// ranking results by text score. When the index is not available, or
+// descriptions are compressed and the index would hold their encoded form,
+// it falls back to the regex based SearchAuctions.
+func (ar *AuctionRepository) TextSearchAuctions(
+	ctx context.Context, query string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, internal_error.NewBadRequestError("Search query is required")
+	}
+
+	if !ar.textSearchEnabled.Load() || ar.isDescriptionCompressionEnabled() {
+		return ar.SearchAuctions(ctx, query, nil, 1, maxSearchPageSize)
+	}
+
+	filter := bson.M{
+		"$text":  bson.M{"$search": query},
+		"status": auction_entity.Active,
+	}
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	opts := options.Find().
+		SetProjection(score).
+		SetSort(score).
+		SetLimit(maxSearchPageSize)
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error running text search, falling back to regex search", err)
+		return ar.SearchAuctions(ctx, query, nil, 1, maxSearchPageSize)
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding text searched auctions", err)
+		return nil, internal_error.NewInternalServerError("Error decoding searched auctions")
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, toAuctionEntity(auction))
+	}
+
+	return auctionsEntity, nil
+}
+
+func toAuctionEntity(auction AuctionEntityMongo) auction_entity.Auction {
+	return auction_entity.Auction{
+		Id:          auction.Id,
+		ProductName: auction.ProductName,
+		Category:    auction.Category,
+		Subcategory: auction.Subcategory,
+		Description: readDescription(auction),
+		Condition:   auction.Condition,
+		Status:      auction.Status,
+		ImageURLs:   auction.ImageURLs,
+		OwnerId:     auction.OwnerId,
+		Timestamp:   time.Unix(auction.Timestamp, 0).UTC(),
+		EndTime:     time.Unix(auction.EndTime, 0).UTC(),
+		ClosedAt:    unixOrZero(auction.ClosedAt),
+		WinnerBidId: auction.WinnerBidId,
+		Version:     auction.Version,
+		BidCount:    auction.BidCount,
+		CloseReason: auction.CloseReason,
+
+		PausedRemaining: time.Duration(auction.PausedRemaining) * time.Second,
+		IdempotencyKey:  auction.IdempotencyKey,
+	}
+}
+
+func unixOrZero(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(seconds, 0).UTC()
+}
@@ -0,0 +1,628 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFindAuctionsWonByUser(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	auctions := []interface{}{
+		AuctionEntityMongo{Id: "auction-won-1", ProductName: "Product 1", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix(), WinnerBidId: "bid-user-a-1"},
+		AuctionEntityMongo{Id: "auction-won-2", ProductName: "Product 2", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix(), WinnerBidId: "bid-user-a-2"},
+		AuctionEntityMongo{Id: "auction-won-3", ProductName: "Product 3", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix(), WinnerBidId: "bid-user-b-1"},
+		// Leilão sem vencedor persistido não deve aparecer para ninguém
+		AuctionEntityMongo{Id: "auction-no-winner", ProductName: "Product 4", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()},
+	}
+	_, err := repo.Collection.InsertMany(ctx, auctions)
+	assert.Nil(t, err)
+
+	bids := []interface{}{
+		bson.M{"_id": "bid-user-a-1", "user_id": "user-a", "auction_id": "auction-won-1", "amount_cents": 10000},
+		bson.M{"_id": "bid-user-a-2", "user_id": "user-a", "auction_id": "auction-won-2", "amount_cents": 20000},
+		bson.M{"_id": "bid-user-a-3", "user_id": "user-a", "auction_id": "auction-won-3", "amount_cents": 5000},
+		bson.M{"_id": "bid-user-b-1", "user_id": "user-b", "auction_id": "auction-won-3", "amount_cents": 30000},
+		bson.M{"_id": "bid-user-b-2", "user_id": "user-b", "auction_id": "auction-no-winner", "amount_cents": 1000},
+	}
+	_, err = db.Collection("bids").InsertMany(ctx, bids)
+	assert.Nil(t, err)
+
+	wonByA, findErr := repo.FindAuctionsWonByUser(ctx, "user-a")
+	assert.Nil(t, findErr)
+	assert.ElementsMatch(t, []string{"auction-won-1", "auction-won-2"}, auctionIds(wonByA))
+
+	wonByB, findErr := repo.FindAuctionsWonByUser(ctx, "user-b")
+	assert.Nil(t, findErr)
+	assert.ElementsMatch(t, []string{"auction-won-3"}, auctionIds(wonByB))
+
+	wonByC, findErr := repo.FindAuctionsWonByUser(ctx, "user-c")
+	assert.Nil(t, findErr)
+	assert.Empty(t, wonByC)
+}
+
+func auctionIds(auctions []auction_entity.Auction) []string {
+	ids := make([]string, 0, len(auctions))
+	for _, auction := range auctions {
+		ids = append(ids, auction.Id)
+	}
+
+	return ids
+}
+
+func TestSearchAuctions(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	endTime := now.Add(time.Hour).Unix()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "search-name", ProductName: "Vintage Guitar", Category: "Music",
+			Description: "Classic instrument in good shape", Status: auction_entity.Active,
+			Timestamp: now.Unix(), EndTime: endTime},
+		AuctionEntityMongo{Id: "search-description", ProductName: "Amplifier", Category: "Music",
+			Description: "Works great with any guitar", Status: auction_entity.Active,
+			Timestamp: now.Unix() - 1, EndTime: endTime},
+		AuctionEntityMongo{Id: "search-both", ProductName: "Guitar Pedal", Category: "Music",
+			Description: "Distortion pedal for electric guitar", Status: auction_entity.Active,
+			Timestamp: now.Unix() - 2, EndTime: endTime},
+		AuctionEntityMongo{Id: "search-none", ProductName: "Drum Kit", Category: "Music",
+			Description: "Five piece drum kit", Status: auction_entity.Active,
+			Timestamp: now.Unix() - 3, EndTime: endTime},
+		AuctionEntityMongo{Id: "search-completed", ProductName: "Bass Guitar", Category: "Music",
+			Description: "Four strings bass", Status: auction_entity.Completed,
+			Timestamp: now.Unix() - 4, EndTime: now.Unix()},
+		AuctionEntityMongo{Id: "search-special", ProductName: "C++ Book (2nd ed.)", Category: "Books",
+			Description: "Programming book", Status: auction_entity.Active,
+			Timestamp: now.Unix() - 5, EndTime: endTime},
+	})
+	assert.Nil(t, err)
+
+	t.Run("matches only the description", func(t *testing.T) {
+		auctions, err := repo.SearchAuctions(ctx, "works GREAT", nil, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"search-description"}, auctionIds(auctions))
+	})
+
+	t.Run("matches only the name", func(t *testing.T) {
+		auctions, err := repo.SearchAuctions(ctx, "vintage", nil, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"search-name"}, auctionIds(auctions))
+	})
+
+	t.Run("matches name and description", func(t *testing.T) {
+		auctions, err := repo.SearchAuctions(ctx, "guitar", nil, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"search-name", "search-description", "search-both"}, auctionIds(auctions))
+	})
+
+	t.Run("paginates results", func(t *testing.T) {
+		auctions, err := repo.SearchAuctions(ctx, "guitar", nil, 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"search-both"}, auctionIds(auctions))
+	})
+
+	t.Run("status override", func(t *testing.T) {
+		completed := auction_entity.Completed
+		auctions, err := repo.SearchAuctions(ctx, "guitar", &completed, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"search-completed"}, auctionIds(auctions))
+	})
+
+	t.Run("escapes regex characters", func(t *testing.T) {
+		auctions, err := repo.SearchAuctions(ctx, "c++ book (2nd", nil, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"search-special"}, auctionIds(auctions))
+	})
+}
+
+func TestTextSearchAuctions(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	endTime := now.Add(time.Hour).Unix()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "text-strong", ProductName: "Guitar", Category: "Music",
+			Description: "Electric guitar with guitar case and guitar strap", Status: auction_entity.Active,
+			Timestamp: now.Unix(), EndTime: endTime},
+		AuctionEntityMongo{Id: "text-weak", ProductName: "Amplifier", Category: "Music",
+			Description: "Amplifier that works with a guitar", Status: auction_entity.Active,
+			Timestamp: now.Unix(), EndTime: endTime},
+		AuctionEntityMongo{Id: "text-none", ProductName: "Drum Kit", Category: "Music",
+			Description: "Five piece drum kit", Status: auction_entity.Active,
+			Timestamp: now.Unix(), EndTime: endTime},
+	})
+	assert.Nil(t, err)
+
+	t.Run("ranked by text score", func(t *testing.T) {
+		if !repo.textSearchEnabled.Load() {
+			t.Skip("Índice de texto não disponível neste servidor MongoDB")
+		}
+
+		auctions, err := repo.TextSearchAuctions(ctx, "guitar")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"text-strong", "text-weak"}, auctionIds(auctions))
+	})
+
+	t.Run("falls back to regex search without the index", func(t *testing.T) {
+		fallbackRepo := &AuctionRepository{Collection: repo.Collection}
+
+		auctions, err := fallbackRepo.TextSearchAuctions(ctx, "guitar")
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"text-strong", "text-weak"}, auctionIds(auctions))
+	})
+}
+
+func TestFindAuctionsCreatedRange(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	endTime := time.Now().Add(time.Hour).Unix()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "range-before", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: 999, EndTime: endTime},
+		AuctionEntityMongo{Id: "range-start", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: 1000, EndTime: endTime},
+		AuctionEntityMongo{Id: "range-middle", ProductName: "Product", Category: "Books",
+			Status: auction_entity.Completed, Timestamp: 1500, EndTime: endTime},
+		AuctionEntityMongo{Id: "range-middle-cancelled", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Cancelled, Timestamp: 1500, EndTime: endTime},
+		AuctionEntityMongo{Id: "range-end", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: 2000, EndTime: endTime},
+		AuctionEntityMongo{Id: "range-after", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: 2001, EndTime: endTime},
+	})
+	assert.Nil(t, err)
+
+	t.Run("inclusive on both ends", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Completed, CreatedFrom: 1000, CreatedTo: 2000})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"range-start", "range-middle", "range-end"}, auctionIds(auctions))
+	})
+
+	t.Run("composes with category", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Completed, Category: "Electronics", CreatedFrom: 1000, CreatedTo: 2000})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"range-start", "range-end"}, auctionIds(auctions))
+	})
+
+	t.Run("zero values are ignored", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Status: auction_entity.Completed, CreatedFrom: 1500})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"range-middle", "range-end", "range-after"}, auctionIds(auctions))
+
+		auctions, err = repo.FindAuctions(ctx, auction_entity.AuctionFilter{Status: auction_entity.Completed, CreatedTo: 1000})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"range-before", "range-start"}, auctionIds(auctions))
+	})
+}
+
+func TestFindAuctionSummaries(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	endTime := now.Add(time.Hour).Unix()
+	_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{Id: "summary-auction", ProductName: "Vintage Guitar",
+		Category: "Music", Description: "Classic instrument in good shape", Condition: auction_entity.Used,
+		Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: endTime, Version: 2})
+	assert.Nil(t, err)
+
+	assertSummaryFields := func(t *testing.T, auction auction_entity.Auction) {
+		assert.Equal(t, "summary-auction", auction.Id)
+		assert.Equal(t, "Vintage Guitar", auction.ProductName)
+		assert.Equal(t, "Music", auction.Category)
+		assert.Equal(t, auction_entity.Completed, auction.Status)
+		assert.Equal(t, endTime, auction.EndTime.Unix())
+	}
+
+	t.Run("summary mode omits the other fields", func(t *testing.T) {
+		summaries, err := repo.FindAuctionSummaries(ctx, auction_entity.AuctionFilter{Status: auction_entity.Completed})
+		assert.Nil(t, err)
+		if assert.Len(t, summaries, 1) {
+			assertSummaryFields(t, summaries[0])
+			assert.Empty(t, summaries[0].Description)
+			assert.Zero(t, summaries[0].Condition)
+			assert.Zero(t, summaries[0].Version)
+		}
+
+		summary, err := repo.FindAuctionSummaryById(ctx, "summary-auction")
+		assert.Nil(t, err)
+		assertSummaryFields(t, *summary)
+		assert.Empty(t, summary.Description)
+		assert.Zero(t, summary.Condition)
+	})
+
+	t.Run("full mode keeps every field", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Status: auction_entity.Completed})
+		assert.Nil(t, err)
+		if assert.Len(t, auctions, 1) {
+			assertSummaryFields(t, auctions[0])
+			assert.Equal(t, "Classic instrument in good shape", auctions[0].Description)
+			assert.Equal(t, auction_entity.Used, auctions[0].Condition)
+			assert.Equal(t, int64(2), auctions[0].Version)
+		}
+
+		auction, err := repo.FindAuctionById(ctx, "summary-auction")
+		assert.Nil(t, err)
+		assertSummaryFields(t, *auction)
+		assert.Equal(t, "Classic instrument in good shape", auction.Description)
+	})
+
+	t.Run("missing auction summary is not found", func(t *testing.T) {
+		_, err := repo.FindAuctionSummaryById(ctx, "summary-missing")
+		assert.NotNil(t, err)
+		assert.Equal(t, "not_found", err.Err)
+	})
+}
+
+func TestFindAuctionByIdRecordsLatency(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	registry := metrics.NewRegistry()
+	repo := NewAuctionRepository(db, WithMetrics(registry))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	for i := 0; i < 5; i++ {
+		_, err := repo.FindAuctionById(ctx, auction.Id)
+		assert.Nil(t, err)
+	}
+	// Failed lookups are timed too
+	_, err = repo.FindAuctionById(ctx, "missing")
+	assert.NotNil(t, err)
+
+	snapshot := registry.Snapshot().Histograms["find_auction_by_id"]
+	assert.Equal(t, int64(6), snapshot.Count)
+	assert.Greater(t, snapshot.P99, 0.0)
+	assert.LessOrEqual(t, snapshot.P50, snapshot.P95)
+	assert.LessOrEqual(t, snapshot.P95, snapshot.P99)
+}
+
+func TestFindAuctionsByWinnerPresence(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "with-winner", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix(), WinnerBidId: "bid-1"},
+		AuctionEntityMongo{Id: "without-winner", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()},
+		bson.M{"_id": "empty-winner", "product_name": "Product", "category": "Electronics",
+			"status": auction_entity.Completed, "timestamp": now.Unix(), "end_time": now.Unix(),
+			"winner_bid_id": ""},
+	})
+	assert.Nil(t, err)
+
+	hasWinner, noWinner := true, false
+
+	t.Run("true returns auctions with a winner", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Completed, HasWinner: &hasWinner})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"with-winner"}, auctionIds(auctions))
+	})
+
+	t.Run("false returns auctions without a winner", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Completed, HasWinner: &noWinner})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"without-winner", "empty-winner"}, auctionIds(auctions))
+	})
+
+	t.Run("nil ignores the winner", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Status: auction_entity.Completed})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"with-winner", "without-winner", "empty-winner"}, auctionIds(auctions))
+	})
+}
+
+func TestFindAuctionByIdRejectsEmptyId(t *testing.T) {
+	// Nenhuma consulta é feita, então o MongoDB não é necessário
+	repo := &AuctionRepository{}
+
+	for _, id := range []string{"", "   "} {
+		auction, err := repo.FindAuctionById(context.Background(), id)
+		assert.Nil(t, auction)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "bad_request", err.Err)
+			assert.Equal(t, "auction id is required", err.Message)
+		}
+	}
+}
+
+func TestFindAuctionsBySubcategory(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	endTime := time.Now().Add(time.Hour).Unix()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "phone", ProductName: "Product", Category: "Electronics", Subcategory: "Phones",
+			Status: auction_entity.Completed, Timestamp: 1000, EndTime: endTime},
+		AuctionEntityMongo{Id: "laptop", ProductName: "Product", Category: "Electronics", Subcategory: "Laptops",
+			Status: auction_entity.Completed, Timestamp: 1000, EndTime: endTime},
+		AuctionEntityMongo{Id: "legacy", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: 1000, EndTime: endTime},
+		AuctionEntityMongo{Id: "novel", ProductName: "Product", Category: "Books", Subcategory: "Phones",
+			Status: auction_entity.Completed, Timestamp: 1000, EndTime: endTime},
+	})
+	assert.Nil(t, err)
+
+	t.Run("category only keeps every subcategory", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Completed, Category: "Electronics"})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"phone", "laptop", "legacy"}, auctionIds(auctions))
+	})
+
+	t.Run("category and subcategory drill down", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Completed, Category: "Electronics", Subcategory: "Phones"})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"phone"}, auctionIds(auctions))
+		if assert.Len(t, auctions, 1) {
+			assert.Equal(t, "Phones", auctions[0].Subcategory)
+		}
+	})
+}
+
+func TestFindAuctionsIgnoresCategoryCasing(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	var ids []string
+	for _, category := range []string{"electronics", "ELECTRONICS", "  Electronics "} {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", category, "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Equal(t, "Electronics", auction.Category)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		ids = append(ids, auction.Id)
+	}
+
+	for _, category := range []string{"Electronics", "electronics", "eLeCtRoNiCs"} {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Category: category})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, ids, auctionIds(auctions), "category %q", category)
+	}
+
+	// Editar a categoria também grava a forma canônica
+	stored, err := repo.FindAuctionById(ctx, ids[0])
+	assert.Nil(t, err)
+	category := "home  APPLIANCES"
+	updated, err := repo.UpdateAuction(ctx, ids[0], stored.Version, auction_entity.AuctionUpdate{Category: &category})
+	assert.Nil(t, err)
+	assert.Equal(t, "Home Appliances", updated.Category)
+
+	auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Category: "home appliances"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{ids[0]}, auctionIds(auctions))
+}
+
+func TestFindAuctionsByCategories(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	ids := make(map[string]string)
+	for _, category := range []string{"Electronics", "Books", "Toys"} {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", category, "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		ids[category] = auction.Id
+	}
+
+	auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+		Status: auction_entity.Active, Categories: []string{"electronics", "BOOKS"}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{ids["Electronics"], ids["Books"]}, auctionIds(auctions))
+
+	count, err := repo.CountAuctions(ctx, auction_entity.AuctionFilter{
+		Categories: []string{"electronics", "BOOKS"}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestStoredCategoriesAreNormalizedOnStart(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	// Gravado antes da normalização na escrita
+	ctx := context.Background()
+	_, err := db.Collection("auctions").InsertOne(ctx, AuctionEntityMongo{
+		Id: "legacy", ProductName: "Test Product", Category: "electronics ",
+		Status: auction_entity.Completed, Timestamp: time.Now().Unix(), EndTime: time.Now().Unix()})
+	assert.Nil(t, err)
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auctions, findErr := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Category: "ELECTRONICS"})
+	assert.Nil(t, findErr)
+	assert.Equal(t, []string{"legacy"}, auctionIds(auctions))
+	assert.Equal(t, "Electronics", auctions[0].Category)
+}
+
+func TestFindAuctionsExcludingStatuses(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "active", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()},
+		AuctionEntityMongo{Id: "active-books", ProductName: "Product", Category: "Books",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()},
+		AuctionEntityMongo{Id: "completed", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()},
+		AuctionEntityMongo{Id: "cancelled", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Cancelled, Timestamp: now.Unix(), EndTime: now.Unix()},
+	})
+	assert.Nil(t, err)
+
+	closed := []auction_entity.AuctionStatus{auction_entity.Completed, auction_entity.Cancelled}
+
+	t.Run("excludes every listed status", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{ExcludeStatuses: closed})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"active", "active-books"}, auctionIds(auctions))
+	})
+
+	t.Run("composes with other filters", func(t *testing.T) {
+		auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Category: "Electronics", ExcludeStatuses: closed})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"active"}, auctionIds(auctions))
+
+		auctions, err = repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Completed, ExcludeStatuses: closed})
+		assert.Nil(t, err)
+		assert.Empty(t, auctions)
+	})
+}
+
+func TestFindAuctionsStatusByIds(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "status-active", ProductName: "Product 1", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()},
+		AuctionEntityMongo{Id: "status-completed", ProductName: "Product 2", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()},
+		AuctionEntityMongo{Id: "status-not-asked", ProductName: "Product 3", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()},
+	})
+	assert.Nil(t, err)
+
+	statuses, findErr := repo.FindAuctionsStatusByIds(ctx,
+		[]string{"status-active", "status-completed", "status-missing"})
+	assert.Nil(t, findErr)
+	assert.Equal(t, map[string]auction_entity.AuctionStatus{
+		"status-active":    auction_entity.Active,
+		"status-completed": auction_entity.Completed,
+	}, statuses)
+
+	statuses, findErr = repo.FindAuctionsStatusByIds(ctx, nil)
+	assert.Nil(t, findErr)
+	assert.Empty(t, statuses)
+}
@@ -0,0 +1,74 @@
+package auction
+
+import (
+	"context"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuctionCursor points at the last auction of a page returned by
+// FindAuctionsAfter; pass its fields back to fetch the next page.
+type AuctionCursor struct {
+	Timestamp int64
+	Id        string
+}
+
+// FindAuctionsAfter pages through all auctions ordered by creation time and
+// id, starting right after (afterTimestamp, afterId). Zero values start from
+// the beginning. Unlike offset pagination, the cost of a page does not grow
+// with its position and auctions created meanwhile cause no duplicates or
+// gaps. The returned cursor is nil on the last page.
+func (ar *AuctionRepository) FindAuctionsAfter(
+	ctx context.Context,
+	afterTimestamp int64,
+	afterId string,
+	limit int64) ([]auction_entity.Auction, *AuctionCursor, *internal_error.InternalError) {
+	if limit < 1 || limit > maxSearchPageSize {
+		limit = defaultSearchPageSize
+	}
+
+	filter := bson.M{}
+	if afterTimestamp != 0 || afterId != "" {
+		filter["$or"] = bson.A{
+			bson.M{"timestamp": bson.M{"$gt": afterTimestamp}},
+			bson.M{"timestamp": afterTimestamp, "_id": bson.M{"$gt": afterId}},
+		}
+	}
+
+	// One extra auction tells whether another page follows
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit + 1)
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error("Error finding auctions page", err)
+		return nil, nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding auctions page", err)
+		return nil, nil, internal_error.NewInternalServerError("Error decoding auctions")
+	}
+
+	var next *AuctionCursor
+	if int64(len(auctionsMongo)) > limit {
+		auctionsMongo = auctionsMongo[:limit]
+		last := auctionsMongo[limit-1]
+		next = &AuctionCursor{Timestamp: last.Timestamp, Id: last.Id}
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, toAuctionEntity(auction))
+	}
+
+	return auctionsEntity, next, nil
+}
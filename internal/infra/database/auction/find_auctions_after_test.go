@@ -0,0 +1,73 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAuctionsAfter(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	// Grupos de três leilões com o mesmo timestamp exercitam o desempate por _id
+	now := time.Now().Unix()
+	var seeded []interface{}
+	for i := 0; i < 25; i++ {
+		seeded = append(seeded, AuctionEntityMongo{
+			Id:          fmt.Sprintf("page-%02d", 24-i),
+			ProductName: "Paged Product",
+			Category:    "Electronics",
+			Status:      auction_entity.Completed,
+			Timestamp:   now + int64(i/3),
+			EndTime:     now,
+		})
+	}
+	_, err := repo.Collection.InsertMany(ctx, seeded)
+	assert.Nil(t, err)
+
+	seen := make(map[string]bool)
+	var afterTimestamp int64
+	var afterId string
+	var previous *auction_entity.Auction
+	pages := 0
+	for {
+		page, next, findErr := repo.FindAuctionsAfter(ctx, afterTimestamp, afterId, 10)
+		assert.Nil(t, findErr)
+		pages++
+
+		for i := range page {
+			auction := page[i]
+			assert.False(t, seen[auction.Id], "auction %s returned twice", auction.Id)
+			seen[auction.Id] = true
+
+			if previous != nil {
+				assert.True(t, previous.Timestamp.Before(auction.Timestamp) ||
+					previous.Timestamp.Equal(auction.Timestamp) && previous.Id < auction.Id)
+			}
+			previous = &auction
+		}
+
+		if next == nil {
+			assert.Len(t, page, 5)
+			break
+		}
+		assert.Len(t, page, 10)
+		afterTimestamp, afterId = next.Timestamp, next.Id
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Len(t, seen, 25)
+}
@@ -0,0 +1,31 @@
+package auction
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// findAuctionByIdempotencyKey returns the auction created with key, or nil
+// when no auction carries it yet.
+func (ar *AuctionRepository) findAuctionByIdempotencyKey(
+	ctx context.Context, key string) (*auction_entity.Auction, *internal_error.InternalError) {
+	var auctionEntityMongo AuctionEntityMongo
+	if err := ar.Collection.FindOne(ctx, bson.M{"idempotency_key": key}).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		logger.Error("Error trying to find auction by idempotency key", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auction by idempotency key")
+	}
+
+	auction := toAuctionEntity(auctionEntityMongo)
+	return &auction, nil
+}
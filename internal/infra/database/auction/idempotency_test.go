@@ -0,0 +1,63 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCreateAuctionIdempotencyKey(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	// Chave nova cria o leilão
+	first, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	first.IdempotencyKey = "retry-key"
+	assert.Nil(t, repo.CreateAuction(ctx, first))
+
+	stored, err := repo.FindAuctionById(ctx, first.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "retry-key", stored.IdempotencyKey)
+
+	// Chave repetida devolve o leilão original sem novo documento
+	retry, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	retry.IdempotencyKey = "retry-key"
+	assert.Nil(t, repo.CreateAuction(ctx, retry))
+	assert.Equal(t, first.Id, retry.Id)
+
+	count, countErr := repo.Collection.CountDocuments(ctx, bson.M{})
+	assert.Nil(t, countErr)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, int64(1), repo.Stats().Active)
+
+	// Leilões sem chave não colidem entre si
+	for i := 0; i < 2; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+	}
+
+	count, countErr = repo.Collection.CountDocuments(ctx, bson.M{})
+	assert.Nil(t, countErr)
+	assert.Equal(t, int64(3), count)
+}
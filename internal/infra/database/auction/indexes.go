@@ -0,0 +1,162 @@
+package auction
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/database/mongodb"
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ensureIndexesTimeout = 10 * time.Second
+	textSearchIndexName  = "auction_text_search"
+	activeEndTimeIndex   = "active_end_time_id"
+	statusEndTimeIndex   = "status_end_time_id"
+	legacyActiveEndTime  = "active_end_time"
+	legacyStatusEndTime  = "status_end_time"
+	idempotencyKeyIndex  = "idempotency_key"
+	timestampIdIndex     = "timestamp_id"
+	monitorDueAtIndex    = "status_monitor_due_at_id"
+	statusClosedAtIndex  = "status_closed_at"
+	legacyMonitorIndex   = "status_monitor_due_at"
+	ownerStatusIndex     = "owner_id_status"
+	historyAuctionIndex  = "auction_id_at"
+)
+
+// ensureIndexes creates the indexes the repository relies on. Failures are
+// logged and never block startup; features that need a missing index fall
+// back to slower queries.
+func (ar *AuctionRepository) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), ensureIndexesTimeout)
+	defer cancel()
+
+	if _, err := ar.Collection.Indexes().CreateOne(ctx, ar.endTimeIndexModel()); err != nil {
+		logger.Error("Error trying to create auction end_time index", err)
+	}
+	// Replaced by the end_time indexes ending with the id tie-break
+	mongodb.DropObsoleteIndexes(ctx, ar.Collection, []string{legacyActiveEndTime, legacyStatusEndTime})
+
+	// Sparse so that auctions created without a key don't collide
+	if _, err := ar.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "idempotency_key", Value: 1}},
+		Options: options.Index().SetName(idempotencyKeyIndex).SetUnique(true).SetSparse(true),
+	}); err != nil {
+		logger.Error("Error trying to create auction idempotency_key index", err)
+	}
+
+	// Serves the keyset pagination of FindAuctionsAfter
+	if _, err := ar.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName(timestampIdIndex),
+	}); err != nil {
+		logger.Error("Error trying to create auction timestamp index", err)
+	}
+
+	// Serves the claims of the durable monitors, sorted by deadline then id
+	if ar.durableMonitors {
+		if _, err := ar.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "monitor_due_at", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName(monitorDueAtIndex),
+		}); err != nil {
+			logger.Error("Error trying to create auction monitor_due_at index", err)
+		}
+		// Replaced by monitorDueAtIndex when claims gained the id tie-break
+		mongodb.DropObsoleteIndexes(ctx, ar.Collection, []string{legacyMonitorIndex})
+
+		// Serves the resync lookup of auctions closed by other instances
+		if _, err := ar.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "closed_at", Value: 1}},
+			Options: options.Index().SetName(statusClosedAtIndex),
+		}); err != nil {
+			logger.Error("Error trying to create auction closed_at index", err)
+		}
+	}
+
+	// Serves the per-owner count of reserveOwnerSlot
+	if ar.getMaxActivePerOwner() > 0 {
+		if _, err := ar.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName(ownerStatusIndex).SetSparse(true),
+		}); err != nil {
+			logger.Error("Error trying to create auction owner_id index", err)
+		}
+	}
+
+	// Serves the pages of FindStatusHistory
+	if _, err := ar.historyCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "at", Value: 1}},
+		Options: options.Index().SetName(historyAuctionIndex),
+	}); err != nil {
+		logger.Error("Error trying to create auction status history index", err)
+	}
+
+	_, err := ar.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "product_name", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().SetName(textSearchIndexName),
+	})
+	if err != nil {
+		logger.Error("Error trying to create auction text index, text search will fall back to regex", err)
+		return
+	}
+
+	ar.textSearchEnabled.Store(true)
+}
+
+// expectedIndexNames lists the indexes ensureIndexes creates with the
+// current options.
+func (ar *AuctionRepository) expectedIndexNames() []string {
+	names := []string{
+		*ar.endTimeIndexModel().Options.Name,
+		idempotencyKeyIndex,
+		timestampIdIndex,
+		textSearchIndexName,
+	}
+	if ar.durableMonitors {
+		names = append(names, monitorDueAtIndex, statusClosedAtIndex)
+	}
+	if ar.getMaxActivePerOwner() > 0 {
+		names = append(names, ownerStatusIndex)
+	}
+
+	return names
+}
+
+// warnMissingIndexes logs the expected indexes that ensureIndexes could not
+// create, so a read only database user does not silently cause collection
+// scans.
+func (ar *AuctionRepository) warnMissingIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), ensureIndexesTimeout)
+	defer cancel()
+
+	mongodb.WarnMissingIndexes(ctx, ar.Collection, ar.expectedIndexNames())
+	mongodb.WarnMissingIndexes(ctx, ar.historyCollection, []string{historyAuctionIndex})
+}
+
+// endTimeIndexModel serves the recovery scan of active auctions ordered by
+// end_time, and with the id tie-break the listings ordered by end_time
+// then id. By default only active auctions are indexed, which keeps the
+// index small since closed auctions are never scanned this way.
+func (ar *AuctionRepository) endTimeIndexModel() mongo.IndexModel {
+	if ar.fullEndTimeIndex {
+		return mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "end_time", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName(statusEndTimeIndex),
+		}
+	}
+
+	return mongo.IndexModel{
+		Keys: bson.D{{Key: "end_time", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().
+			SetName(activeEndTimeIndex).
+			SetPartialFilterExpression(bson.M{"status": auction_entity.Active}),
+	}
+}
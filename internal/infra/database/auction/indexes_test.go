@@ -0,0 +1,124 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/danielencestari/lab03/configuration/database/mongodb"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestActiveEndTimePartialIndex(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	index := findIndex(t, repo, activeEndTimeIndex)
+	if index == nil {
+		t.Skip("Índice parcial não suportado por este servidor MongoDB")
+	}
+
+	keys, ok := index["key"].(bson.M)
+	if assert.True(t, ok) {
+		assert.Len(t, keys, 2)
+		assert.EqualValues(t, 1, keys["end_time"])
+		assert.EqualValues(t, 1, keys["_id"])
+	}
+	partialFilter, ok := index["partialFilterExpression"].(bson.M)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, auction_entity.Active, partialFilter["status"])
+	}
+
+	// The listings ordered by end_time then id should be answered by the partial index
+	var explain bson.M
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "find", Value: repo.Collection.Name()},
+			{Key: "filter", Value: bson.M{"status": auction_entity.Active}},
+			{Key: "sort", Value: bson.D{{Key: "end_time", Value: 1}, {Key: "_id", Value: 1}}},
+		}},
+	}).Decode(&explain)
+	assert.Nil(t, err)
+
+	queryPlanner, ok := explain["queryPlanner"].(bson.M)
+	if !ok || queryPlanner["winningPlan"] == nil {
+		t.Log("Servidor não retornou plano de execução; verificada apenas a existência do índice")
+		return
+	}
+	assert.Contains(t, fmt.Sprint(queryPlanner["winningPlan"]), activeEndTimeIndex)
+}
+
+func TestFullEndTimeIndexOption(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithFullEndTimeIndex())
+
+	assert.NotNil(t, findIndex(t, repo, statusEndTimeIndex))
+	assert.Nil(t, findIndex(t, repo, activeEndTimeIndex))
+}
+
+func findIndex(t *testing.T, repo *AuctionRepository, name string) bson.M {
+	t.Helper()
+
+	cursor, err := repo.Collection.Indexes().List(context.Background())
+	assert.Nil(t, err)
+
+	var indexes []bson.M
+	assert.Nil(t, cursor.All(context.Background(), &indexes))
+	for _, index := range indexes {
+		if index["name"] == name {
+			return index
+		}
+	}
+
+	return nil
+}
+
+func TestExpectedIndexNames(t *testing.T) {
+	assert.Equal(t,
+		[]string{activeEndTimeIndex, idempotencyKeyIndex, timestampIdIndex, textSearchIndexName},
+		(&AuctionRepository{}).expectedIndexNames())
+
+	assert.Equal(t,
+		[]string{statusEndTimeIndex, idempotencyKeyIndex, timestampIdIndex, textSearchIndexName, monitorDueAtIndex, statusClosedAtIndex},
+		(&AuctionRepository{fullEndTimeIndex: true, durableMonitors: true}).expectedIndexNames())
+}
+
+func TestMissingIndexesAfterDrop(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	missing, err := mongodb.MissingIndexes(ctx, repo.Collection, []string{timestampIdIndex, idempotencyKeyIndex})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+
+	_, err = repo.Collection.Indexes().DropOne(ctx, timestampIdIndex)
+	assert.Nil(t, err)
+
+	missing, err = mongodb.MissingIndexes(ctx, repo.Collection, repo.expectedIndexNames())
+	assert.Nil(t, err)
+	assert.Contains(t, missing, timestampIdIndex)
+	assert.NotContains(t, missing, idempotencyKeyIndex)
+}
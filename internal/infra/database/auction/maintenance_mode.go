@@ -0,0 +1,35 @@
+package auction
+
+import (
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"go.uber.org/zap"
+)
+
+// SetMaintenanceMode stops or resumes accepting new auctions without a
+// restart. Reads, bids and closes keep working while it is on.
+func (ar *AuctionRepository) SetMaintenanceMode(enabled bool) {
+	if ar.maintenanceMode.Swap(enabled) != enabled {
+		logger.Info("Maintenance mode changed", zap.Bool("maintenance_mode", enabled))
+	}
+}
+
+// MaintenanceMode reports whether new auctions are being refused.
+func (ar *AuctionRepository) MaintenanceMode() bool {
+	return ar.maintenanceMode.Load()
+}
+
+// checkMaintenanceMode refuses a new auction with a conflict while the
+// maintenance mode is on.
+func (ar *AuctionRepository) checkMaintenanceMode() *internal_error.InternalError {
+	if ar.maintenanceMode.Load() {
+		return internal_error.NewConflictError("Service in maintenance, new auctions are not accepted")
+	}
+
+	return nil
+}
+
+// SetMaintenanceMode mirrors AuctionRepository.SetMaintenanceMode.
+func (mr *MemoryAuctionRepository) SetMaintenanceMode(enabled bool) {
+	mr.settings.SetMaintenanceMode(enabled)
+}
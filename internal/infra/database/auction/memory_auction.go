@@ -0,0 +1,647 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+var _ auction_entity.AuctionRepositoryInterface = (*MemoryAuctionRepository)(nil)
+
+// MemoryAuctionRepository keeps auctions in process memory, for tests and
+// demos that run without MongoDB. It honours the same environment settings,
+// status transitions, auto-close and concurrency limit as AuctionRepository,
+// but has no cache, webhooks or bids. Close callbacks only fire for
+// auctions closed by the monitors.
+type MemoryAuctionRepository struct {
+	mutex               sync.Mutex
+	auctions            map[string]auction_entity.Auction
+	history             map[string][]auction_entity.StatusChange
+	activeAuctionsCount int64
+	closed              bool
+	monitors            *monitorScheduler
+	clock               clock.Clock
+	closeCallbacks      closeCallbacks
+
+	// settings reads the environment configuration shared with the Mongo
+	// repository (interval, categories, description limit)
+	settings AuctionRepository
+}
+
+// NewMemoryAuctionRepository starts an empty repository whose auctions are
+// closed on c; a nil clock uses the real time.
+func NewMemoryAuctionRepository(c clock.Clock) *MemoryAuctionRepository {
+	if c == nil {
+		c = clock.NewRealClock()
+	}
+
+	repo := &MemoryAuctionRepository{
+		auctions: make(map[string]auction_entity.Auction),
+		history:  make(map[string][]auction_entity.StatusChange),
+		clock:    c,
+		settings: AuctionRepository{config: ConfigFromEnv()},
+	}
+
+	repo.monitors = newMonitorScheduler(c, repo.settings.getMonitorWorkers(), repo.closeDueAuction)
+	repo.monitors.Start()
+
+	return repo
+}
+
+func (mr *MemoryAuctionRepository) CreateAuction(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	if !mr.settings.isCategoryAllowed(auctionEntity.Category) {
+		return internal_error.NewBadRequestError("Auction category is not allowed")
+	}
+
+	description, err := mr.settings.limitDescription(auctionEntity.Description)
+	if err != nil {
+		return err
+	}
+
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	if mr.closed {
+		return internal_error.NewConflictError("repository is shutting down")
+	}
+
+	if auctionEntity.IdempotencyKey != "" {
+		for _, existing := range mr.auctions {
+			if existing.IdempotencyKey == auctionEntity.IdempotencyKey {
+				*auctionEntity = existing
+				return nil
+			}
+		}
+	}
+
+	if err := mr.settings.checkMaintenanceMode(); err != nil {
+		return err
+	}
+
+	if _, ok := mr.auctions[auctionEntity.Id]; ok {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction already exists with this id = %s", auctionEntity.Id))
+	}
+
+	if !mr.hasSlotLocked(auctionEntity.Category) {
+		logger.Error("Maximum concurrent auctions limit reached", nil)
+		return internal_error.NewInternalServerError("Maximum concurrent auctions limit reached")
+	}
+
+	if maxPerOwner := mr.settings.getMaxActivePerOwner(); maxPerOwner > 0 && auctionEntity.OwnerId != "" &&
+		mr.activeOfOwnerLocked(auctionEntity.OwnerId) >= maxPerOwner {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Owner already has the maximum of %d active auctions", maxPerOwner))
+	}
+
+	auctionDuration := mr.settings.getAuctionDuration()
+	if auctionEntity.Duration > 0 {
+		auctionDuration = auctionEntity.Duration
+	}
+
+	auction := *auctionEntity
+	auction.Description = description
+	auction.Timestamp = auctionEntity.Timestamp.UTC()
+	auction.EndTime = auction.Timestamp.Add(auctionDuration)
+	auction.ClosedAt = time.Time{}
+	auction.Version = 1
+	auction.Duration = 0
+	mr.auctions[auction.Id] = auction
+
+	if auction.Status == auction_entity.Active {
+		mr.activeAuctionsCount++
+		mr.monitors.Schedule(auction.Id, mr.settings.closeTime(mr.clock.Now().Add(auctionDuration)))
+	}
+
+	return nil
+}
+
+func (mr *MemoryAuctionRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	auction, ok := mr.auctions[id]
+	if !ok {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", id))
+	}
+
+	return &auction, nil
+}
+
+func (mr *MemoryAuctionRepository) FindAuctionSummaryById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction, err := mr.FindAuctionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := toAuctionSummary(*auction)
+	return &summary, nil
+}
+
+// FindAuctions returns the matching auctions ordered by creation time.
+func (mr *MemoryAuctionRepository) FindAuctions(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	var productName *regexp.Regexp
+	if filter.ProductName != "" {
+		var err error
+		if productName, err = regexp.Compile("(?i)" + filter.ProductName); err != nil {
+			return nil, internal_error.NewBadRequestError("Invalid product name filter")
+		}
+	}
+
+	var auctions []auction_entity.Auction
+	for _, auction := range mr.auctions {
+		if matchesAuctionFilter(auction, filter, productName) {
+			auctions = append(auctions, auction)
+		}
+	}
+
+	sortOldestFirst(auctions)
+
+	return pageAuctions(auctions, filter), nil
+}
+
+func (mr *MemoryAuctionRepository) CountAuctions(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) (int64, *internal_error.InternalError) {
+	// Counts ignore paging
+	filter.Page, filter.PageSize = 0, 0
+	auctions, err := mr.FindAuctions(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(auctions)), nil
+}
+
+func (mr *MemoryAuctionRepository) FindAuctionSummaries(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError) {
+	auctions, err := mr.FindAuctions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, auction := range auctions {
+		auctions[i] = toAuctionSummary(auction)
+	}
+
+	return auctions, nil
+}
+
+func (mr *MemoryAuctionRepository) UpdateAuctionStatus(
+	ctx context.Context,
+	auctionId string,
+	status auction_entity.AuctionStatus) *internal_error.InternalError {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	return mr.changeAuctionStatusLocked(auctionId, status, statusReasonUpdate)
+}
+
+func (mr *MemoryAuctionRepository) UpdateAuction(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	update auction_entity.AuctionUpdate) (*auction_entity.Auction, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	auction, ok := mr.auctions[auctionId]
+	if !ok {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+
+	if update == (auction_entity.AuctionUpdate{}) {
+		return &auction, nil
+	}
+
+	if auction.Status != auction_entity.Active {
+		return nil, internal_error.NewConflictError("Auction is not active and cannot be updated")
+	}
+
+	if auction.Version != expectedVersion {
+		return nil, internal_error.NewConflictError(
+			fmt.Sprintf("Auction was modified concurrently (expected version %d, current %d)",
+				expectedVersion, auction.Version))
+	}
+
+	if update.Category != nil {
+		if err := checkCategoryUnchanged(auction.Category, *update.Category); err != nil {
+			return nil, err
+		}
+	}
+	if update.Description != nil {
+		description, err := mr.settings.limitDescription(*update.Description)
+		if err != nil {
+			return nil, err
+		}
+		update.Description = &description
+	}
+
+	auction.ApplyUpdate(update)
+	auction.Version++
+	mr.auctions[auctionId] = auction
+
+	return &auction, nil
+}
+
+func (mr *MemoryAuctionRepository) CancelAllActive(
+	ctx context.Context, reason string) (int64, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	var cancelled int64
+	for auctionId, auction := range mr.auctions {
+		if auction.Status != auction_entity.Active && auction.Status != auction_entity.Paused {
+			continue
+		}
+
+		mr.setStatusLocked(auctionId, auction, auction_entity.Cancelled, reason, auction_entity.CloseReasonEmergencyCancel)
+		cancelled++
+	}
+
+	return cancelled, nil
+}
+
+func (mr *MemoryAuctionRepository) PurgeCompletedBefore(
+	ctx context.Context, before time.Time) (int64, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	var purged int64
+	for auctionId, auction := range mr.auctions {
+		closed := auction.Status == auction_entity.Completed || auction.Status == auction_entity.Cancelled
+		if closed && auction.ClosedAt.Before(before) {
+			delete(mr.auctions, auctionId)
+			delete(mr.history, auctionId)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// AuctionStats is computed on every call; the repository holds no bids, so
+// Bids is always zero.
+func (mr *MemoryAuctionRepository) AuctionStats(
+	ctx context.Context) (*auction_entity.AuctionStats, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	stats := &auction_entity.AuctionStats{}
+	for _, auction := range mr.auctions {
+		stats.Total++
+		switch auction.Status {
+		case auction_entity.Active:
+			stats.Active++
+		case auction_entity.Completed:
+			stats.Completed++
+			stats.CompletedDuration += auction.ClosedAt.Sub(auction.Timestamp)
+		}
+	}
+
+	return stats, nil
+}
+
+func (mr *MemoryAuctionRepository) CountAuctionsByTime(
+	ctx context.Context,
+	bucket auction_entity.TimeBucket,
+	from, to time.Time) ([]auction_entity.AuctionCountBucket, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	var buckets []auction_entity.AuctionCountBucket
+	for _, auction := range mr.auctions {
+		created := auction.Timestamp.Unix()
+		if !from.IsZero() && created < from.Unix() || !to.IsZero() && created >= to.Unix() {
+			continue
+		}
+
+		buckets = append(buckets, auction_entity.AuctionCountBucket{
+			Start: truncateToBucket(auction.Timestamp, bucket),
+			Count: 1,
+		})
+	}
+
+	return mergeCountBuckets(buckets), nil
+}
+
+// ActiveAuctionsCount reports how many auctions hold a concurrency slot.
+func (mr *MemoryAuctionRepository) ActiveAuctionsCount() int64 {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	return mr.activeAuctionsCount
+}
+
+// FindAuctionsEndingBefore mirrors AuctionRepository.FindAuctionsEndingBefore.
+func (mr *MemoryAuctionRepository) FindAuctionsEndingBefore(
+	ctx context.Context, before time.Time) ([]auction_entity.Auction, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	var auctions []auction_entity.Auction
+	for _, auction := range mr.auctions {
+		if auction.Status == auction_entity.Active && !auction.EndTime.After(before) {
+			auctions = append(auctions, auction)
+		}
+	}
+
+	sortByEndTime(auctions)
+	return auctions, nil
+}
+
+// SetMaxConcurrentAuctions replaces the concurrency limit like the Mongo
+// repository does.
+func (mr *MemoryAuctionRepository) SetMaxConcurrentAuctions(n int64) *internal_error.InternalError {
+	return mr.settings.SetMaxConcurrentAuctions(n)
+}
+
+// RuntimeConfig mirrors AuctionRepository.RuntimeConfig.
+func (mr *MemoryAuctionRepository) RuntimeConfig() RuntimeConfig {
+	config := mr.settings.RuntimeConfig()
+	config.MonitorWorkers = mr.monitors.Workers()
+
+	return config
+}
+
+// Close stops the auto-close monitors; later creates fail with a conflict.
+func (mr *MemoryAuctionRepository) Close() {
+	mr.mutex.Lock()
+	mr.closed = true
+	mr.mutex.Unlock()
+
+	mr.monitors.Shutdown()
+}
+
+func (mr *MemoryAuctionRepository) closeDueAuction(auctionId string) func() {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	if err := mr.changeAuctionStatusLocked(
+		auctionId, auction_entity.Completed, statusReasonAutoClose); err != nil {
+		logger.Error("Error closing auction automatically", err)
+		return nil
+	}
+
+	logger.Info("Auction closed automatically due to timeout")
+	// setStatusLocked started the callbacks in their own goroutines, so
+	// there is nothing to order
+	return nil
+}
+
+func (mr *MemoryAuctionRepository) changeAuctionStatusLocked(
+	auctionId string,
+	status auction_entity.AuctionStatus,
+	reason string) *internal_error.InternalError {
+	auction, ok := mr.auctions[auctionId]
+	if !ok {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+
+	if !validTransition(auction.Status, status) {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction status cannot change from %d to %d", auction.Status, status))
+	}
+	if isPauseTransition(auction.Status, status) {
+		return internal_error.NewConflictError("Use PauseAuction and ResumeAuction to pause or resume an auction")
+	}
+
+	if status != auction_entity.Active {
+		mr.setStatusLocked(auctionId, auction, status, reason, closeReasonFor(reason))
+		return nil
+	}
+
+	// Reopening takes a slot and a fresh AUCTION_INTERVAL, as in
+	// AuctionRepository
+	if err := mr.checkActivationLocked(auction); err != nil {
+		return err
+	}
+	auction.EndTime = mr.clock.Now().UTC().Add(mr.settings.getAuctionDuration())
+	mr.setStatusLocked(auctionId, auction, status, reason, "")
+	mr.monitors.Schedule(auctionId, mr.settings.closeTime(auction.EndTime))
+	return nil
+}
+
+// checkActivationLocked fails if auction can't become Active again: the
+// repository is closed, or its category, the global or its owner's limit
+// is reached. The caller holds mr.mutex.
+func (mr *MemoryAuctionRepository) checkActivationLocked(auction auction_entity.Auction) *internal_error.InternalError {
+	if mr.closed {
+		return internal_error.NewConflictError("repository is shutting down")
+	}
+	if !mr.hasSlotLocked(auction.Category) {
+		return internal_error.NewConflictError("Maximum concurrent auctions limit reached")
+	}
+	if maxPerOwner := mr.settings.getMaxActivePerOwner(); maxPerOwner > 0 && auction.OwnerId != "" &&
+		mr.activeOfOwnerLocked(auction.OwnerId) >= maxPerOwner {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Owner already has the maximum of %d active auctions", maxPerOwner))
+	}
+	return nil
+}
+
+// setStatusLocked applies a valid transition, records it in the history
+// and keeps the monitors and the active count in step with it. closeReason
+// is dropped when reopening.
+func (mr *MemoryAuctionRepository) setStatusLocked(
+	auctionId string,
+	auction auction_entity.Auction,
+	status auction_entity.AuctionStatus,
+	reason, closeReason string) {
+	from := auction.Status
+	if from == auction_entity.Active {
+		mr.monitors.Cancel(auctionId)
+		mr.activeAuctionsCount--
+	}
+
+	auction.Status = status
+	auction.Version++
+	switch status {
+	case auction_entity.Active:
+		// The caller checked the limits and schedules the new monitor
+		mr.activeAuctionsCount++
+		auction.ClosedAt = time.Time{}
+		auction.CloseReason = ""
+	case auction_entity.Paused:
+		// Paused auctions are not closed, only frozen
+	default:
+		auction.ClosedAt = mr.clock.Now().UTC()
+		auction.CloseReason = closeReason
+		auction.PausedRemaining = 0
+	}
+	mr.auctions[auctionId] = auction
+	switch status {
+	case auction_entity.Completed:
+		mr.closeCallbacks.run(mr.closeCallbacks.take(auctionId), auction)
+	case auction_entity.Cancelled:
+		mr.closeCallbacks.forget(auctionId)
+	}
+
+	change := auction_entity.StatusChange{
+		AuctionId: auctionId,
+		From:      from,
+		To:        status,
+		At:        mr.clock.Now().UTC(),
+		Reason:    reason,
+	}
+	if status != auction_entity.Active && status != auction_entity.Paused {
+		change.CloseReason = closeReason
+	}
+	mr.history[auctionId] = append(mr.history[auctionId], change)
+}
+
+// FindAuctionsStatusByIds mirrors AuctionRepository.FindAuctionsStatusByIds.
+func (mr *MemoryAuctionRepository) FindAuctionsStatusByIds(
+	ctx context.Context,
+	auctionIds []string) (map[string]auction_entity.AuctionStatus, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	statuses := make(map[string]auction_entity.AuctionStatus, len(auctionIds))
+	for _, auctionId := range auctionIds {
+		if auction, ok := mr.auctions[auctionId]; ok {
+			statuses[auctionId] = auction.Status
+		}
+	}
+
+	return statuses, nil
+}
+
+// FindStatusHistory pages through the status changes of an auction, oldest
+// first.
+func (mr *MemoryAuctionRepository) FindStatusHistory(
+	ctx context.Context,
+	auctionId string,
+	page, pageSize int64) ([]auction_entity.StatusChange, int64, *internal_error.InternalError) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	if _, ok := mr.auctions[auctionId]; !ok {
+		return nil, 0, internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+	page, pageSize = normalizePage(page, pageSize)
+
+	history := mr.history[auctionId]
+	total := int64(len(history))
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+
+	changes := make([]auction_entity.StatusChange, end-start)
+	copy(changes, history[start:end])
+	return changes, total, nil
+}
+
+// hasSlotLocked applies the category's own cap, or the global one, like
+// AuctionRepository does.
+func (mr *MemoryAuctionRepository) hasSlotLocked(category string) bool {
+	maxAuctions, ok := mr.settings.categoryLimit(category)
+	if !ok {
+		return mr.activeAuctionsCount < mr.settings.getMaxConcurrentAuctions()
+	}
+
+	var active int64
+	for _, auction := range mr.auctions {
+		if auction.Status == auction_entity.Active &&
+			auction_entity.NormalizeCategory(auction.Category) == auction_entity.NormalizeCategory(category) {
+			active++
+		}
+	}
+	return active < maxAuctions
+}
+
+// activeOfOwnerLocked counts the active auctions of ownerId. The caller
+// holds mutex.
+func (mr *MemoryAuctionRepository) activeOfOwnerLocked(ownerId string) int64 {
+	var active int64
+	for _, auction := range mr.auctions {
+		if auction.OwnerId == ownerId && auction.Status == auction_entity.Active {
+			active++
+		}
+	}
+	return active
+}
+
+func matchesAuctionFilter(
+	auction auction_entity.Auction,
+	filter auction_entity.AuctionFilter,
+	productName *regexp.Regexp) bool {
+	if filter.Status != 0 && auction.Status != filter.Status {
+		return false
+	}
+
+	for _, excluded := range filter.ExcludeStatuses {
+		if auction.Status == excluded {
+			return false
+		}
+	}
+
+	if categories := filter.FilterCategories(); len(categories) > 0 {
+		matched := false
+		for _, category := range categories {
+			if auction.Category == category {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if filter.Subcategory != "" && auction.Subcategory != filter.Subcategory {
+		return false
+	}
+
+	if productName != nil && !productName.MatchString(auction.ProductName) {
+		return false
+	}
+
+	created := auction.Timestamp.Unix()
+	if filter.CreatedFrom != 0 && created < filter.CreatedFrom ||
+		filter.CreatedTo != 0 && created > filter.CreatedTo {
+		return false
+	}
+
+	if filter.HasWinner != nil && *filter.HasWinner != (auction.WinnerBidId != "") {
+		return false
+	}
+
+	return true
+}
+
+func toAuctionSummary(auction auction_entity.Auction) auction_entity.Auction {
+	return auction_entity.Auction{
+		Id:          auction.Id,
+		ProductName: auction.ProductName,
+		Category:    auction.Category,
+		Status:      auction.Status,
+		EndTime:     auction.EndTime,
+	}
+}
@@ -0,0 +1,322 @@
+package auction
+
+import (
+	"context"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryAutoCloseWithFakeClock(t *testing.T) {
+	// Um intervalo longo prova que nenhum tempo real precisa passar
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewMemoryAuctionRepository(fakeClock)
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+	assert.Equal(t, int64(1), repo.ActiveAuctionsCount())
+
+	fakeClock.BlockUntil(1)
+
+	fakeClock.Advance(59 * time.Minute)
+	stored, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, stored.Status)
+
+	fakeClock.Advance(time.Minute)
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, fakeClock.Now().UTC(), stored.ClosedAt)
+	assert.Equal(t, auction_entity.CloseReasonTimeout, stored.CloseReason)
+	assert.Equal(t, int64(0), repo.ActiveAuctionsCount())
+}
+
+func TestMemoryConcurrentAuctionsLimit(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	var first *auction_entity.Auction
+	for i := int64(0); i < repo.settings.getMaxConcurrentAuctions(); i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		if first == nil {
+			first = auction
+		}
+	}
+
+	extra, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.NotNil(t, repo.CreateAuction(ctx, extra))
+
+	// Fechar um leilão libera uma vaga
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, first.Id, auction_entity.Completed))
+	assert.Nil(t, repo.CreateAuction(ctx, extra))
+
+	// Reabrir ocupa a vaga de novo
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, extra.Id, auction_entity.Completed))
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, extra.Id, auction_entity.Active))
+	assert.Equal(t, repo.settings.getMaxConcurrentAuctions(), repo.ActiveAuctionsCount())
+}
+
+func TestMemoryFindAndUpdateAuctions(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	phone, err := auction_entity.CreateAuction(
+		"Phone", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, phone))
+
+	chair, err := auction_entity.CreateAuction(
+		"Chair", "Furniture", "Test description for auction", auction_entity.Used)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, chair))
+
+	auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Category: "Furniture"})
+	assert.Nil(t, err)
+	if assert.Len(t, auctions, 1) {
+		assert.Equal(t, chair.Id, auctions[0].Id)
+	}
+
+	summaries, err := repo.FindAuctionSummaries(ctx, auction_entity.AuctionFilter{ProductName: "pho"})
+	assert.Nil(t, err)
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, phone.Id, summaries[0].Id)
+		assert.Empty(t, summaries[0].Description)
+	}
+
+	_, err = repo.FindAuctionById(ctx, "missing")
+	assert.Equal(t, "not_found", err.Err)
+
+	name := "Smartphone"
+	updated, err := repo.UpdateAuction(ctx, phone.Id, 1, auction_entity.AuctionUpdate{ProductName: &name})
+	assert.Nil(t, err)
+	assert.Equal(t, name, updated.ProductName)
+	assert.Equal(t, int64(2), updated.Version)
+
+	_, err = repo.UpdateAuction(ctx, phone.Id, 1, auction_entity.AuctionUpdate{ProductName: &name})
+	assert.Equal(t, "conflict", err.Err)
+
+	// Cancelado é terminal
+	cancelled, err := repo.CancelAllActive(ctx, "test")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), cancelled)
+	assert.Equal(t, "conflict", repo.UpdateAuctionStatus(ctx, phone.Id, auction_entity.Active).Err)
+	assert.Equal(t, int64(0), repo.ActiveAuctionsCount())
+}
+
+func TestMemoryUpdateAuctionAppliesCreateRules(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	phone, err := auction_entity.CreateAuction(
+		"Phone", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, phone))
+
+	// Trocar de categoria moveria a vaga do limite por categoria
+	furniture := "Furniture"
+	_, err = repo.UpdateAuction(ctx, phone.Id, 1, auction_entity.AuctionUpdate{Category: &furniture})
+	assert.Equal(t, "conflict", err.Err)
+
+	sameCategory := "electronics"
+	updated, err := repo.UpdateAuction(ctx, phone.Id, 1, auction_entity.AuctionUpdate{Category: &sameCategory})
+	assert.Nil(t, err)
+	assert.Equal(t, "Electronics", updated.Category)
+
+	tooLong := strings.Repeat("a", maxDescriptionLength+1)
+	_, err = repo.UpdateAuction(ctx, phone.Id, 2, auction_entity.AuctionUpdate{Description: &tooLong})
+	assert.Equal(t, "bad_request", err.Err)
+
+	stored, err := repo.FindAuctionById(ctx, phone.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "Test description for auction", stored.Description)
+}
+
+func TestMemoryFindAuctionsBySubcategory(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	var ids []string
+	for _, subcategory := range []string{"Phones", "Laptops", ""} {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		auction.Subcategory = subcategory
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		ids = append(ids, auction.Id)
+	}
+
+	auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Category: "Electronics"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, ids, auctionIds(auctions))
+
+	auctions, err = repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+		Category: "Electronics", Subcategory: "Phones"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{ids[0]}, auctionIds(auctions))
+}
+
+func TestMemoryFindAuctionsIgnoresCategoryCasing(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	var ids []string
+	for _, category := range []string{"electronics", "ELECTRONICS"} {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", category, "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		ids = append(ids, auction.Id)
+	}
+
+	auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{Category: "eLeCtRoNiCs"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, ids, auctionIds(auctions))
+}
+
+func TestMemoryFindAuctionsByCategories(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	ids := make(map[string]string)
+	for _, category := range []string{"Electronics", "Books", "Toys"} {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", category, "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		ids[category] = auction.Id
+	}
+
+	auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+		Categories: []string{"electronics", "BOOKS"}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{ids["Electronics"], ids["Books"]}, auctionIds(auctions))
+
+	// Category and Categories add up, and still compose with the status
+	auctions, err = repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+		Status: auction_entity.Active, Category: "toys", Categories: []string{"Books"}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{ids["Toys"], ids["Books"]}, auctionIds(auctions))
+
+	auctions, err = repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+		Status: auction_entity.Completed, Categories: []string{"electronics", "books"}})
+	assert.Nil(t, err)
+	assert.Empty(t, auctions)
+}
+
+func TestMemoryCreateAuctionIdempotencyKey(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	first, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	first.IdempotencyKey = "retry-key"
+	assert.Nil(t, repo.CreateAuction(ctx, first))
+
+	retry, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	retry.IdempotencyKey = "retry-key"
+	assert.Nil(t, repo.CreateAuction(ctx, retry))
+
+	assert.Equal(t, first.Id, retry.Id)
+	assert.Equal(t, int64(1), repo.ActiveAuctionsCount())
+}
+
+func TestMemoryFindAuctionsExcludingStatuses(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	ids := map[auction_entity.AuctionStatus]string{}
+	for _, status := range []auction_entity.AuctionStatus{
+		auction_entity.Active, auction_entity.Completed, auction_entity.Cancelled} {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		if status != auction_entity.Active {
+			assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, status))
+		}
+		ids[status] = auction.Id
+	}
+
+	auctions, err := repo.FindAuctions(ctx, auction_entity.AuctionFilter{
+		ExcludeStatuses: []auction_entity.AuctionStatus{auction_entity.Completed, auction_entity.Cancelled}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{ids[auction_entity.Active]}, auctionIds(auctions))
+}
+
+func TestMemoryFindAuctionsStatusByIds(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	active, err := auction_entity.CreateAuction(
+		"Active Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, active))
+	completed, err := auction_entity.CreateAuction(
+		"Completed Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, completed))
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, completed.Id, auction_entity.Completed))
+
+	statuses, findErr := repo.FindAuctionsStatusByIds(ctx, []string{active.Id, completed.Id, "missing"})
+	assert.Nil(t, findErr)
+	assert.Equal(t, map[string]auction_entity.AuctionStatus{
+		active.Id:    auction_entity.Active,
+		completed.Id: auction_entity.Completed,
+	}, statuses)
+}
+
+func TestMemoryStatusHistoryPastTheLastPage(t *testing.T) {
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+
+	// Uma página enorme não pode estourar o deslocamento
+	history, total, findErr := repo.FindStatusHistory(ctx, auction.Id, math.MaxInt64, maxSearchPageSize)
+	assert.Nil(t, findErr)
+	assert.Equal(t, int64(1), total)
+	assert.Empty(t, history)
+}
@@ -0,0 +1,353 @@
+package auction
+
+import (
+	"container/heap"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+)
+
+const defaultMonitorWorkers = 100
+
+// MonitorScheduler closes auctions once their deadline is reached by
+// calling back the repository that created it. The default keeps the
+// deadlines in process; WithDurableMonitors stores them in the database so
+// any instance can close any auction.
+type MonitorScheduler interface {
+	Start()
+
+	// Schedule arranges for the auction to be closed at deadline, replacing
+	// any deadline it already had
+	Schedule(auctionId string, deadline time.Time)
+
+	// Cancel drops a pending auction
+	Cancel(auctionId string)
+
+	// Shutdown stops the scheduler and waits for in-flight closes; only
+	// the first call reports what was stopped
+	Shutdown() ShutdownStats
+
+	// Pending reports how many auctions are waiting for their deadline
+	Pending() int
+
+	// Workers reports how many auctions can be closed concurrently
+	Workers() int
+}
+
+// ShutdownStats tells what a scheduler shutdown stopped: the monitors
+// dropped before their deadline and the closes it waited for.
+type ShutdownStats struct {
+	CancelledMonitors int
+	DrainedCloses     int
+}
+
+var _ MonitorScheduler = (*monitorScheduler)(nil)
+
+// monitorScheduler multiplexes the wait of every monitored auction onto a
+// single timer. Due auctions are queued to a fixed pool of workers, so a
+// flood of auctions does not translate into a flood of goroutines.
+// Workers close auctions in parallel, but what onDue returns to publish a
+// close runs in deadline then id order, so auctions sharing a deadline
+// announce their close in id order.
+type monitorScheduler struct {
+	clock   clock.Clock
+	onDue   func(auctionId string) (publish func())
+	workers int
+
+	mutex sync.Mutex
+	queue monitorQueue
+	items map[string]*monitorItem
+
+	// nextTicket numbers the due auctions in the order popDue returns
+	// them; only the dispatcher uses it
+	nextTicket uint64
+	publisher  closePublisher
+
+	wake     chan struct{}
+	jobs     chan monitorJob
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	busyWorkers    atomic.Int64
+	maxBusyWorkers atomic.Int64
+
+	// drainedCloses counts the closes that finished after Shutdown began
+	drainedCloses atomic.Int64
+}
+
+type monitorItem struct {
+	auctionId string
+	deadline  time.Time
+	index     int
+}
+
+// monitorJob is a due auction handed to a worker with its place in the
+// publication order.
+type monitorJob struct {
+	auctionId string
+	ticket    uint64
+}
+
+func newMonitorScheduler(
+	c clock.Clock, workers int, onDue func(auctionId string) (publish func())) *monitorScheduler {
+	if workers <= 0 {
+		workers = defaultMonitorWorkers
+	}
+
+	return &monitorScheduler{
+		clock:   c,
+		onDue:   onDue,
+		workers: workers,
+		items:   make(map[string]*monitorItem),
+		wake:    make(chan struct{}, 1),
+		jobs:    make(chan monitorJob),
+		stop:    make(chan struct{}),
+	}
+}
+
+func (ms *monitorScheduler) Start() {
+	ms.wg.Add(ms.workers + 1)
+	go ms.dispatch()
+	for i := 0; i < ms.workers; i++ {
+		go ms.work()
+	}
+}
+
+// Schedule arranges for onDue(auctionId) to run at deadline, replacing any
+// deadline the auction already had.
+func (ms *monitorScheduler) Schedule(auctionId string, deadline time.Time) {
+	ms.mutex.Lock()
+	if item, ok := ms.items[auctionId]; ok {
+		item.deadline = deadline
+		heap.Fix(&ms.queue, item.index)
+	} else {
+		item := &monitorItem{auctionId: auctionId, deadline: deadline}
+		heap.Push(&ms.queue, item)
+		ms.items[auctionId] = item
+	}
+	ms.mutex.Unlock()
+
+	ms.notify()
+}
+
+// Cancel drops a pending auction. Auctions already handed to a worker are
+// not affected.
+func (ms *monitorScheduler) Cancel(auctionId string) {
+	ms.mutex.Lock()
+	item, ok := ms.items[auctionId]
+	if ok {
+		heap.Remove(&ms.queue, item.index)
+		delete(ms.items, auctionId)
+	}
+	ms.mutex.Unlock()
+
+	if ok {
+		ms.notify()
+	}
+}
+
+// Shutdown stops the dispatcher and the workers and waits for them. Pending
+// auctions are discarded.
+func (ms *monitorScheduler) Shutdown() ShutdownStats {
+	var stats ShutdownStats
+	first := false
+	ms.stopOnce.Do(func() {
+		ms.mutex.Lock()
+		stats.CancelledMonitors = ms.queue.Len()
+		ms.mutex.Unlock()
+
+		close(ms.stop)
+		first = true
+	})
+	ms.wg.Wait()
+
+	if first {
+		stats.DrainedCloses = int(ms.drainedCloses.Load())
+	}
+	return stats
+}
+
+func (ms *monitorScheduler) notify() {
+	select {
+	case ms.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ms *monitorScheduler) dispatch() {
+	defer ms.wg.Done()
+
+	for {
+		due, next, hasNext := ms.popDue()
+		for _, auctionId := range due {
+			select {
+			case ms.jobs <- monitorJob{auctionId: auctionId, ticket: ms.nextTicket}:
+				ms.nextTicket++
+			case <-ms.stop:
+				return
+			}
+		}
+
+		var timerC <-chan time.Time
+		var timer clock.Timer
+		if hasNext {
+			timer = ms.clock.NewTimer(next.Sub(ms.clock.Now()))
+			timerC = timer.C()
+		}
+
+		select {
+		case <-timerC:
+		case <-ms.wake:
+		case <-ms.stop:
+			if timer != nil {
+				stopTimer(timer)
+			}
+			return
+		}
+
+		if timer != nil {
+			stopTimer(timer)
+		}
+	}
+}
+
+// popDue removes every auction whose deadline has passed, in deadline then
+// id order, and reports the next deadline still pending, if any.
+func (ms *monitorScheduler) popDue() ([]string, time.Time, bool) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := ms.clock.Now()
+	var due []string
+	for ms.queue.Len() > 0 {
+		item := ms.queue[0]
+		if item.deadline.After(now) {
+			return due, item.deadline, true
+		}
+
+		heap.Pop(&ms.queue)
+		delete(ms.items, item.auctionId)
+		due = append(due, item.auctionId)
+	}
+
+	return due, time.Time{}, false
+}
+
+func (ms *monitorScheduler) work() {
+	defer ms.wg.Done()
+
+	for {
+		select {
+		case job := <-ms.jobs:
+			busy := ms.busyWorkers.Add(1)
+			for {
+				max := ms.maxBusyWorkers.Load()
+				if busy <= max || ms.maxBusyWorkers.CompareAndSwap(max, busy) {
+					break
+				}
+			}
+
+			ms.publisher.done(job.ticket, ms.onDue(job.auctionId))
+			ms.busyWorkers.Add(-1)
+
+			select {
+			case <-ms.stop:
+				ms.drainedCloses.Add(1)
+			default:
+			}
+		case <-ms.stop:
+			return
+		}
+	}
+}
+
+func (ms *monitorScheduler) Pending() int {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	return ms.queue.Len()
+}
+
+func (ms *monitorScheduler) Workers() int {
+	return ms.workers
+}
+
+// parseMonitorWorkers parses MONITOR_WORKERS, returning zero when it is
+// not a positive number.
+func parseMonitorWorkers(value string) int {
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers <= 0 {
+		return 0
+	}
+	return workers
+}
+
+// monitorQueue is a min-heap of monitored auctions ordered by deadline,
+// then by id.
+type monitorQueue []*monitorItem
+
+func (mq monitorQueue) Len() int { return len(mq) }
+
+func (mq monitorQueue) Less(i, j int) bool {
+	if !mq[i].deadline.Equal(mq[j].deadline) {
+		return mq[i].deadline.Before(mq[j].deadline)
+	}
+	return mq[i].auctionId < mq[j].auctionId
+}
+
+func (mq monitorQueue) Swap(i, j int) {
+	mq[i], mq[j] = mq[j], mq[i]
+	mq[i].index = i
+	mq[j].index = j
+}
+
+func (mq *monitorQueue) Push(x interface{}) {
+	item := x.(*monitorItem)
+	item.index = len(*mq)
+	*mq = append(*mq, item)
+}
+
+func (mq *monitorQueue) Pop() interface{} {
+	old := *mq
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*mq = old[:len(old)-1]
+	return item
+}
+
+// closePublisher runs the publications of due auctions in ticket order,
+// whichever worker finishes first. A publication waits for every earlier
+// ticket to be done; nil marks a close with nothing to publish.
+type closePublisher struct {
+	mutex   sync.Mutex
+	next    uint64
+	pending map[uint64]func()
+}
+
+func (cp *closePublisher) done(ticket uint64, publish func()) {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	if cp.pending == nil {
+		cp.pending = make(map[uint64]func())
+	}
+	cp.pending[ticket] = publish
+
+	// Publishing under the mutex keeps two workers from running their
+	// share of the queue at the same time
+	for {
+		publish, ok := cp.pending[cp.next]
+		if !ok {
+			return
+		}
+		delete(cp.pending, cp.next)
+		cp.next++
+		if publish != nil {
+			publish()
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorSchedulerRespectsWorkerCap(t *testing.T) {
+	const (
+		numAuctions = 500
+		workers     = 8
+	)
+
+	var mutex sync.Mutex
+	closed := make(map[string]bool)
+	allClosed := make(chan struct{})
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	scheduler := newMonitorScheduler(fakeClock, workers, func(auctionId string) func() {
+		// Hold the worker briefly so closes overlap
+		time.Sleep(time.Millisecond)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		closed[auctionId] = true
+		if len(closed) == numAuctions {
+			close(allClosed)
+		}
+		return nil
+	})
+	scheduler.Start()
+	defer scheduler.Shutdown()
+
+	for i := 0; i < numAuctions; i++ {
+		scheduler.Schedule(fmt.Sprintf("auction-%d", i), fakeClock.Now().Add(time.Duration(i%10)*time.Second))
+	}
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(10 * time.Second)
+
+	select {
+	case <-allClosed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("not every auction was closed")
+	}
+
+	assert.LessOrEqual(t, scheduler.maxBusyWorkers.Load(), int64(workers))
+	assert.Equal(t, 0, scheduler.Pending())
+}
+
+func TestMonitorSchedulerCancelAndReschedule(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	due := make(chan string, 10)
+	scheduler := newMonitorScheduler(fakeClock, 2, func(auctionId string) func() {
+		due <- auctionId
+		return nil
+	})
+	scheduler.Start()
+	defer scheduler.Shutdown()
+
+	scheduler.Schedule("cancelled", fakeClock.Now().Add(time.Minute))
+	scheduler.Schedule("moved", fakeClock.Now().Add(time.Minute))
+	scheduler.Cancel("cancelled")
+	scheduler.Schedule("moved", fakeClock.Now().Add(2*time.Minute))
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Minute)
+	select {
+	case auctionId := <-due:
+		t.Fatalf("auction %s closed before its deadline", auctionId)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Minute)
+	assert.Equal(t, "moved", <-due)
+}
+
+func TestMonitorSchedulerShutdownDiscardsPending(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	due := make(chan string, 1)
+	scheduler := newMonitorScheduler(fakeClock, 2, func(auctionId string) func() {
+		due <- auctionId
+		return nil
+	})
+	scheduler.Start()
+
+	scheduler.Schedule("pending", fakeClock.Now().Add(time.Minute))
+	fakeClock.BlockUntil(1)
+	scheduler.Shutdown()
+
+	assert.Equal(t, 0, fakeClock.PendingTimers())
+	fakeClock.Advance(time.Hour)
+	select {
+	case <-due:
+		t.Fatal("auction closed after shutdown")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMonitorSchedulerShutdownStats(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	scheduler := newMonitorScheduler(fakeClock, 2, func(string) func() { return nil })
+	scheduler.Start()
+
+	for i := 0; i < 3; i++ {
+		scheduler.Schedule(fmt.Sprintf("auction-%d", i), fakeClock.Now().Add(time.Minute))
+	}
+	assert.Equal(t, 3, scheduler.Pending())
+
+	stats := scheduler.Shutdown()
+	assert.Equal(t, 3, stats.CancelledMonitors)
+	assert.Equal(t, 0, stats.DrainedCloses)
+
+	// Only the first shutdown reports
+	assert.Equal(t, ShutdownStats{}, scheduler.Shutdown())
+}
+
+func TestMonitorSchedulerShutdownCountsDrainedCloses(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	closing := make(chan struct{})
+	release := make(chan struct{})
+	scheduler := newMonitorScheduler(fakeClock, 2, func(string) func() {
+		close(closing)
+		<-release
+		return nil
+	})
+	scheduler.Start()
+
+	scheduler.Schedule("auction-due", fakeClock.Now())
+	scheduler.Schedule("auction-later", fakeClock.Now().Add(time.Minute))
+	<-closing
+
+	// O fechamento em andamento é aguardado e contado; o pendente é descartado
+	statsC := make(chan ShutdownStats)
+	go func() { statsC <- scheduler.Shutdown() }()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	stats := <-statsC
+	assert.Equal(t, 1, stats.CancelledMonitors)
+	assert.Equal(t, 1, stats.DrainedCloses)
+}
+
+func TestCloseRecordsShutdownMetrics(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	registry := metrics.NewRegistry()
+	repo := NewAuctionRepository(db, WithMetrics(registry))
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+	}
+
+	repo.Close()
+	// A second Close must not count the monitors twice
+	repo.Close()
+
+	snapshot := registry.Snapshot()
+	assert.Equal(t, int64(4), snapshot.Counters["shutdown_cancelled_monitors"])
+	assert.Equal(t, int64(0), snapshot.Counters["shutdown_drained_closes"])
+	assert.Equal(t, int64(1), snapshot.Histograms["shutdown_duration"].Count)
+}
+
+func TestMonitorSchedulerPublishesSameDeadlineInIdOrder(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	var mutex sync.Mutex
+	var published []string
+	allPublished := make(chan struct{})
+
+	ids := []string{"auction-c", "auction-a", "auction-e", "auction-b", "auction-d"}
+	delays := map[string]time.Duration{
+		"auction-a": 20 * time.Millisecond,
+		"auction-b": 10 * time.Millisecond,
+	}
+	scheduler := newMonitorScheduler(fakeClock, 4, func(auctionId string) func() {
+		// Os primeiros da ordem terminam por último
+		time.Sleep(delays[auctionId])
+
+		return func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+			published = append(published, auctionId)
+			if len(published) == len(ids) {
+				close(allPublished)
+			}
+		}
+	})
+	scheduler.Start()
+	defer scheduler.Shutdown()
+
+	deadline := fakeClock.Now().Add(time.Minute)
+	for _, auctionId := range ids {
+		scheduler.Schedule(auctionId, deadline)
+	}
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Minute)
+
+	select {
+	case <-allPublished:
+	case <-time.After(time.Second):
+		t.Fatal("not every close was published")
+	}
+
+	expected := append([]string{}, ids...)
+	sort.Strings(expected)
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, expected, published)
+	// Os fechamentos continuam em paralelo
+	assert.Greater(t, scheduler.maxBusyWorkers.Load(), int64(1))
+}
+
+func TestClosePublisherSkipsClosesWithNothingToPublish(t *testing.T) {
+	var publisher closePublisher
+	var published []uint64
+	publish := func(ticket uint64) func() {
+		return func() { published = append(published, ticket) }
+	}
+
+	publisher.done(2, publish(2))
+	publisher.done(1, nil)
+	assert.Empty(t, published)
+
+	publisher.done(0, publish(0))
+	assert.Equal(t, []uint64{0, 2}, published)
+}
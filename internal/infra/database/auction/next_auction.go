@@ -0,0 +1,43 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NextAuctionToClose returns the active auction with the earliest end_time
+// and how long until it ends. The remaining time is never negative: an
+// auction already past its end_time but not yet closed reports zero.
+func (ar *AuctionRepository) NextAuctionToClose(
+	ctx context.Context) (*auction_entity.Auction, time.Duration, *internal_error.InternalError) {
+	// Served by the end_time index, which covers every active auction and
+	// ends with the id tie-break
+	opts := options.FindOne().SetSort(bson.D{{Key: "end_time", Value: 1}, {Key: "_id", Value: 1}})
+
+	var auctionMongo AuctionEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"status": auction_entity.Active}, opts).Decode(&auctionMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, 0, internal_error.NewNotFoundError("No active auction found")
+	}
+	if err != nil {
+		logger.Error("Error trying to find the next auction to close", err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find the next auction to close")
+	}
+
+	auction := toAuctionEntity(auctionMongo)
+	remaining := auction.EndTime.Sub(ar.clock.Now())
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &auction, remaining, nil
+}
@@ -0,0 +1,50 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextAuctionToClose(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	now := time.Now().Truncate(time.Second)
+	repo := NewAuctionRepository(db, WithClock(clock.NewFakeClock(now)))
+	defer repo.Close()
+	ctx := context.Background()
+
+	t.Run("not found without active auctions", func(t *testing.T) {
+		_, _, err := repo.NextAuctionToClose(ctx)
+		assert.True(t, errors.Is(err, internal_error.ErrNotFound))
+	})
+
+	t.Run("returns the soonest active auction", func(t *testing.T) {
+		insert := func(id string, status auction_entity.AuctionStatus, endTime time.Time) {
+			_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{Id: id, ProductName: "Product",
+				Category: "Electronics", Status: status, Timestamp: now.Unix(), EndTime: endTime.Unix()})
+			assert.Nil(t, err)
+		}
+		insert("later", auction_entity.Active, now.Add(3*time.Hour))
+		insert("soonest", auction_entity.Active, now.Add(10*time.Minute))
+		insert("middle", auction_entity.Active, now.Add(time.Hour))
+		// Auctions that are no longer active are ignored even if they end first
+		insert("completed", auction_entity.Completed, now.Add(time.Minute))
+
+		next, remaining, err := repo.NextAuctionToClose(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "soonest", next.Id)
+		assert.Equal(t, 10*time.Minute, remaining)
+	})
+}
@@ -0,0 +1,76 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// getMaxActivePerOwner returns how many active auctions one owner may run
+// (MAX_ACTIVE_PER_OWNER); zero means unlimited.
+func (ar *AuctionRepository) getMaxActivePerOwner() int64 {
+	return ar.config.MaxActivePerOwner
+}
+
+// reserveOwnerSlot takes one of ownerId's active auction slots for an
+// auction about to become Active, rejecting with a conflict when the owner
+// already runs the maximum. The returned release must be called once the
+// write is done, whatever its outcome. Auctions without an owner are never
+// limited.
+//
+// The reservation is taken under auctionCountMutex before the database
+// count, and writes still in flight count against the limit, so two writes
+// of this instance racing for the owner's last slot never both pass; both
+// may be rejected. The count itself is read from the database, so the
+// limit also holds across instances outside of such races.
+func (ar *AuctionRepository) reserveOwnerSlot(
+	ctx context.Context, ownerId string) (release func(), err *internal_error.InternalError) {
+	maxPerOwner := ar.getMaxActivePerOwner()
+	if maxPerOwner == 0 || ownerId == "" {
+		return func() {}, nil
+	}
+
+	ar.auctionCountMutex.Lock()
+	ar.ownerReservations[ownerId]++
+	ar.auctionCountMutex.Unlock()
+
+	release = func() {
+		ar.auctionCountMutex.Lock()
+		defer ar.auctionCountMutex.Unlock()
+
+		ar.ownerReservations[ownerId]--
+		if ar.ownerReservations[ownerId] <= 0 {
+			delete(ar.ownerReservations, ownerId)
+		}
+	}
+
+	active, countErr := ar.Collection.CountDocuments(ctx,
+		bson.M{"owner_id": ownerId, "status": auction_entity.Active})
+	if countErr != nil {
+		release()
+		logger.Error("Error trying to count the active auctions of the owner", countErr)
+		return nil, internal_error.NewInternalServerError("Error trying to count the active auctions of the owner")
+	}
+
+	// A write in flight may already be in the count, which only rejects more
+	ar.auctionCountMutex.Lock()
+	inFlight := ar.ownerReservations[ownerId] - 1
+	ar.auctionCountMutex.Unlock()
+
+	if active+inFlight >= maxPerOwner {
+		release()
+		logger.Warn("Maximum active auctions per owner reached",
+			zap.String("owner_id", ownerId),
+			zap.Int64("max_active_per_owner", maxPerOwner))
+		return nil, internal_error.NewConflictError(
+			fmt.Sprintf("Owner already has the maximum of %d active auctions", maxPerOwner))
+	}
+
+	return release, nil
+}
@@ -0,0 +1,112 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newOwnedAuction(t *testing.T, ownerId string) *auction_entity.Auction {
+	t.Helper()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	auction.OwnerId = ownerId
+	return auction
+}
+
+func TestMemoryMaxActivePerOwner(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	os.Setenv("MAX_ACTIVE_PER_OWNER", "2")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	defer os.Unsetenv("MAX_ACTIVE_PER_OWNER")
+
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	first := newOwnedAuction(t, "seller-1")
+	assert.Nil(t, repo.CreateAuction(ctx, first))
+	assert.Nil(t, repo.CreateAuction(ctx, newOwnedAuction(t, "seller-1")))
+
+	err := repo.CreateAuction(ctx, newOwnedAuction(t, "seller-1"))
+	assert.True(t, errors.Is(err, internal_error.ErrConflict))
+
+	// Outros vendedores e leilões sem dono não são afetados
+	assert.Nil(t, repo.CreateAuction(ctx, newOwnedAuction(t, "seller-2")))
+	assert.Nil(t, repo.CreateAuction(ctx, newOwnedAuction(t, "")))
+
+	// Encerrar um leilão libera uma vaga para o dono
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, first.Id, auction_entity.Completed))
+	assert.Nil(t, repo.CreateAuction(ctx, newOwnedAuction(t, "seller-1")))
+}
+
+func TestMaxActivePerOwner(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithConfig(Config{AuctionInterval: time.Hour, MaxActivePerOwner: 2}))
+	defer repo.Close()
+	ctx := context.Background()
+
+	assert.Nil(t, repo.CreateAuction(ctx, newOwnedAuction(t, "seller-1")))
+	assert.Nil(t, repo.CreateAuction(ctx, newOwnedAuction(t, "seller-1")))
+
+	err := repo.CreateAuction(ctx, newOwnedAuction(t, "seller-1"))
+	assert.True(t, errors.Is(err, internal_error.ErrConflict))
+
+	other := newOwnedAuction(t, "seller-2")
+	assert.Nil(t, repo.CreateAuction(ctx, other))
+
+	stored, findErr := repo.FindAuctionById(ctx, other.Id)
+	assert.Nil(t, findErr)
+	assert.Equal(t, "seller-2", stored.OwnerId)
+}
+
+func TestMaxActivePerOwnerConcurrentCreates(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithConfig(Config{AuctionInterval: time.Hour, MaxActivePerOwner: 1}))
+	defer repo.Close()
+	ctx := context.Background()
+
+	// Criações simultâneas disputando a última vaga do dono nunca passam juntas
+	var wg sync.WaitGroup
+	var created atomic.Int64
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if repo.CreateAuction(ctx, newOwnedAuction(t, "seller-1")) == nil {
+				created.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, created.Load(), int64(1))
+	active, err := repo.Collection.CountDocuments(ctx,
+		bson.M{"owner_id": "seller-1", "status": auction_entity.Active})
+	assert.Nil(t, err)
+	assert.Equal(t, created.Load(), active)
+}
@@ -0,0 +1,186 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// PauseAuction freezes an active auction: its monitor is cancelled, its
+// slot freed and the time it has left stored, in whole seconds, until
+// ResumeAuction. Bids are rejected while it is paused and recovery leaves
+// it alone.
+func (ar *AuctionRepository) PauseAuction(ctx context.Context, auctionId string) *internal_error.InternalError {
+	// Read around the cache, an extension may have moved end_time
+	auction, err := ar.findAuctionByIdFromDatabase(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if auction.Status != auction_entity.Active {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s is not active", auctionId))
+	}
+
+	remaining := auction.EndTime.Sub(ar.clock.Now()).Truncate(time.Second)
+	if remaining <= 0 {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s has already ended", auctionId))
+	}
+
+	// Only pause if nobody closed or extended the auction since it was read
+	filter := bson.M{
+		"_id":      auctionId,
+		"status":   auction_entity.Active,
+		"end_time": auction.EndTime.Unix(),
+	}
+	update := bson.M{
+		"$set": bson.M{"status": auction_entity.Paused, "paused_remaining": int64(remaining / time.Second)},
+		"$inc": bson.M{"version": 1},
+	}
+	result, updateErr := ar.Collection.UpdateOne(ctx, filter, update)
+	if updateErr != nil {
+		logger.Error(fmt.Sprintf("Error trying to pause auction with id = %s", auctionId), updateErr)
+		return internal_error.NewInternalServerError("Error trying to pause auction")
+	}
+	ar.invalidateCachedAuction(auctionId)
+	ar.invalidateCachedCounts()
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewConflictError("Auction was changed concurrently")
+	}
+
+	ar.auctionCountMutex.Lock()
+	if _, ok := ar.monitoredAuctions[auctionId]; ok {
+		ar.monitors.Cancel(auctionId)
+		ar.untrackAuctionLocked(auctionId)
+	}
+	ar.auctionCountMutex.Unlock()
+
+	logger.Info("Auction paused",
+		zap.String("auction_id", auctionId),
+		zap.Duration("remaining", remaining))
+
+	ar.publishStatusChange(auction_entity.Active, auction_entity.Paused, auctionId)
+	return ar.recordStatusChanges(ctx, statusReasonPause, "",
+		auction_entity.Active, auction_entity.Paused, auctionId)
+}
+
+// ResumeAuction reopens a paused auction with the time it had left when
+// paused. Like a new auction it needs a free slot and must fit the owner
+// limit, and is refused with a conflict otherwise.
+func (ar *AuctionRepository) ResumeAuction(ctx context.Context, auctionId string) *internal_error.InternalError {
+	auction, err := ar.findAuctionByIdFromDatabase(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if auction.Status != auction_entity.Paused {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s is not paused", auctionId))
+	}
+
+	releaseOwnerSlot, err := ar.reserveOwnerSlot(ctx, auction.OwnerId)
+	if err != nil {
+		return err
+	}
+	defer releaseOwnerSlot()
+
+	if err := ar.reserveSlot(auctionId, auction.Category); err != nil {
+		return err
+	}
+
+	endTime := ar.clock.Now().UTC().Add(auction.PausedRemaining)
+	filter := bson.M{"_id": auctionId, "status": auction_entity.Paused}
+	update := bson.M{
+		"$set":   bson.M{"status": auction_entity.Active, "end_time": endTime.Unix()},
+		"$unset": bson.M{"paused_remaining": ""},
+		"$inc":   bson.M{"version": 1},
+	}
+	result, updateErr := ar.Collection.UpdateOne(ctx, filter, update)
+	ar.invalidateCachedAuction(auctionId)
+	ar.invalidateCachedCounts()
+	if updateErr != nil || result.MatchedCount == 0 {
+		ar.releaseSlot(auctionId)
+
+		if updateErr != nil {
+			logger.Error(fmt.Sprintf("Error trying to resume auction with id = %s", auctionId), updateErr)
+			return internal_error.NewInternalServerError("Error trying to resume auction")
+		}
+		return internal_error.NewConflictError("Auction was changed concurrently")
+	}
+
+	ar.auctionCountMutex.Lock()
+	ar.settleSlotLocked(auctionId)
+	ar.monitors.Schedule(auctionId, ar.closeTime(endTime))
+	ar.auctionCountMutex.Unlock()
+
+	logger.Info("Auction resumed",
+		zap.String("auction_id", auctionId),
+		zap.Duration("remaining", auction.PausedRemaining))
+
+	ar.publishStatusChange(auction_entity.Paused, auction_entity.Active, auctionId)
+	return ar.recordStatusChanges(ctx, statusReasonResume, "",
+		auction_entity.Paused, auction_entity.Active, auctionId)
+}
+
+// PauseAuction mirrors AuctionRepository.PauseAuction.
+func (mr *MemoryAuctionRepository) PauseAuction(ctx context.Context, auctionId string) *internal_error.InternalError {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	auction, ok := mr.auctions[auctionId]
+	if !ok {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+
+	if auction.Status != auction_entity.Active {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s is not active", auctionId))
+	}
+
+	remaining := auction.EndTime.Sub(mr.clock.Now()).Truncate(time.Second)
+	if remaining <= 0 {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s has already ended", auctionId))
+	}
+
+	auction.PausedRemaining = remaining
+	mr.setStatusLocked(auctionId, auction, auction_entity.Paused, statusReasonPause, "")
+	return nil
+}
+
+// ResumeAuction mirrors AuctionRepository.ResumeAuction.
+func (mr *MemoryAuctionRepository) ResumeAuction(ctx context.Context, auctionId string) *internal_error.InternalError {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	auction, ok := mr.auctions[auctionId]
+	if !ok {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+
+	if auction.Status != auction_entity.Paused {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s is not paused", auctionId))
+	}
+
+	if err := mr.checkActivationLocked(auction); err != nil {
+		return err
+	}
+
+	auction.EndTime = mr.clock.Now().UTC().Add(auction.PausedRemaining)
+	auction.PausedRemaining = 0
+	mr.setStatusLocked(auctionId, auction, auction_entity.Active, statusReasonResume, "")
+	mr.monitors.Schedule(auctionId, mr.settings.closeTime(auction.EndTime))
+	return nil
+}
@@ -0,0 +1,193 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPauseResumePreservesRemainingTime(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewMemoryAuctionRepository(fakeClock)
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+	fakeClock.BlockUntil(1)
+
+	fakeClock.Advance(20 * time.Minute)
+	assert.Nil(t, repo.PauseAuction(ctx, auction.Id))
+	assert.Equal(t, int64(0), repo.ActiveAuctionsCount())
+
+	stored, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Paused, stored.Status)
+	assert.Equal(t, 40*time.Minute, stored.PausedRemaining)
+	assert.True(t, stored.ClosedAt.IsZero())
+
+	// Pausado, o leilão não fecha mesmo depois do fim original
+	fakeClock.Advance(2 * time.Hour)
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Paused, stored.Status)
+
+	pauseErr := repo.PauseAuction(ctx, auction.Id)
+	assert.NotNil(t, pauseErr)
+	assert.Equal(t, "conflict", pauseErr.Err)
+
+	assert.Nil(t, repo.ResumeAuction(ctx, auction.Id))
+	assert.Equal(t, int64(1), repo.ActiveAuctionsCount())
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, stored.Status)
+	assert.Equal(t, fakeClock.Now().UTC().Add(40*time.Minute), stored.EndTime)
+	assert.Equal(t, time.Duration(0), stored.PausedRemaining)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(39 * time.Minute)
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, stored.Status)
+
+	fakeClock.Advance(time.Minute)
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+
+	history, total, err := repo.FindStatusHistory(ctx, auction.Id, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, statusReasonPause, history[0].Reason)
+		assert.Equal(t, statusReasonResume, history[1].Reason)
+	}
+}
+
+func TestMemoryResumeRespectsConcurrencyLimit(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	os.Setenv("MAX_CONCURRENT_AUCTIONS", "1")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	defer os.Unsetenv("MAX_CONCURRENT_AUCTIONS")
+
+	repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+	defer repo.Close()
+	ctx := context.Background()
+
+	paused, err := auction_entity.CreateAuction(
+		"Paused Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, paused))
+	assert.Nil(t, repo.PauseAuction(ctx, paused.Id))
+
+	// O pausado libera a vaga para outro leilão
+	other, err := auction_entity.CreateAuction(
+		"Other Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, other))
+
+	resumeErr := repo.ResumeAuction(ctx, paused.Id)
+	assert.NotNil(t, resumeErr)
+	assert.Equal(t, "conflict", resumeErr.Err)
+
+	stored, err := repo.FindAuctionById(ctx, paused.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Paused, stored.Status)
+}
+
+func TestPauseResumePreservesRemainingTime(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+	fakeClock.BlockUntil(1)
+
+	fakeClock.Advance(20 * time.Minute)
+	assert.Nil(t, repo.PauseAuction(ctx, auction.Id))
+
+	stored, err := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Paused, stored.Status)
+	// end_time é guardado em segundos
+	assert.InDelta(t, (40 * time.Minute).Seconds(), stored.PausedRemaining.Seconds(), 1)
+
+	// A recuperação de um novo processo deixa o leilão pausado em paz
+	recovered := NewAuctionRepository(db, WithClock(fakeClock))
+	assert.Eventually(t, recovered.RecoveryDone, time.Second, time.Millisecond)
+	recovered.Close()
+
+	fakeClock.Advance(2 * time.Hour)
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Paused, stored.Status)
+	remaining := stored.PausedRemaining
+
+	assert.Nil(t, repo.ResumeAuction(ctx, auction.Id))
+	stored, err = repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, stored.Status)
+	assert.Equal(t, fakeClock.Now().Add(remaining).Unix(), stored.EndTime.Unix())
+	assert.Equal(t, time.Duration(0), stored.PausedRemaining)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(remaining)
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+}
+
+func TestMemoryCancelAllActiveCancelsPausedAuctions(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewMemoryAuctionRepository(fakeClock)
+	defer repo.Close()
+	ctx := context.Background()
+
+	var auctions []*auction_entity.Auction
+	for i := 0; i < 2; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		auctions = append(auctions, auction)
+	}
+	assert.Nil(t, repo.PauseAuction(ctx, auctions[0].Id))
+
+	cancelled, err := repo.CancelAllActive(ctx, "incident drill")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), cancelled)
+	assert.Equal(t, int64(0), repo.ActiveAuctionsCount())
+
+	// Um leilão pausado não pode ser retomado depois da parada de emergência
+	assert.Equal(t, "conflict", repo.ResumeAuction(ctx, auctions[0].Id).Err)
+	stored, err := repo.FindAuctionById(ctx, auctions[0].Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Cancelled, stored.Status)
+}
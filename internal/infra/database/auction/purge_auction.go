@@ -0,0 +1,72 @@
+package auction
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// WithPurgeSchedule deletes, every interval, the completed and cancelled
+// auctions closed more than retention ago. A non-positive interval or
+// retention leaves the schedule disabled.
+func WithPurgeSchedule(interval, retention time.Duration) RepositoryOption {
+	return func(ar *AuctionRepository) {
+		if interval > 0 && retention > 0 {
+			ar.config.PurgeInterval = interval
+			ar.config.PurgeRetention = retention
+		}
+	}
+}
+
+// PurgeCompletedBefore deletes the completed and cancelled auctions closed
+// before the given time and returns how many were deleted. Active auctions
+// are never touched.
+func (ar *AuctionRepository) PurgeCompletedBefore(
+	ctx context.Context, before time.Time) (int64, *internal_error.InternalError) {
+	filter := bson.M{
+		"status": bson.M{"$in": []auction_entity.AuctionStatus{
+			auction_entity.Completed, auction_entity.Cancelled}},
+		"closed_at": bson.M{"$lt": before.Unix()},
+	}
+
+	result, err := ar.Collection.DeleteMany(ctx, filter)
+	if err != nil {
+		logger.Error("Error trying to purge closed auctions", err)
+		return 0, internal_error.NewInternalServerError("Error trying to purge closed auctions")
+	}
+	if result.DeletedCount > 0 {
+		if ar.cache != nil {
+			ar.cache.purge()
+		}
+		ar.invalidateCachedCounts()
+	}
+
+	logger.Info("Closed auctions purged",
+		zap.Time("before", before),
+		zap.Int64("purged", result.DeletedCount))
+
+	return result.DeletedCount, nil
+}
+
+func (ar *AuctionRepository) runPurgeSchedule() {
+	for {
+		timer := ar.clock.NewTimer(ar.config.PurgeInterval)
+		select {
+		case <-timer.C():
+		case <-ar.stopPurge:
+			stopTimer(timer)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), ar.getPurgeTimeout())
+		// Errors are already logged; the next run simply tries again
+		ar.PurgeCompletedBefore(ctx, ar.clock.Now().Add(-ar.config.PurgeRetention))
+		cancel()
+	}
+}
@@ -0,0 +1,95 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func seedClosedAuctions(t *testing.T, repo *AuctionRepository, now time.Time) {
+	ctx := context.Background()
+	auctions := []AuctionEntityMongo{
+		{Id: "old-completed", Status: auction_entity.Completed, ClosedAt: now.Add(-48 * time.Hour).Unix()},
+		{Id: "old-cancelled", Status: auction_entity.Cancelled, ClosedAt: now.Add(-48 * time.Hour).Unix()},
+		{Id: "recent-completed", Status: auction_entity.Completed, ClosedAt: now.Add(-time.Hour).Unix()},
+		{Id: "old-active", Status: auction_entity.Active, EndTime: now.Add(time.Hour).Unix()},
+	}
+	for _, auction := range auctions {
+		auction.ProductName = "Test Product"
+		auction.Category = "Electronics"
+		auction.Timestamp = now.Add(-72 * time.Hour).Unix()
+		_, err := repo.Collection.InsertOne(ctx, auction)
+		assert.Nil(t, err)
+	}
+}
+
+func TestPurgeCompletedBefore(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	now := time.Now()
+	seedClosedAuctions(t, repo, now)
+
+	purged, err := repo.PurgeCompletedBefore(ctx, now.Add(-24*time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	for _, auctionId := range []string{"old-completed", "old-cancelled"} {
+		_, err := repo.FindAuctionById(ctx, auctionId)
+		assert.NotNil(t, err)
+		assert.Equal(t, "not_found", err.Err)
+	}
+	for _, auctionId := range []string{"recent-completed", "old-active"} {
+		_, err := repo.FindAuctionById(ctx, auctionId)
+		assert.Nil(t, err)
+	}
+}
+
+func TestPurgeSchedule(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	now := time.Now()
+	fakeClock := clock.NewFakeClock(now)
+	repo := NewAuctionRepository(db, WithClock(fakeClock), WithPurgeSchedule(time.Hour, 24*time.Hour))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	seedClosedAuctions(t, repo, now)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	assert.Eventually(t, func() bool {
+		count, err := repo.Collection.CountDocuments(ctx, map[string]interface{}{})
+		return err == nil && count == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, err := repo.FindAuctionById(ctx, "recent-completed")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,110 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+const (
+	readYourWritesRetries = 3
+	readYourWritesBackoff = 20 * time.Millisecond
+)
+
+// ReadYourWritesRepository hides replication lag from the client that
+// created an auction: for window after a CreateAuction, a not found from
+// FindAuctionById on that id is retried up to readYourWritesRetries times
+// with a jittered, doubling backoff. Reads from secondaries may miss an
+// insert for a moment; any other lookup goes through unchanged.
+type ReadYourWritesRepository struct {
+	RoutableRepository
+
+	window time.Duration
+	clock  clock.Clock
+
+	mutex   sync.Mutex
+	created map[string]time.Time
+}
+
+// NewReadYourWritesRepository wraps repository so auctions it creates are
+// looked up again on not found for window.
+func NewReadYourWritesRepository(repository RoutableRepository, window time.Duration) *ReadYourWritesRepository {
+	return &ReadYourWritesRepository{
+		RoutableRepository: repository,
+		window:             window,
+		clock:              clock.NewRealClock(),
+		created:            make(map[string]time.Time),
+	}
+}
+
+func (rr *ReadYourWritesRepository) CreateAuction(
+	ctx context.Context, auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	if err := rr.RoutableRepository.CreateAuction(ctx, auctionEntity); err != nil {
+		return err
+	}
+
+	rr.remember(auctionEntity.Id)
+	return nil
+}
+
+func (rr *ReadYourWritesRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction, err := rr.RoutableRepository.FindAuctionById(ctx, id)
+	if err == nil {
+		return auction, nil
+	}
+
+	backoff := readYourWritesBackoff
+	for attempt := 1; attempt <= readYourWritesRetries; attempt++ {
+		if !errors.Is(err, internal_error.ErrNotFound) || !rr.recentlyCreated(id) {
+			return nil, err
+		}
+
+		// Wait between half and one and a half backoffs so concurrent
+		// readers of the same auction don't retry in lockstep
+		timer := rr.clock.NewTimer(backoff/2 + time.Duration(rand.Int63n(int64(backoff))))
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+		backoff *= 2
+
+		auction, err = rr.RoutableRepository.FindAuctionById(ctx, id)
+		if err == nil {
+			return auction, nil
+		}
+	}
+
+	return nil, err
+}
+
+// remember records id as just created and drops the entries whose window
+// is over.
+func (rr *ReadYourWritesRepository) remember(id string) {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	now := rr.clock.Now()
+	for createdId, createdAt := range rr.created {
+		if now.Sub(createdAt) > rr.window {
+			delete(rr.created, createdId)
+		}
+	}
+	rr.created[id] = now
+}
+
+func (rr *ReadYourWritesRepository) recentlyCreated(id string) bool {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	createdAt, ok := rr.created[id]
+	return ok && rr.clock.Now().Sub(createdAt) <= rr.window
+}
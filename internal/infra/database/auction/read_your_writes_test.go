@@ -0,0 +1,143 @@
+package auction
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// delayedVisibilityRepositoryMock simula uma leitura em secundário: um
+// leilão criado só aparece depois de hiddenLookups buscas
+type delayedVisibilityRepositoryMock struct {
+	RoutableRepository
+
+	mutex         sync.Mutex
+	hiddenLookups int
+	lookups       int
+	auctions      map[string]auction_entity.Auction
+}
+
+func (m *delayedVisibilityRepositoryMock) CreateAuction(
+	ctx context.Context, auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.auctions[auctionEntity.Id] = *auctionEntity
+	return nil
+}
+
+func (m *delayedVisibilityRepositoryMock) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.lookups++
+	auction, ok := m.auctions[id]
+	if !ok || m.lookups <= m.hiddenLookups {
+		return nil, internal_error.NewNotFoundError("Auction not found")
+	}
+	return &auction, nil
+}
+
+func (m *delayedVisibilityRepositoryMock) lookupCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.lookups
+}
+
+func TestReadYourWritesRepository(t *testing.T) {
+	newRepository := func(hiddenLookups int) (*ReadYourWritesRepository, *delayedVisibilityRepositoryMock, *clock.FakeClock) {
+		mock := &delayedVisibilityRepositoryMock{
+			hiddenLookups: hiddenLookups,
+			auctions:      make(map[string]auction_entity.Auction),
+		}
+		fakeClock := clock.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+		repo := NewReadYourWritesRepository(mock, 5*time.Second)
+		repo.clock = fakeClock
+		return repo, mock, fakeClock
+	}
+
+	create := func(repo *ReadYourWritesRepository) string {
+		auction := &auction_entity.Auction{Id: uuid.New().String(), ProductName: "Notebook"}
+		assert.Nil(t, repo.CreateAuction(context.Background(), auction))
+		return auction.Id
+	}
+
+	// find busca em segundo plano e avança o relógio a cada espera, até
+	// retries esperas
+	find := func(repo *ReadYourWritesRepository, fakeClock *clock.FakeClock, id string, retries int) (
+		*auction_entity.Auction, *internal_error.InternalError) {
+		type result struct {
+			auction *auction_entity.Auction
+			err     *internal_error.InternalError
+		}
+		done := make(chan result, 1)
+		go func() {
+			auction, err := repo.FindAuctionById(context.Background(), id)
+			done <- result{auction, err}
+		}()
+
+		for i := 0; i < retries; i++ {
+			fakeClock.BlockUntil(1)
+			fakeClock.Advance(time.Second)
+		}
+
+		select {
+		case found := <-done:
+			return found.auction, found.err
+		case <-time.After(time.Second):
+			t.Fatal("FindAuctionById não terminou")
+			return nil, nil
+		}
+	}
+
+	t.Run("retries until the created auction becomes visible", func(t *testing.T) {
+		repo, mock, fakeClock := newRepository(2)
+		id := create(repo)
+
+		auction, err := find(repo, fakeClock, id, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, id, auction.Id)
+		assert.Equal(t, 3, mock.lookupCount())
+	})
+
+	t.Run("gives up after the last retry", func(t *testing.T) {
+		repo, mock, fakeClock := newRepository(10)
+		id := create(repo)
+
+		auction, err := find(repo, fakeClock, id, readYourWritesRetries)
+		assert.Nil(t, auction)
+		assert.Equal(t, "not_found", err.Err)
+		assert.Equal(t, readYourWritesRetries+1, mock.lookupCount())
+	})
+
+	t.Run("does not retry auctions it did not create", func(t *testing.T) {
+		repo, mock, fakeClock := newRepository(0)
+
+		auction, err := find(repo, fakeClock, uuid.New().String(), 0)
+		assert.Nil(t, auction)
+		assert.Equal(t, "not_found", err.Err)
+		assert.Equal(t, 1, mock.lookupCount())
+		assert.Equal(t, 0, fakeClock.PendingTimers())
+	})
+
+	t.Run("does not retry once the window is over", func(t *testing.T) {
+		repo, mock, fakeClock := newRepository(1)
+		id := create(repo)
+		fakeClock.Advance(6 * time.Second)
+
+		auction, err := find(repo, fakeClock, id, 0)
+		assert.Nil(t, auction)
+		assert.Equal(t, "not_found", err.Err)
+		assert.Equal(t, 1, mock.lookupCount())
+	})
+}
@@ -0,0 +1,78 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"go.uber.org/zap"
+)
+
+// RearmMonitor schedules the auto-close of an active auction again against
+// its stored end_time, for auctions whose monitor was lost without a
+// restart. The scheduler keys monitors by auction id, so rearming an
+// auction that is still monitored only replaces its deadline. An auction
+// not monitored yet takes a slot and is refused when none is free.
+func (ar *AuctionRepository) RearmMonitor(ctx context.Context, auctionId string) *internal_error.InternalError {
+	// Read around the cache, an extension may have moved end_time
+	auction, err := ar.findAuctionByIdFromDatabase(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if auction.Status != auction_entity.Active {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s is not active", auctionId))
+	}
+
+	ar.auctionCountMutex.Lock()
+	defer ar.auctionCountMutex.Unlock()
+
+	if ar.closed {
+		return internal_error.NewConflictError("repository is shutting down")
+	}
+
+	_, monitored := ar.monitoredAuctions[auctionId]
+	if !monitored {
+		if !ar.hasSlotLocked(auction.Category) {
+			return internal_error.NewConflictError("Maximum concurrent auctions limit reached")
+		}
+		ar.trackAuctionLocked(auctionId, auction.Category)
+	}
+	ar.monitors.Schedule(auctionId, ar.closeTime(auction.EndTime))
+
+	logger.Info("Auction monitor rearmed",
+		zap.String("auction_id", auctionId),
+		zap.Bool("was_monitored", monitored),
+		zap.Duration("remaining", auction.EndTime.Sub(ar.clock.Now()).Round(time.Second)))
+
+	return nil
+}
+
+// RearmMonitor schedules the auto-close of an active auction again against
+// its end time, like the Mongo repository does.
+func (mr *MemoryAuctionRepository) RearmMonitor(ctx context.Context, auctionId string) *internal_error.InternalError {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	auction, ok := mr.auctions[auctionId]
+	if !ok {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+
+	if auction.Status != auction_entity.Active {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id = %s is not active", auctionId))
+	}
+
+	if mr.closed {
+		return internal_error.NewConflictError("repository is shutting down")
+	}
+
+	mr.monitors.Schedule(auctionId, mr.settings.closeTime(auction.EndTime))
+	return nil
+}
@@ -0,0 +1,86 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRearmMonitor(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock), WithConfig(Config{AuctionInterval: time.Minute}))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	t.Run("rearms a live auction whose monitor was lost", func(t *testing.T) {
+		// Simulate the lost monitor: the slot stays taken, the deadline is gone
+		repo.monitors.Cancel(auction.Id)
+		assert.Equal(t, 0, repo.monitors.Pending())
+
+		assert.Nil(t, repo.RearmMonitor(ctx, auction.Id))
+		assert.Nil(t, repo.RearmMonitor(ctx, auction.Id))
+		assert.Equal(t, 1, repo.monitors.Pending())
+		assert.Equal(t, int64(1), repo.Stats().Active)
+
+		fakeClock.Advance(time.Minute)
+		assert.Eventually(t, func() bool {
+			found, err := repo.FindAuctionById(ctx, auction.Id)
+			return err == nil && found.Status == auction_entity.Completed
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("rejects an auction that is no longer active", func(t *testing.T) {
+		err := repo.RearmMonitor(ctx, auction.Id)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "conflict", err.Err)
+		}
+		assert.Equal(t, 0, repo.monitors.Pending())
+	})
+
+	t.Run("rejects an unknown auction", func(t *testing.T) {
+		err := repo.RearmMonitor(ctx, "missing")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "not_found", err.Err)
+		}
+	})
+}
+
+func TestMemoryRearmMonitor(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewMemoryAuctionRepository(fakeClock)
+	defer repo.Close()
+	ctx := context.Background()
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	repo.monitors.Cancel(auction.Id)
+	assert.Nil(t, repo.RearmMonitor(ctx, auction.Id))
+	assert.Nil(t, repo.RearmMonitor(ctx, auction.Id))
+	assert.Equal(t, 1, repo.monitors.Pending())
+
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+	err = repo.RearmMonitor(ctx, auction.Id)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "conflict", err.Err)
+	}
+}
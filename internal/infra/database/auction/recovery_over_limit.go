@@ -0,0 +1,120 @@
+package auction
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const overLimitSweepInterval = time.Minute
+
+// Modes of RECOVERY_OVER_LIMIT, applied to the active auctions recovery
+// finds once the concurrency limit is already reached.
+const (
+	// RecoveryOverLimitClose completes the auction, as recovery always did
+	RecoveryOverLimitClose = "close"
+
+	// RecoveryOverLimitKeep monitors the auction anyway, so the active
+	// count exceeds the limit until enough auctions close
+	RecoveryOverLimitKeep = "keep"
+
+	// RecoveryOverLimitSkip leaves the auction Active without a monitor;
+	// RearmMonitor or the next start picks it up, and a periodic sweep
+	// closes it once it is past its end time
+	RecoveryOverLimitSkip = "skip"
+)
+
+// getRecoveryOverLimit resolves the over limit mode from Config, falling
+// back to RecoveryOverLimitClose.
+func (ar *AuctionRepository) getRecoveryOverLimit() string {
+	mode := ar.config.RecoveryOverLimit
+	if mode == "" {
+		return RecoveryOverLimitClose
+	}
+
+	switch normalized := strings.ToLower(strings.TrimSpace(mode)); normalized {
+	case RecoveryOverLimitClose, RecoveryOverLimitKeep, RecoveryOverLimitSkip:
+		return normalized
+	}
+
+	logger.Warn("Invalid RECOVERY_OVER_LIMIT, using the default",
+		zap.String("recovery_over_limit", mode),
+		zap.String("default", RecoveryOverLimitClose))
+	return RecoveryOverLimitClose
+}
+
+// SweepExpiredUnmonitored closes the active auctions past their end time
+// that no monitor of this repository watches, such as those the recovery
+// skipped beyond the limit, and returns how many it closed.
+func (ar *AuctionRepository) SweepExpiredUnmonitored(ctx context.Context) (int64, *internal_error.InternalError) {
+	cursor, err := ar.Collection.Find(ctx, bson.M{
+		"status":   auction_entity.Active,
+		"end_time": bson.M{"$lte": ar.clock.Now().Unix()},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logger.Error("Error trying to find expired auctions without a monitor", err)
+		return 0, internal_error.NewInternalServerError("Error trying to sweep expired auctions")
+	}
+
+	var expired []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &expired); err != nil {
+		logger.Error("Error trying to decode expired auctions without a monitor", err)
+		return 0, internal_error.NewInternalServerError("Error trying to sweep expired auctions")
+	}
+
+	var closed int64
+	for _, auction := range expired {
+		// A monitored auction is closed by its own monitor
+		ar.auctionCountMutex.Lock()
+		_, monitored := ar.monitoredAuctions[auction.Id]
+		ar.auctionCountMutex.Unlock()
+		if monitored {
+			continue
+		}
+
+		applied, err := ar.changeAuctionStatus(ctx, auction.Id, auction_entity.Completed, statusReasonAutoClose)
+		if err != nil {
+			logger.Error("Error closing expired auction without a monitor", err)
+		}
+		if !applied {
+			continue
+		}
+		ar.assignWinnerOnClose(ctx, auction.Id)
+		ar.notifyAuctionClosed(auction.Id)
+		closed++
+	}
+
+	if closed > 0 {
+		logger.Info("Expired auctions without a monitor closed", zap.Int64("closed", closed))
+	}
+	return closed, nil
+}
+
+// runOverLimitSweep runs SweepExpiredUnmonitored every
+// overLimitSweepInterval until the repository is closed.
+func (ar *AuctionRepository) runOverLimitSweep() {
+	for {
+		timer := ar.clock.NewTimer(overLimitSweepInterval)
+		select {
+		case <-timer.C():
+		case <-ar.ctx.Done():
+			stopTimer(timer)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ar.ctx, ensureIndexesTimeout)
+		// Errors are already logged; the next run simply tries again
+		ar.SweepExpiredUnmonitored(ctx)
+		cancel()
+	}
+}
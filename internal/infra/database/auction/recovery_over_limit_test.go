@@ -0,0 +1,143 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRecoveryOverLimit(t *testing.T) {
+	repo := &AuctionRepository{}
+	assert.Equal(t, RecoveryOverLimitClose, repo.getRecoveryOverLimit())
+
+	os.Setenv("RECOVERY_OVER_LIMIT", "Skip")
+	defer os.Unsetenv("RECOVERY_OVER_LIMIT")
+	repo = &AuctionRepository{config: ConfigFromEnv()}
+	assert.Equal(t, RecoveryOverLimitSkip, repo.getRecoveryOverLimit())
+
+	// Explicit values win over the environment
+	explicit := Config{RecoveryOverLimit: RecoveryOverLimitKeep}
+	repo = &AuctionRepository{config: explicit.orElse(ConfigFromEnv())}
+	assert.Equal(t, RecoveryOverLimitKeep, repo.getRecoveryOverLimit())
+
+	os.Setenv("RECOVERY_OVER_LIMIT", "drop")
+	repo = &AuctionRepository{config: ConfigFromEnv()}
+	assert.Equal(t, RecoveryOverLimitClose, repo.getRecoveryOverLimit())
+}
+
+func TestRecoveryOverLimitModes(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	for _, test := range []struct {
+		mode        string
+		overLimit   auction_entity.AuctionStatus
+		activeCount int64
+		monitored   bool
+		counter     string
+	}{
+		{RecoveryOverLimitClose, auction_entity.Completed, 2, false, "recovery_closed_over_limit"},
+		{RecoveryOverLimitKeep, auction_entity.Active, 3, true, "recovery_kept_over_limit"},
+		{RecoveryOverLimitSkip, auction_entity.Active, 2, false, "recovery_skipped_over_limit"},
+	} {
+		t.Run(test.mode, func(t *testing.T) {
+			db, cleanup := setupTestDBForRecovery()
+			defer cleanup()
+
+			ctx := context.Background()
+			now := time.Now()
+			seed := func(id string, endTime time.Time) interface{} {
+				return AuctionEntityMongo{Id: id, ProductName: "Recovery Test Product", Category: "Electronics",
+					Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: endTime.Unix()}
+			}
+			// Três leilões ativos para um limite de dois: o último excede
+			_, err := db.Collection("auctions").InsertMany(ctx, []interface{}{
+				seed("first", now.Add(time.Hour)),
+				seed("second", now.Add(2*time.Hour)),
+				seed("over-limit", now.Add(3*time.Hour)),
+			})
+			assert.Nil(t, err)
+
+			registry := metrics.NewRegistry()
+			repo := NewAuctionRepository(db,
+				WithClock(clock.NewFakeClock(now)),
+				WithMetrics(registry),
+				WithConfig(Config{MaxConcurrentAuctions: 2, RecoveryOverLimit: test.mode}))
+			defer repo.Close()
+			assert.Nil(t, repo.WaitForRecovery(ctx))
+
+			overLimit, findErr := repo.FindAuctionById(ctx, "over-limit")
+			assert.Nil(t, findErr)
+			assert.Equal(t, test.overLimit, overLimit.Status)
+
+			stats := repo.Stats()
+			assert.Equal(t, test.activeCount, stats.Active)
+			assert.Equal(t, int64(1), registry.Snapshot().Counters[test.counter])
+
+			repo.auctionCountMutex.Lock()
+			_, monitored := repo.monitoredAuctions["over-limit"]
+			repo.auctionCountMutex.Unlock()
+			assert.Equal(t, test.monitored, monitored)
+		})
+	}
+}
+
+func TestRecoveryOverLimitSkipClosesExpiredAuctions(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	seed := func(id string, endTime time.Time) AuctionEntityMongo {
+		return AuctionEntityMongo{Id: id, ProductName: "Recovery Test Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: endTime.Unix()}
+	}
+	_, err := db.Collection("auctions").InsertMany(ctx, []interface{}{
+		seed("first", now.Add(time.Hour)),
+		seed("skipped", now.Add(2*time.Hour)),
+	})
+	assert.Nil(t, err)
+
+	fakeClock := clock.NewFakeClock(now)
+	repo := NewAuctionRepository(db,
+		WithClock(fakeClock),
+		WithConfig(Config{MaxConcurrentAuctions: 1, RecoveryOverLimit: RecoveryOverLimitSkip}))
+	defer repo.Close()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	// Um leilão já expirado fecha mesmo sem vaga
+	expired := seed("expired", now.Add(-time.Minute))
+	_, err = db.Collection("auctions").InsertOne(ctx, expired)
+	assert.Nil(t, err)
+	repo.recoverActiveAuctions(ctx, []AuctionEntityMongo{expired})
+	assert.Eventually(t, func() bool {
+		auction, findErr := repo.FindAuctionById(ctx, "expired")
+		return findErr == nil && auction.Status == auction_entity.Completed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// O leilão ignorado continua ativo até a varredura depois do seu fim
+	closed, sweepErr := repo.SweepExpiredUnmonitored(ctx)
+	assert.Nil(t, sweepErr)
+	assert.Equal(t, int64(0), closed)
+
+	fakeClock.Advance(2 * time.Hour)
+	closed, sweepErr = repo.SweepExpiredUnmonitored(ctx)
+	assert.Nil(t, sweepErr)
+	assert.Equal(t, int64(1), closed)
+
+	skipped, findErr := repo.FindAuctionById(ctx, "skipped")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Completed, skipped.Status)
+	assert.Equal(t, auction_entity.CloseReasonTimeout, skipped.CloseReason)
+}
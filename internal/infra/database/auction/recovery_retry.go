@@ -0,0 +1,93 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.uber.org/zap"
+)
+
+const (
+	recoveryCloseRetries      = 3
+	recoveryCloseRetryBackoff = 500 * time.Millisecond
+)
+
+// failedRecoveryClose is an auction the startup recovery tried to close
+// and will try again once the main pass is over.
+type failedRecoveryClose struct {
+	auctionId string
+	reason    string
+}
+
+// recoveryClose closes an auction found by the startup recovery. Tests
+// replace recoveryCloser to inject failures.
+func (ar *AuctionRepository) recoveryClose(ctx context.Context, auctionId, reason string) *internal_error.InternalError {
+	if ar.recoveryCloser != nil {
+		return ar.recoveryCloser(ctx, auctionId, reason)
+	}
+
+	_, err := ar.changeAuctionStatus(ctx, auctionId, auction_entity.Completed, reason)
+	return err
+}
+
+// countRecoveryClose records a successful recovery close in summary, stores
+// the winner as a monitor close would, and announces it.
+func (ar *AuctionRepository) countRecoveryClose(
+	ctx context.Context, summary *recoverySummary, auctionId, reason string) {
+	if reason == statusReasonClockSkew {
+		summary.closedClockSkew++
+	} else {
+		summary.closedOverLimit++
+	}
+	ar.assignWinnerOnClose(ctx, auctionId)
+	ar.notifyAuctionClosed(auctionId)
+}
+
+// retryFailedRecoveryCloses retries the closes that failed during the
+// recovery pass up to recoveryCloseRetries times, doubling the wait before
+// each round. Only unexpected database errors are retried; an auction
+// that was changed or removed meanwhile is left alone. Auctions still
+// failing stay Active and are picked up again on the next start.
+func (ar *AuctionRepository) retryFailedRecoveryCloses(
+	ctx context.Context, failed []failedRecoveryClose, summary *recoverySummary) {
+	backoff := recoveryCloseRetryBackoff
+	for attempt := 1; attempt <= recoveryCloseRetries && len(failed) > 0; attempt++ {
+		timer := ar.clock.NewTimer(backoff)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("Context cancelled, stopping auction recovery retries")
+			return
+		}
+		backoff *= 2
+
+		var stillFailing []failedRecoveryClose
+		for _, pending := range failed {
+			err := ar.recoveryClose(ctx, pending.auctionId, pending.reason)
+			switch {
+			case err == nil:
+				ar.countRecoveryClose(ctx, summary, pending.auctionId, pending.reason)
+			case errors.Is(err, internal_error.ErrInternalServerError):
+				stillFailing = append(stillFailing, pending)
+			default:
+				logger.Warn("Auction changed during recovery, not retrying its close",
+					zap.String("auction_id", pending.auctionId),
+					zap.String("error", err.Error()))
+			}
+		}
+		failed = stillFailing
+	}
+
+	for _, pending := range failed {
+		logger.Warn("Giving up closing auction on restart, it stays active until the next start",
+			zap.String("auction_id", pending.auctionId),
+			zap.String("reason", pending.reason),
+			zap.Int("retries", recoveryCloseRetries))
+	}
+}
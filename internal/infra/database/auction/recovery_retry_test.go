@@ -0,0 +1,55 @@
+package auction
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryRetriesFailedCloses(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDBForRecovery()
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	_, err := db.Collection("auctions").InsertOne(ctx, AuctionEntityMongo{Id: "skewed",
+		ProductName: "Recovery Test Product", Category: "Electronics",
+		Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(48 * time.Hour).Unix()})
+	assert.Nil(t, err)
+
+	// A primeira tentativa de fechamento falha como um erro transitório do banco
+	var attempts atomic.Int32
+	failFirstClose := func(ar *AuctionRepository) {
+		ar.recoveryCloser = func(ctx context.Context, auctionId, reason string) *internal_error.InternalError {
+			if attempts.Add(1) == 1 {
+				return internal_error.NewInternalServerError("transient failure")
+			}
+			_, err := ar.changeAuctionStatus(ctx, auctionId, auction_entity.Completed, reason)
+			return err
+		}
+	}
+
+	registry := metrics.NewRegistry()
+	repo := NewAuctionRepository(db,
+		failFirstClose,
+		WithMetrics(registry),
+		WithConfig(Config{MaxRemainingTime: 24 * time.Hour}))
+	defer repo.Close()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	skewed, findErr := repo.FindAuctionById(ctx, "skewed")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Completed, skewed.Status)
+	assert.Equal(t, int32(2), attempts.Load())
+	assert.Equal(t, int64(1), registry.Snapshot().Counters["recovery_closed_clock_skew"])
+}
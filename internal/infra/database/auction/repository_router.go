@@ -0,0 +1,425 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+// RoutableRepository is what the router needs from each auction database.
+type RoutableRepository interface {
+	auction_entity.AuctionRepositoryInterface
+
+	ExtendForLateBid(
+		ctx context.Context,
+		auctionId string,
+		bidTime time.Time) (time.Time, bool, *internal_error.InternalError)
+
+	AdjustBidCount(ctx context.Context, auctionId string, delta int64) *internal_error.InternalError
+}
+
+// RepositoryRouter spreads auctions over several repositories by category.
+// Categories without a route use the default repository. Lookups by id
+// try every repository, and listings without a category are aggregated.
+type RepositoryRouter struct {
+	defaultRepository RoutableRepository
+	routes            map[string]RoutableRepository
+}
+
+// NewRepositoryRouter builds a router; route keys are matched to
+// categories case-insensitively.
+func NewRepositoryRouter(
+	defaultRepository RoutableRepository,
+	routes map[string]RoutableRepository) *RepositoryRouter {
+	normalizedRoutes := make(map[string]RoutableRepository, len(routes))
+	for category, repository := range routes {
+		normalizedRoutes[auction_entity.NormalizeCategory(category)] = repository
+	}
+
+	return &RepositoryRouter{
+		defaultRepository: defaultRepository,
+		routes:            normalizedRoutes,
+	}
+}
+
+func (rr *RepositoryRouter) CreateAuction(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	return rr.repositoryFor(auctionEntity.Category).CreateAuction(ctx, auctionEntity)
+}
+
+func (rr *RepositoryRouter) FindAuctions(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError) {
+	return rr.findPaged(filter, func(repository RoutableRepository, filter auction_entity.AuctionFilter) (
+		[]auction_entity.Auction, *internal_error.InternalError) {
+		return repository.FindAuctions(ctx, filter)
+	})
+}
+
+// CountAuctions adds up the matching auctions of every repository, or asks
+// only the routed ones when the filter has categories.
+func (rr *RepositoryRouter) CountAuctions(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) (int64, *internal_error.InternalError) {
+	var total int64
+	for _, repository := range rr.repositoriesForFilter(filter) {
+		count, err := repository.CountAuctions(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+func (rr *RepositoryRouter) FindAuctionSummaries(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError) {
+	return rr.findPaged(filter, func(repository RoutableRepository, filter auction_entity.AuctionFilter) (
+		[]auction_entity.Auction, *internal_error.InternalError) {
+		return repository.FindAuctionSummaries(ctx, filter)
+	})
+}
+
+// findPaged runs find on the repositories matching filter. A page spanning
+// several repositories is cut from the merge of the first Page*PageSize
+// auctions of each, which always holds it.
+func (rr *RepositoryRouter) findPaged(
+	filter auction_entity.AuctionFilter,
+	find func(RoutableRepository, auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError),
+) ([]auction_entity.Auction, *internal_error.InternalError) {
+	repositories := rr.repositoriesForFilter(filter)
+	if len(repositories) == 1 || filter.PageSize <= 0 {
+		var auctions []auction_entity.Auction
+		for _, repository := range repositories {
+			found, err := find(repository, filter)
+			if err != nil {
+				return nil, err
+			}
+			auctions = append(auctions, found...)
+		}
+		return auctions, nil
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	leading := filter
+	leading.Page = 1
+	leading.PageSize = page * filter.PageSize
+
+	var auctions []auction_entity.Auction
+	for _, repository := range repositories {
+		found, err := find(repository, leading)
+		if err != nil {
+			return nil, err
+		}
+		auctions = append(auctions, found...)
+	}
+
+	sortOldestFirst(auctions)
+	return pageAuctions(auctions, filter), nil
+}
+
+func (rr *RepositoryRouter) FindAuctionSummaryById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	for _, repository := range rr.repositories() {
+		auction, err := repository.FindAuctionSummaryById(ctx, id)
+		if err == nil || err.Err != "not_found" {
+			return auction, err
+		}
+	}
+
+	return nil, internal_error.NewNotFoundError(
+		fmt.Sprintf("Auction not found with this id = %s", id))
+}
+
+func (rr *RepositoryRouter) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	_, auction, err := rr.locate(ctx, id)
+	return auction, err
+}
+
+// FindAuctionsStatusByIds asks every repository, since ids do not tell
+// which one holds an auction, and merges what they find.
+func (rr *RepositoryRouter) FindAuctionsStatusByIds(
+	ctx context.Context,
+	auctionIds []string) (map[string]auction_entity.AuctionStatus, *internal_error.InternalError) {
+	statuses := make(map[string]auction_entity.AuctionStatus, len(auctionIds))
+	for _, repository := range rr.repositories() {
+		if len(statuses) == len(auctionIds) {
+			break
+		}
+
+		found, err := repository.FindAuctionsStatusByIds(ctx, auctionIds)
+		if err != nil {
+			return nil, err
+		}
+		for auctionId, status := range found {
+			statuses[auctionId] = status
+		}
+	}
+
+	return statuses, nil
+}
+
+func (rr *RepositoryRouter) UpdateAuctionStatus(
+	ctx context.Context,
+	auctionId string,
+	status auction_entity.AuctionStatus) *internal_error.InternalError {
+	repository, _, err := rr.locate(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	return repository.UpdateAuctionStatus(ctx, auctionId, status)
+}
+
+func (rr *RepositoryRouter) PauseAuction(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	repository, _, err := rr.locate(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	return repository.PauseAuction(ctx, auctionId)
+}
+
+func (rr *RepositoryRouter) ResumeAuction(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	repository, _, err := rr.locate(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	return repository.ResumeAuction(ctx, auctionId)
+}
+
+func (rr *RepositoryRouter) FindStatusHistory(
+	ctx context.Context,
+	auctionId string,
+	page, pageSize int64) ([]auction_entity.StatusChange, int64, *internal_error.InternalError) {
+	repository, _, err := rr.locate(ctx, auctionId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return repository.FindStatusHistory(ctx, auctionId, page, pageSize)
+}
+
+func (rr *RepositoryRouter) UpdateAuction(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	update auction_entity.AuctionUpdate) (*auction_entity.Auction, *internal_error.InternalError) {
+	repository, _, err := rr.locate(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.UpdateAuction(ctx, auctionId, expectedVersion, update)
+}
+
+func (rr *RepositoryRouter) CancelAllActive(
+	ctx context.Context, reason string) (int64, *internal_error.InternalError) {
+	var cancelled int64
+	for _, repository := range rr.repositories() {
+		count, err := repository.CancelAllActive(ctx, reason)
+		cancelled += count
+		if err != nil {
+			return cancelled, err
+		}
+	}
+
+	return cancelled, nil
+}
+
+// SetMaxConcurrentAuctions gives every repository the same limit; each
+// still counts only its own active auctions against it.
+func (rr *RepositoryRouter) SetMaxConcurrentAuctions(n int64) *internal_error.InternalError {
+	for _, repository := range rr.repositories() {
+		if err := repository.SetMaxConcurrentAuctions(n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetMaintenanceMode switches every repository at once.
+func (rr *RepositoryRouter) SetMaintenanceMode(enabled bool) {
+	for _, repository := range rr.repositories() {
+		repository.SetMaintenanceMode(enabled)
+	}
+}
+
+func (rr *RepositoryRouter) PurgeCompletedBefore(
+	ctx context.Context, before time.Time) (int64, *internal_error.InternalError) {
+	var purged int64
+	for _, repository := range rr.repositories() {
+		count, err := repository.PurgeCompletedBefore(ctx, before)
+		purged += count
+		if err != nil {
+			return purged, err
+		}
+	}
+
+	return purged, nil
+}
+
+// AuctionStats adds up the auction totals of every repository. Bids live in
+// the main database for all of them, so they are counted once.
+func (rr *RepositoryRouter) AuctionStats(
+	ctx context.Context) (*auction_entity.AuctionStats, *internal_error.InternalError) {
+	total := &auction_entity.AuctionStats{}
+	for i, repository := range rr.repositories() {
+		stats, err := repository.AuctionStats(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		total.Total += stats.Total
+		total.Active += stats.Active
+		total.Completed += stats.Completed
+		total.CompletedDuration += stats.CompletedDuration
+		if i == 0 {
+			total.Bids = stats.Bids
+		}
+	}
+
+	return total, nil
+}
+
+// CountAuctionsByTime merges the creation series of every repository.
+func (rr *RepositoryRouter) CountAuctionsByTime(
+	ctx context.Context,
+	bucket auction_entity.TimeBucket,
+	from, to time.Time) ([]auction_entity.AuctionCountBucket, *internal_error.InternalError) {
+	var series [][]auction_entity.AuctionCountBucket
+	for _, repository := range rr.repositories() {
+		buckets, err := repository.CountAuctionsByTime(ctx, bucket, from, to)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, buckets)
+	}
+
+	return mergeCountBuckets(series...), nil
+}
+
+// FindAuctionsEndingBefore merges the auctions ending soon of every
+// repository, keeping them ordered by end time.
+func (rr *RepositoryRouter) FindAuctionsEndingBefore(
+	ctx context.Context, before time.Time) ([]auction_entity.Auction, *internal_error.InternalError) {
+	var auctions []auction_entity.Auction
+	for _, repository := range rr.repositories() {
+		found, err := repository.FindAuctionsEndingBefore(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		auctions = append(auctions, found...)
+	}
+
+	sortByEndTime(auctions)
+	return auctions, nil
+}
+
+func (rr *RepositoryRouter) ExtendForLateBid(
+	ctx context.Context,
+	auctionId string,
+	bidTime time.Time) (time.Time, bool, *internal_error.InternalError) {
+	repository, _, err := rr.locate(ctx, auctionId)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return repository.ExtendForLateBid(ctx, auctionId, bidTime)
+}
+
+func (rr *RepositoryRouter) AdjustBidCount(
+	ctx context.Context, auctionId string, delta int64) *internal_error.InternalError {
+	repository, _, err := rr.locate(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	return repository.AdjustBidCount(ctx, auctionId, delta)
+}
+
+func (rr *RepositoryRouter) repositoryFor(category string) RoutableRepository {
+	if repository, ok := rr.routes[auction_entity.NormalizeCategory(category)]; ok {
+		return repository
+	}
+
+	return rr.defaultRepository
+}
+
+// repositoriesForFilter lists the repositories a listing must ask: the
+// ones routed for the filter categories, or all of them when it has none.
+func (rr *RepositoryRouter) repositoriesForFilter(filter auction_entity.AuctionFilter) []RoutableRepository {
+	categories := filter.FilterCategories()
+	if len(categories) == 0 {
+		return rr.repositories()
+	}
+
+	var repositories []RoutableRepository
+	for _, category := range categories {
+		repository := rr.repositoryFor(category)
+		duplicate := false
+		for _, seen := range repositories {
+			if seen == repository {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			repositories = append(repositories, repository)
+		}
+	}
+
+	return repositories
+}
+
+// repositories lists each distinct repository once, default first.
+func (rr *RepositoryRouter) repositories() []RoutableRepository {
+	repositories := []RoutableRepository{rr.defaultRepository}
+	for _, repository := range rr.routes {
+		duplicate := false
+		for _, seen := range repositories {
+			if seen == repository {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			repositories = append(repositories, repository)
+		}
+	}
+
+	return repositories
+}
+
+// locate finds the repository holding auctionId. Only not found errors
+// move on to the next repository.
+func (rr *RepositoryRouter) locate(
+	ctx context.Context,
+	auctionId string) (RoutableRepository, *auction_entity.Auction, *internal_error.InternalError) {
+	for _, repository := range rr.repositories() {
+		auction, err := repository.FindAuctionById(ctx, auctionId)
+		if err == nil {
+			return repository, auction, nil
+		}
+		if err.Err != "not_found" {
+			return nil, nil, err
+		}
+	}
+
+	return nil, nil, internal_error.NewNotFoundError(
+		fmt.Sprintf("Auction not found with this id = %s", auctionId))
+}
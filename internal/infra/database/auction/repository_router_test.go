@@ -0,0 +1,211 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRoutableRepository struct {
+	RoutableRepository
+
+	auctions map[string]auction_entity.Auction
+	calls    []string
+}
+
+func newFakeRoutableRepository() *fakeRoutableRepository {
+	return &fakeRoutableRepository{auctions: make(map[string]auction_entity.Auction)}
+}
+
+func (f *fakeRoutableRepository) CreateAuction(
+	ctx context.Context, auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	f.calls = append(f.calls, "CreateAuction")
+	f.auctions[auctionEntity.Id] = *auctionEntity
+	return nil
+}
+
+func (f *fakeRoutableRepository) FindAuctions(
+	ctx context.Context,
+	filter auction_entity.AuctionFilter) ([]auction_entity.Auction, *internal_error.InternalError) {
+	f.calls = append(f.calls, "FindAuctions")
+
+	var auctions []auction_entity.Auction
+	for _, auction := range f.auctions {
+		if filter.Category == "" || auction.Category == filter.Category {
+			auctions = append(auctions, auction)
+		}
+	}
+	sortOldestFirst(auctions)
+	return pageAuctions(auctions, filter), nil
+}
+
+func (f *fakeRoutableRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	f.calls = append(f.calls, "FindAuctionById")
+
+	auction, ok := f.auctions[id]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("auction not found")
+	}
+	return &auction, nil
+}
+
+func (f *fakeRoutableRepository) UpdateAuctionStatus(
+	ctx context.Context, auctionId string, status auction_entity.AuctionStatus) *internal_error.InternalError {
+	f.calls = append(f.calls, "UpdateAuctionStatus")
+
+	auction := f.auctions[auctionId]
+	auction.Status = status
+	f.auctions[auctionId] = auction
+	return nil
+}
+
+func (f *fakeRoutableRepository) FindAuctionsStatusByIds(
+	ctx context.Context, auctionIds []string) (map[string]auction_entity.AuctionStatus, *internal_error.InternalError) {
+	f.calls = append(f.calls, "FindAuctionsStatusByIds")
+
+	statuses := make(map[string]auction_entity.AuctionStatus)
+	for _, auctionId := range auctionIds {
+		if auction, ok := f.auctions[auctionId]; ok {
+			statuses[auctionId] = auction.Status
+		}
+	}
+	return statuses, nil
+}
+
+func (f *fakeRoutableRepository) CancelAllActive(
+	ctx context.Context, reason string) (int64, *internal_error.InternalError) {
+	f.calls = append(f.calls, "CancelAllActive")
+	return int64(len(f.auctions)), nil
+}
+
+func TestRepositoryRouter(t *testing.T) {
+	ctx := context.Background()
+
+	newRouter := func() (*RepositoryRouter, *fakeRoutableRepository, *fakeRoutableRepository) {
+		electronics := newFakeRoutableRepository()
+		art := newFakeRoutableRepository()
+		router := NewRepositoryRouter(electronics, map[string]RoutableRepository{"art": art})
+		return router, electronics, art
+	}
+
+	t.Run("create goes to the repository of the category", func(t *testing.T) {
+		router, electronics, art := newRouter()
+
+		assert.Nil(t, router.CreateAuction(ctx, &auction_entity.Auction{Id: "phone", Category: "Electronics"}))
+		assert.Nil(t, router.CreateAuction(ctx, &auction_entity.Auction{Id: "painting", Category: "Art"}))
+
+		assert.Contains(t, electronics.auctions, "phone")
+		assert.NotContains(t, electronics.auctions, "painting")
+		assert.Contains(t, art.auctions, "painting")
+		assert.NotContains(t, art.auctions, "phone")
+	})
+
+	t.Run("find with category only queries its repository", func(t *testing.T) {
+		router, electronics, art := newRouter()
+		art.auctions["painting"] = auction_entity.Auction{Id: "painting", Category: "Art"}
+
+		auctions, err := router.FindAuctions(ctx, auction_entity.AuctionFilter{
+			Status: auction_entity.Active, Category: "Art"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"painting"}, auctionIds(auctions))
+		assert.Empty(t, electronics.calls)
+	})
+
+	t.Run("find with categories queries each routed repository once", func(t *testing.T) {
+		router, electronics, art := newRouter()
+		electronics.auctions["phone"] = auction_entity.Auction{Id: "phone", Category: "Electronics"}
+		art.auctions["painting"] = auction_entity.Auction{Id: "painting", Category: "Art"}
+
+		_, err := router.FindAuctions(ctx, auction_entity.AuctionFilter{Categories: []string{"art", "ART"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"FindAuctions"}, art.calls)
+		assert.Empty(t, electronics.calls)
+
+		_, err = router.FindAuctions(ctx, auction_entity.AuctionFilter{Categories: []string{"Art", "Electronics"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"FindAuctions"}, electronics.calls)
+	})
+
+	t.Run("find without category aggregates every repository", func(t *testing.T) {
+		router, electronics, art := newRouter()
+		electronics.auctions["phone"] = auction_entity.Auction{Id: "phone", Category: "Electronics"}
+		art.auctions["painting"] = auction_entity.Auction{Id: "painting", Category: "Art"}
+
+		auctions, err := router.FindAuctions(ctx, auction_entity.AuctionFilter{Status: auction_entity.Active})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"phone", "painting"}, auctionIds(auctions))
+
+		cancelled, err := router.CancelAllActive(ctx, "maintenance")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), cancelled)
+	})
+
+	t.Run("pages are merged across repositories oldest first", func(t *testing.T) {
+		router, electronics, art := newRouter()
+		start := time.Unix(1718985600, 0)
+		for i, id := range []string{"a", "b", "c", "d", "e"} {
+			auction := auction_entity.Auction{Id: id, Timestamp: start.Add(time.Duration(i) * time.Minute)}
+			// Intercalados entre os dois bancos
+			if i%2 == 0 {
+				electronics.auctions[id] = auction
+			} else {
+				art.auctions[id] = auction
+			}
+		}
+
+		for page, expected := range map[int64][]string{1: {"a", "b"}, 2: {"c", "d"}, 3: {"e"}, 4: {}} {
+			auctions, err := router.FindAuctions(ctx, auction_entity.AuctionFilter{Page: page, PageSize: 2})
+			assert.Nil(t, err)
+			assert.Equal(t, expected, auctionIds(auctions), "page %d", page)
+		}
+	})
+
+	t.Run("lookups by id find the owning repository", func(t *testing.T) {
+		router, electronics, art := newRouter()
+		art.auctions["painting"] = auction_entity.Auction{
+			Id: "painting", Category: "Art", Status: auction_entity.Active}
+
+		auction, err := router.FindAuctionById(ctx, "painting")
+		assert.Nil(t, err)
+		assert.Equal(t, "painting", auction.Id)
+
+		assert.Nil(t, router.UpdateAuctionStatus(ctx, "painting", auction_entity.Completed))
+		assert.Equal(t, auction_entity.Completed, art.auctions["painting"].Status)
+		assert.NotContains(t, electronics.calls, "UpdateAuctionStatus")
+
+		_, err = router.FindAuctionById(ctx, "missing")
+		assert.NotNil(t, err)
+		assert.Equal(t, "not_found", err.Err)
+	})
+
+	t.Run("statuses by id are merged across repositories", func(t *testing.T) {
+		router, electronics, art := newRouter()
+		electronics.auctions["phone"] = auction_entity.Auction{
+			Id: "phone", Category: "Electronics", Status: auction_entity.Completed}
+		art.auctions["painting"] = auction_entity.Auction{
+			Id: "painting", Category: "Art", Status: auction_entity.Active}
+
+		statuses, err := router.FindAuctionsStatusByIds(ctx, []string{"phone", "painting", "missing"})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]auction_entity.AuctionStatus{
+			"phone":    auction_entity.Completed,
+			"painting": auction_entity.Active,
+		}, statuses)
+	})
+
+	t.Run("without routes everything uses the default repository", func(t *testing.T) {
+		single := newFakeRoutableRepository()
+		router := NewRepositoryRouter(single, nil)
+
+		assert.Nil(t, router.CreateAuction(ctx, &auction_entity.Auction{Id: "painting", Category: "Art"}))
+		assert.Contains(t, single.auctions, "painting")
+
+		_, _, err := router.ExtendForLateBid(ctx, "missing", time.Now())
+		assert.Equal(t, "not_found", err.Err)
+	})
+}
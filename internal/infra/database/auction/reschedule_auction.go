@@ -0,0 +1,76 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// RescheduleActiveAuctions applies a new auction duration to the auctions
+// already running: each active auction ends at timestamp + newDuration and
+// its monitor is rescheduled, so auctions already past the new end time
+// close right away. It returns how many auctions were rescheduled. Auctions
+// created afterwards still follow AUCTION_INTERVAL or the Config.
+func (ar *AuctionRepository) RescheduleActiveAuctions(
+	ctx context.Context, newDuration time.Duration) (int64, *internal_error.InternalError) {
+	if newDuration <= 0 {
+		return 0, internal_error.NewBadRequestError("Auction duration must be positive")
+	}
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"status": auction_entity.Active},
+		options.Find().SetProjection(bson.M{"_id": 1, "timestamp": 1, "end_time": 1}))
+	if err != nil {
+		logger.Error("Error trying to find active auctions to reschedule", err)
+		return 0, internal_error.NewInternalServerError("Error trying to reschedule active auctions")
+	}
+
+	var activeAuctions []AuctionEntityMongo
+	if err := cursor.All(ctx, &activeAuctions); err != nil {
+		logger.Error("Error trying to decode active auctions to reschedule", err)
+		return 0, internal_error.NewInternalServerError("Error trying to reschedule active auctions")
+	}
+
+	var rescheduled int64
+	for _, auction := range activeAuctions {
+		newEndTime := time.Unix(auction.Timestamp, 0).UTC().Add(newDuration)
+
+		// Skip auctions closed or extended since they were read
+		filter := bson.M{
+			"_id":      auction.Id,
+			"status":   auction_entity.Active,
+			"end_time": auction.EndTime,
+		}
+		update := bson.M{
+			"$set": bson.M{"end_time": newEndTime.Unix()},
+			"$inc": bson.M{"version": 1},
+		}
+
+		result, err := ar.Collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Error trying to reschedule auction with id = %s", auction.Id), err)
+			return rescheduled, internal_error.NewInternalServerError("Error trying to reschedule active auctions")
+		}
+		ar.invalidateCachedAuction(auction.Id)
+
+		if result.MatchedCount == 0 {
+			continue
+		}
+
+		ar.rescheduleMonitor(auction.Id, newEndTime)
+		rescheduled++
+	}
+
+	logger.Info("Active auctions rescheduled",
+		zap.Duration("duration", newDuration),
+		zap.Int64("rescheduled", rescheduled))
+
+	return rescheduled, nil
+}
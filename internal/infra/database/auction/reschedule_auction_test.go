@@ -0,0 +1,75 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRescheduleActiveAuctions(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupAutoCloseTestDB()
+	defer cleanup()
+
+	now := time.Now()
+	fakeClock := clock.NewFakeClock(now)
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	recent, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, recent))
+
+	// Criado há 30 minutos: com o novo intervalo de 10 minutos já venceu
+	old, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	old.Timestamp = now.Add(-30 * time.Minute)
+	assert.Nil(t, repo.CreateAuction(ctx, old))
+
+	fakeClock.BlockUntil(1)
+
+	_, rescheduleErr := repo.RescheduleActiveAuctions(ctx, 0)
+	assert.NotNil(t, rescheduleErr)
+
+	rescheduled, rescheduleErr := repo.RescheduleActiveAuctions(ctx, 10*time.Minute)
+	assert.Nil(t, rescheduleErr)
+	assert.Equal(t, int64(2), rescheduled)
+
+	var stored AuctionEntityMongo
+	assert.Nil(t, repo.Collection.FindOne(ctx, bson.M{"_id": recent.Id}).Decode(&stored))
+	assert.Equal(t, stored.Timestamp+int64((10*time.Minute).Seconds()), stored.EndTime)
+
+	// O leilão antigo fecha sem avançar o relógio
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, old.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+
+	found, err := repo.FindAuctionById(ctx, recent.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, found.Status)
+
+	// O recente fecha em 10 minutos em vez de 1 hora
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(10 * time.Minute)
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, recent.Id)
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,69 @@
+package auction
+
+import (
+	"os"
+	"strings"
+)
+
+// RuntimeConfig is the configuration the repository actually runs with,
+// after env parsing and defaults. It is meant for debugging, so secrets
+// must be redacted by whoever exposes it.
+type RuntimeConfig struct {
+	AuctionInterval         string   `json:"auction_interval"`
+	MaxConcurrentAuctions   int64    `json:"max_concurrent_auctions"`
+	AutoCloseEnabled        bool     `json:"auto_close_enabled"`
+	MonitorWorkers          int      `json:"monitor_workers"`
+	AllowedCategories       []string `json:"allowed_categories"`
+	DescriptionOverflowMode string   `json:"description_overflow_mode"`
+	DescriptionCompression  bool     `json:"description_compression"`
+	AntiSnipeEnabled        bool     `json:"anti_snipe_enabled"`
+	AntiSnipeWindow         string   `json:"anti_snipe_window"`
+	AntiSnipeExtension      string   `json:"anti_snipe_extension"`
+	CacheSize               int      `json:"cache_size"`
+	CacheTTL                string   `json:"cache_ttl,omitempty"`
+	PurgeInterval           string   `json:"purge_interval,omitempty"`
+	PurgeRetention          string   `json:"purge_retention,omitempty"`
+	RecoveryOverLimit       string   `json:"recovery_over_limit"`
+	StrictAudit             bool     `json:"strict_audit"`
+	SchemaValidation        bool     `json:"schema_validation"`
+	CloseWebhookURL         string   `json:"close_webhook_url"`
+	MaintenanceMode         bool     `json:"maintenance_mode"`
+}
+
+func (ar *AuctionRepository) RuntimeConfig() RuntimeConfig {
+	config := RuntimeConfig{
+		AuctionInterval:       ar.getAuctionDuration().String(),
+		MaxConcurrentAuctions: ar.getMaxConcurrentAuctions(),
+		// Auctions are always closed by the monitors, there is no switch
+		AutoCloseEnabled:        true,
+		MonitorWorkers:          ar.getMonitorWorkers(),
+		AllowedCategories:       ar.getAllowedCategories(),
+		DescriptionOverflowMode: "reject",
+		DescriptionCompression:  ar.isDescriptionCompressionEnabled(),
+		AntiSnipeEnabled:        ar.isAntiSnipeEnabled(),
+		AntiSnipeWindow:         ar.getAntiSnipeWindow().String(),
+		AntiSnipeExtension:      ar.getAntiSnipeExtension().String(),
+		RecoveryOverLimit:       ar.getRecoveryOverLimit(),
+		StrictAudit:             isStrictAudit(),
+		SchemaValidation:        ar.isSchemaValidationEnabled(),
+		CloseWebhookURL:         os.Getenv("CLOSE_WEBHOOK_URL"),
+		MaintenanceMode:         ar.MaintenanceMode(),
+	}
+
+	if ar.monitors != nil {
+		config.MonitorWorkers = ar.monitors.Workers()
+	}
+	if strings.EqualFold(os.Getenv("DESCRIPTION_OVERFLOW_MODE"), "truncate") {
+		config.DescriptionOverflowMode = "truncate"
+	}
+	if ar.cache != nil {
+		config.CacheSize = ar.cache.size
+		config.CacheTTL = ar.cache.ttl.String()
+	}
+	if ar.purgeEnabled() {
+		config.PurgeInterval = ar.config.PurgeInterval.String()
+		config.PurgeRetention = ar.config.PurgeRetention.String()
+	}
+
+	return config
+}
@@ -0,0 +1,104 @@
+package auction
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	namespaceNotFoundCode = 26
+	namespaceExistsCode   = 48
+
+	// Existing documents that break the schema can still be updated
+	schemaValidationLevel  = "moderate"
+	schemaValidationAction = "error"
+)
+
+// auctionStatuses lists every valid status, enforced by the schema.
+var auctionStatuses = bson.A{
+	auction_entity.Active, auction_entity.Completed, auction_entity.Cancelled, auction_entity.Paused,
+}
+
+func (ar *AuctionRepository) isSchemaValidationEnabled() bool {
+	return ar.config.SchemaValidation
+}
+
+// auctionsValidator is the $jsonSchema validator of the auctions
+// collection. Go ints are stored as int or long depending on their value,
+// so numeric fields accept both.
+func auctionsValidator() bson.M {
+	integer := bson.A{"int", "long"}
+	positive := bson.M{"bsonType": integer, "minimum": 1}
+	nonNegative := bson.M{"bsonType": integer, "minimum": 0}
+
+	return bson.M{"$jsonSchema": bson.M{
+		"bsonType": "object",
+		"required": bson.A{
+			"_id", "product_name", "category", "description", "condition", "status", "timestamp", "end_time",
+		},
+		"properties": bson.M{
+			"_id":          bson.M{"bsonType": "string"},
+			"product_name": bson.M{"bsonType": "string", "minLength": 1},
+			"category":     bson.M{"bsonType": "string", "minLength": 1},
+			"subcategory":  bson.M{"bsonType": "string"},
+			"description":  bson.M{"bsonType": "string"},
+			"condition":    bson.M{"bsonType": integer},
+			"status":       bson.M{"bsonType": integer, "enum": auctionStatuses},
+			"timestamp":    positive,
+			"end_time":     positive,
+			"closed_at":    positive,
+			"version":      nonNegative,
+			"bid_count":    nonNegative,
+			"compressed":   bson.M{"bsonType": "bool"},
+		},
+	}}
+}
+
+// applySchemaValidation installs the validator on the auctions collection,
+// creating the collection when it does not exist yet. Like the indexes,
+// failures are logged and never block startup.
+func (ar *AuctionRepository) applySchemaValidation() {
+	ctx, cancel := context.WithTimeout(context.Background(), ensureIndexesTimeout)
+	defer cancel()
+
+	err := ar.modifyValidator(ctx)
+	if hasCommandErrorCode(err, namespaceNotFoundCode) {
+		err = ar.Collection.Database().CreateCollection(ctx, ar.Collection.Name(),
+			options.CreateCollection().
+				SetValidator(auctionsValidator()).
+				SetValidationLevel(schemaValidationLevel).
+				SetValidationAction(schemaValidationAction))
+
+		// Another instance created it first
+		if hasCommandErrorCode(err, namespaceExistsCode) {
+			err = ar.modifyValidator(ctx)
+		}
+	}
+
+	if err != nil {
+		logger.Error("Error trying to apply the auctions schema validation", err)
+		return
+	}
+	logger.Info("Auctions schema validation enabled")
+}
+
+func (ar *AuctionRepository) modifyValidator(ctx context.Context) error {
+	return ar.Collection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: ar.Collection.Name()},
+		{Key: "validator", Value: auctionsValidator()},
+		{Key: "validationLevel", Value: schemaValidationLevel},
+		{Key: "validationAction", Value: schemaValidationAction},
+	}).Err()
+}
+
+func hasCommandErrorCode(err error, code int32) bool {
+	var commandErr mongo.CommandError
+	return errors.As(err, &commandErr) && commandErr.Code == code
+}
@@ -0,0 +1,48 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSchemaValidationRejectsMalformedInserts(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db, WithConfig(Config{AuctionInterval: time.Hour, SchemaValidation: true}))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Nil(t, repo.WaitForRecovery(ctx))
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	now := time.Now().Unix()
+	for name, document := range map[string]bson.M{
+		"missing fields": {"_id": "missing", "product_name": "Test Product"},
+		"unknown status": {"_id": "status", "product_name": "Test Product", "category": "Electronics",
+			"description": "Test description", "condition": 1, "status": 9, "timestamp": now, "end_time": now},
+		"negative timestamp": {"_id": "timestamp", "product_name": "Test Product", "category": "Electronics",
+			"description": "Test description", "condition": 1, "status": 0, "timestamp": -1, "end_time": now},
+		"wrong type": {"_id": "type", "product_name": "Test Product", "category": "Electronics",
+			"description": "Test description", "condition": 1, "status": "active", "timestamp": now, "end_time": now},
+	} {
+		_, err := db.Collection("auctions").InsertOne(ctx, document)
+		assert.NotNil(t, err, name)
+	}
+
+	count, countErr := db.Collection("auctions").CountDocuments(ctx, bson.M{})
+	assert.Nil(t, countErr)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,163 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	statusReasonUpdate        = "status_update"
+	statusReasonAutoClose     = "auto_close"
+	statusReasonRecoveryLimit = "recovery_limit"
+	statusReasonClockSkew     = "clock_skew"
+	statusReasonPause         = "pause"
+	statusReasonResume        = "resume"
+)
+
+// closeReasonFor maps the history reason of a status change to the close
+// reason stored on the auction.
+func closeReasonFor(statusReason string) string {
+	switch statusReason {
+	case statusReasonAutoClose:
+		return auction_entity.CloseReasonTimeout
+	case statusReasonRecoveryLimit:
+		return auction_entity.CloseReasonRecoveryLimit
+	case statusReasonClockSkew:
+		return auction_entity.CloseReasonClockSkew
+	default:
+		return auction_entity.CloseReasonManual
+	}
+}
+
+// StatusHistoryEntityMongo is an append-only record of a status change,
+// stored in the auction_status_history collection.
+type StatusHistoryEntityMongo struct {
+	AuctionId string                       `bson:"auction_id"`
+	From      auction_entity.AuctionStatus `bson:"from"`
+	To        auction_entity.AuctionStatus `bson:"to"`
+	At        int64                        `bson:"at"`
+	Reason    string                       `bson:"reason"`
+
+	// CloseReason is set when the change closed the auction
+	CloseReason string `bson:"close_reason,omitempty"`
+}
+
+// recordStatusChanges appends one history entry per auction. Failures are
+// only logged unless STRICT_AUDIT is enabled, in which case they are
+// returned to the caller (the status change itself is already stored).
+func (ar *AuctionRepository) recordStatusChanges(
+	ctx context.Context,
+	reason, closeReason string,
+	from, to auction_entity.AuctionStatus,
+	auctionIds ...string) *internal_error.InternalError {
+	if len(auctionIds) == 0 {
+		return nil
+	}
+
+	at := ar.clock.Now().Unix()
+	entries := make([]interface{}, 0, len(auctionIds))
+	for _, auctionId := range auctionIds {
+		entries = append(entries, StatusHistoryEntityMongo{
+			AuctionId: auctionId,
+			From:      from,
+			To:        to,
+			At:        at,
+			Reason:    reason,
+
+			CloseReason: closeReason,
+		})
+	}
+
+	if _, err := ar.historyCollection.InsertMany(ctx, entries); err != nil {
+		logger.Error("Error trying to record auction status history", err,
+			zap.Strings("auction_ids", auctionIds),
+			zap.String("reason", reason))
+
+		if isStrictAudit() {
+			return internal_error.NewInternalServerError("Error trying to record auction status history")
+		}
+	}
+
+	return nil
+}
+
+func isStrictAudit() bool {
+	strict, err := strconv.ParseBool(os.Getenv("STRICT_AUDIT"))
+	return err == nil && strict
+}
+
+// FindStatusHistory pages through the status changes of an auction, oldest
+// first. Changes recorded in the same second keep their insertion order.
+func (ar *AuctionRepository) FindStatusHistory(
+	ctx context.Context,
+	auctionId string,
+	page, pageSize int64) ([]auction_entity.StatusChange, int64, *internal_error.InternalError) {
+	if _, err := ar.findAuctionByIdFromDatabase(ctx, auctionId); err != nil {
+		return nil, 0, err
+	}
+	page, pageSize = normalizePage(page, pageSize)
+
+	filter := bson.M{"auction_id": auctionId}
+	total, err := ar.historyCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to count status history of auction with id = %s", auctionId), err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find auction status history")
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+	cursor, err := ar.historyCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find status history of auction with id = %s", auctionId), err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find auction status history")
+	}
+
+	var entries []StatusHistoryEntityMongo
+	if err := cursor.All(ctx, &entries); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode status history of auction with id = %s", auctionId), err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find auction status history")
+	}
+
+	changes := make([]auction_entity.StatusChange, 0, len(entries))
+	for _, entry := range entries {
+		changes = append(changes, auction_entity.StatusChange{
+			AuctionId:   entry.AuctionId,
+			From:        entry.From,
+			To:          entry.To,
+			At:          time.Unix(entry.At, 0).UTC(),
+			Reason:      entry.Reason,
+			CloseReason: entry.CloseReason,
+		})
+	}
+
+	return changes, total, nil
+}
+
+// normalizePage defaults a missing page to the first one and an invalid
+// page size to the default search page size. Pages past maxSearchPage are
+// capped so their offset can't overflow.
+func normalizePage(page, pageSize int64) (int64, int64) {
+	if page < 1 {
+		page = 1
+	}
+	if page > maxSearchPage {
+		page = maxSearchPage
+	}
+	if pageSize < 1 || pageSize > maxSearchPageSize {
+		pageSize = defaultSearchPageSize
+	}
+	return page, pageSize
+}
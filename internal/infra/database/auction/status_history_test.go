@@ -0,0 +1,96 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestStatusHistoryRecordedOnAutoClose(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	var history StatusHistoryEntityMongo
+	assert.Eventually(t, func() bool {
+		return repo.historyCollection.FindOne(ctx, bson.M{"auction_id": auction.Id}).Decode(&history) == nil
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, auction_entity.Active, history.From)
+	assert.Equal(t, auction_entity.Completed, history.To)
+	assert.Equal(t, statusReasonAutoClose, history.Reason)
+	assert.Equal(t, auction_entity.CloseReasonTimeout, history.CloseReason)
+	assert.Equal(t, fakeClock.Now().Unix(), history.At)
+
+	stored, findErr := repo.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.CloseReasonTimeout, stored.CloseReason)
+}
+
+func TestStatusHistoryRecordedOnCancelAll(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	now := time.Now()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "history-active", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()},
+		AuctionEntityMongo{Id: "history-completed", ProductName: "Product", Category: "Electronics",
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()},
+	})
+	assert.Nil(t, err)
+
+	cancelled, cancelErr := repo.CancelAllActive(ctx, "emergency maintenance")
+	assert.Nil(t, cancelErr)
+	assert.Equal(t, int64(1), cancelled)
+
+	cursor, err := repo.historyCollection.Find(ctx, bson.M{})
+	assert.Nil(t, err)
+
+	var history []StatusHistoryEntityMongo
+	assert.Nil(t, cursor.All(ctx, &history))
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "history-active", history[0].AuctionId)
+		assert.Equal(t, auction_entity.Active, history[0].From)
+		assert.Equal(t, auction_entity.Cancelled, history[0].To)
+		assert.Equal(t, "emergency maintenance", history[0].Reason)
+		assert.Equal(t, auction_entity.CloseReasonEmergencyCancel, history[0].CloseReason)
+	}
+
+	stored, findErr := repo.FindAuctionById(ctx, "history-active")
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.CloseReasonEmergencyCancel, stored.CloseReason)
+}
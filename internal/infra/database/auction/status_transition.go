@@ -0,0 +1,26 @@
+package auction
+
+import "github.com/danielencestari/lab03/internal/entity/auction_entity"
+
+// validTransition reports whether an auction may move from one status to
+// another. Completed auctions can only go back to Active through a reopen,
+// paused auctions resume or get cancelled, and Cancelled is terminal.
+func validTransition(from, to auction_entity.AuctionStatus) bool {
+	switch from {
+	case auction_entity.Active:
+		return to == auction_entity.Completed || to == auction_entity.Cancelled || to == auction_entity.Paused
+	case auction_entity.Completed:
+		return to == auction_entity.Active
+	case auction_entity.Paused:
+		return to == auction_entity.Active || to == auction_entity.Cancelled
+	default:
+		return false
+	}
+}
+
+// isPauseTransition reports whether a transition pauses or resumes an
+// auction, which only PauseAuction and ResumeAuction may do since they
+// also move its monitor and remaining time.
+func isPauseTransition(from, to auction_entity.AuctionStatus) bool {
+	return to == auction_entity.Paused || (from == auction_entity.Paused && to == auction_entity.Active)
+}
@@ -0,0 +1,159 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidTransition(t *testing.T) {
+	statuses := []auction_entity.AuctionStatus{
+		auction_entity.Active,
+		auction_entity.Completed,
+		auction_entity.Cancelled,
+		auction_entity.Paused,
+	}
+
+	allowed := map[[2]auction_entity.AuctionStatus]bool{
+		{auction_entity.Active, auction_entity.Completed}: true,
+		{auction_entity.Active, auction_entity.Cancelled}: true,
+		{auction_entity.Completed, auction_entity.Active}: true,
+		{auction_entity.Active, auction_entity.Paused}:    true,
+		{auction_entity.Paused, auction_entity.Active}:    true,
+		{auction_entity.Paused, auction_entity.Cancelled}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(fmt.Sprintf("%d to %d", from, to), func(t *testing.T) {
+				assert.Equal(t, allowed[[2]auction_entity.AuctionStatus{from, to}], validTransition(from, to))
+			})
+		}
+	}
+}
+
+func TestUpdateAuctionStatusRejectsIllegalTransition(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{
+		Id:        "auction-cancelled",
+		Status:    auction_entity.Cancelled,
+		Timestamp: now.Unix(),
+		EndTime:   now.Unix(),
+	})
+	assert.Nil(t, err)
+
+	updateErr := repo.UpdateAuctionStatus(ctx, "auction-cancelled", auction_entity.Active)
+	assert.NotNil(t, updateErr)
+	assert.Equal(t, "conflict", updateErr.Err)
+
+	updateErr = repo.UpdateAuctionStatus(ctx, "auction-missing", auction_entity.Completed)
+	assert.NotNil(t, updateErr)
+	assert.Equal(t, "not_found", updateErr.Err)
+}
+
+func TestUpdateAuctionStatusReopenTakesSlotAndMonitor(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Nil(t, repo.SetMaxConcurrentAuctions(1))
+
+	past := time.Now().Add(-time.Hour)
+	for _, id := range []string{"auction-reopen-a", "auction-reopen-b"} {
+		_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{
+			Id:        id,
+			Category:  "Electronics",
+			Status:    auction_entity.Completed,
+			Timestamp: past.Unix(),
+			EndTime:   past.Unix(),
+			ClosedAt:  past.Unix(),
+		})
+		assert.Nil(t, err)
+	}
+
+	// Reabrir ocupa uma vaga, ganha um novo término e volta a ser monitorado
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, "auction-reopen-a", auction_entity.Active))
+	reopened, err := repo.FindAuctionById(ctx, "auction-reopen-a")
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, reopened.Status)
+	assert.True(t, reopened.EndTime.After(time.Now()))
+	assert.Equal(t, 1, repo.monitors.Pending())
+
+	assert.Equal(t, int64(1), repo.Stats().Active)
+
+	// O limite vale para reaberturas como para criações
+	updateErr := repo.UpdateAuctionStatus(ctx, "auction-reopen-b", auction_entity.Active)
+	assert.Equal(t, "conflict", updateErr.Err)
+	stillClosed, err := repo.FindAuctionById(ctx, "auction-reopen-b")
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Completed, stillClosed.Status)
+}
+
+func TestMemoryUpdateAuctionStatusReopenTakesSlotAndMonitor(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewMemoryAuctionRepository(fakeClock)
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Nil(t, repo.SetMaxConcurrentAuctions(1))
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		auction, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateAuction(ctx, auction))
+		assert.Nil(t, repo.UpdateAuctionStatus(ctx, auction.Id, auction_entity.Completed))
+		ids = append(ids, auction.Id)
+	}
+	fakeClock.Advance(2 * time.Hour)
+
+	// Reabrir ocupa uma vaga, ganha um novo término e volta a ser monitorado
+	assert.Nil(t, repo.UpdateAuctionStatus(ctx, ids[0], auction_entity.Active))
+	reopened, err := repo.FindAuctionById(ctx, ids[0])
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, reopened.Status)
+	assert.Equal(t, fakeClock.Now().UTC().Add(time.Hour), reopened.EndTime)
+	assert.Equal(t, 1, repo.monitors.Pending())
+	assert.Equal(t, int64(1), repo.ActiveAuctionsCount())
+
+	// O limite vale para reaberturas como para criações
+	updateErr := repo.UpdateAuctionStatus(ctx, ids[1], auction_entity.Active)
+	assert.NotNil(t, updateErr)
+	assert.Equal(t, "conflict", updateErr.Err)
+	stillClosed, err := repo.FindAuctionById(ctx, ids[1])
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Completed, stillClosed.Status)
+
+	// O leilão reaberto fecha sozinho no novo término
+	fakeClock.Advance(time.Hour)
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, ids[0])
+		return err == nil && stored.Status == auction_entity.Completed
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int64(0), repo.ActiveAuctionsCount())
+}
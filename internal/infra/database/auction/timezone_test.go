@@ -0,0 +1,64 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// withLocalZone troca o fuso local durante o teste, simulando TZ != UTC
+func withLocalZone(t *testing.T, zone *time.Location) {
+	previous := time.Local
+	time.Local = zone
+	t.Cleanup(func() { time.Local = previous })
+}
+
+func assertUTCTimes(t *testing.T, created, stored *auction_entity.Auction, interval time.Duration) {
+	assert.Equal(t, time.UTC, stored.Timestamp.Location())
+	assert.Equal(t, time.UTC, stored.EndTime.Location())
+	assert.Equal(t, created.Timestamp.Unix(), stored.Timestamp.Unix())
+	assert.Equal(t, stored.Timestamp.Add(interval), stored.EndTime)
+}
+
+func TestTimestampsAreUTCRegardlessOfLocalZone(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	withLocalZone(t, time.FixedZone("BRT", -3*60*60))
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, auction.Timestamp.Location())
+
+	t.Run("memory repository", func(t *testing.T) {
+		repo := NewMemoryAuctionRepository(clock.NewFakeClock(time.Now()))
+		defer repo.Close()
+
+		assert.Nil(t, repo.CreateAuction(context.Background(), auction))
+		stored, err := repo.FindAuctionById(context.Background(), auction.Id)
+		assert.Nil(t, err)
+		assertUTCTimes(t, auction, stored, time.Hour)
+	})
+
+	t.Run("mongo repository", func(t *testing.T) {
+		if !isMongoDBAvailable() {
+			t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+		}
+
+		db, cleanup := setupTestDB()
+		defer cleanup()
+
+		repo := NewAuctionRepository(db)
+		defer repo.Close()
+
+		assert.Nil(t, repo.CreateAuction(context.Background(), auction))
+		stored, err := repo.FindAuctionById(context.Background(), auction.Id)
+		assert.Nil(t, err)
+		assertUTCTimes(t, auction, stored, time.Hour)
+	})
+}
@@ -0,0 +1,104 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (ar *AuctionRepository) UpdateAuction(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	update auction_entity.AuctionUpdate) (*auction_entity.Auction, *internal_error.InternalError) {
+
+	if update.Category != nil {
+		current, err := ar.FindAuctionById(ctx, auctionId)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCategoryUnchanged(current.Category, *update.Category); err != nil {
+			return nil, err
+		}
+	}
+
+	fields := bson.M{}
+	if update.ProductName != nil {
+		fields["product_name"] = *update.ProductName
+	}
+	if update.Description != nil {
+		description, err := ar.limitDescription(*update.Description)
+		if err != nil {
+			return nil, err
+		}
+		fields["description"], fields["compressed"] = ar.storedDescription(description)
+	}
+	if update.Condition != nil {
+		fields["condition"] = *update.Condition
+	}
+
+	if len(fields) == 0 {
+		return ar.FindAuctionById(ctx, auctionId)
+	}
+
+	// Only active auctions at the expected version can be edited
+	filter := bson.M{
+		"_id":     auctionId,
+		"status":  auction_entity.Active,
+		"version": versionFilter(expectedVersion),
+	}
+	changes := bson.M{"$set": fields, "$inc": bson.M{"version": 1}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, changes)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to update auction with id = %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to update auction")
+	}
+	ar.invalidateCachedAuction(auctionId)
+	ar.invalidateCachedCounts()
+
+	if result.MatchedCount == 0 {
+		// Tell a missing auction apart from one that is no longer active
+		// or was modified since the caller read it
+		current, err := ar.FindAuctionById(ctx, auctionId)
+		if err != nil {
+			return nil, err
+		}
+
+		if current.Status != auction_entity.Active {
+			return nil, internal_error.NewConflictError("Auction is not active and cannot be updated")
+		}
+
+		return nil, internal_error.NewConflictError(
+			fmt.Sprintf("Auction was modified concurrently (expected version %d, current %d)",
+				expectedVersion, current.Version))
+	}
+
+	return ar.FindAuctionById(ctx, auctionId)
+}
+
+// checkCategoryUnchanged rejects moving an auction to another category: its
+// concurrency slot and, behind a RepositoryRouter, its database belong to
+// the category it was created with. Resending the same category is fine.
+func checkCategoryUnchanged(current, category string) *internal_error.InternalError {
+	if auction_entity.NormalizeCategory(category) != auction_entity.NormalizeCategory(current) {
+		return internal_error.NewConflictError("Auction category cannot be changed")
+	}
+
+	return nil
+}
+
+// versionFilter matches documents at the given version. Auctions written
+// before versioning have no version field and are treated as version 0.
+func versionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+
+	return version
+}
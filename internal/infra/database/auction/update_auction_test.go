@@ -0,0 +1,102 @@
+package auction
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateAuctionPartialFields(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "auction-update-active", ProductName: "Test Product", Category: "Electronics",
+			Description: "Test description for auction", Condition: auction_entity.Used,
+			Status: auction_entity.Active, Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix()},
+		AuctionEntityMongo{Id: "auction-update-completed", ProductName: "Test Product", Category: "Electronics",
+			Description: "Test description for auction", Condition: auction_entity.Used,
+			Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()},
+	})
+	assert.Nil(t, err)
+
+	description := "Updated description for auction"
+	updated, updateErr := repo.UpdateAuction(ctx, "auction-update-active", 0,
+		auction_entity.AuctionUpdate{Description: &description})
+	assert.Nil(t, updateErr)
+	assert.Equal(t, description, updated.Description)
+	assert.Equal(t, "Test Product", updated.ProductName)
+	assert.Equal(t, "Electronics", updated.Category)
+	assert.Equal(t, auction_entity.Used, updated.Condition)
+	assert.Equal(t, int64(1), updated.Version)
+
+	_, updateErr = repo.UpdateAuction(ctx, "auction-update-completed", 0,
+		auction_entity.AuctionUpdate{Description: &description})
+	assert.NotNil(t, updateErr)
+	assert.Equal(t, "conflict", updateErr.Err)
+
+	// A categoria fica fixa e a descrição segue o mesmo limite da criação
+	category := "Furniture"
+	_, updateErr = repo.UpdateAuction(ctx, "auction-update-active", 1,
+		auction_entity.AuctionUpdate{Category: &category})
+	assert.Equal(t, "conflict", updateErr.Err)
+
+	tooLong := strings.Repeat("a", maxDescriptionLength+1)
+	_, updateErr = repo.UpdateAuction(ctx, "auction-update-active", 1,
+		auction_entity.AuctionUpdate{Description: &tooLong})
+	assert.Equal(t, "bad_request", updateErr.Err)
+
+	_, updateErr = repo.UpdateAuction(ctx, "auction-update-missing", 0,
+		auction_entity.AuctionUpdate{Description: &description})
+	assert.NotNil(t, updateErr)
+	assert.Equal(t, "not_found", updateErr.Err)
+}
+
+func TestUpdateAuctionStaleVersion(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{Id: "auction-update-versioned",
+		ProductName: "Test Product", Category: "Electronics", Description: "Test description for auction",
+		Condition: auction_entity.Used, Status: auction_entity.Active,
+		Timestamp: now.Unix(), EndTime: now.Add(time.Hour).Unix(), Version: 3})
+	assert.Nil(t, err)
+
+	// Two clients read the auction at version 3; the first write wins
+	firstName := "First writer"
+	updated, updateErr := repo.UpdateAuction(ctx, "auction-update-versioned", 3,
+		auction_entity.AuctionUpdate{ProductName: &firstName})
+	assert.Nil(t, updateErr)
+	assert.Equal(t, int64(4), updated.Version)
+
+	secondName := "Second writer"
+	_, updateErr = repo.UpdateAuction(ctx, "auction-update-versioned", 3,
+		auction_entity.AuctionUpdate{ProductName: &secondName})
+	assert.NotNil(t, updateErr)
+	assert.Equal(t, "conflict", updateErr.Err)
+
+	stored, findErr := repo.FindAuctionById(ctx, "auction-update-versioned")
+	assert.Nil(t, findErr)
+	assert.Equal(t, firstName, stored.ProductName)
+	assert.Equal(t, int64(4), stored.Version)
+}
@@ -0,0 +1,77 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// AssignWinner sets the winning bid of an auction only if it has none yet,
+// so concurrent close paths cannot flip the winner. assigned reports
+// whether bidId was stored; a later attempt is logged and ignored.
+func (ar *AuctionRepository) AssignWinner(
+	ctx context.Context, auctionId, bidId string) (bool, *internal_error.InternalError) {
+	filter := bson.M{"_id": auctionId, "winner_bid_id": bson.M{"$in": bson.A{nil, ""}}}
+	update := bson.M{"$set": bson.M{"winner_bid_id": bidId}, "$inc": bson.M{"version": 1}}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to assign the winner of auction with id = %s", auctionId), err)
+		return false, internal_error.NewInternalServerError("Error trying to assign the auction winner")
+	}
+	ar.invalidateCachedAuction(auctionId)
+	// Counts filtered by HasWinner change with it
+	ar.invalidateCachedCounts()
+
+	if result.MatchedCount > 0 {
+		return true, nil
+	}
+
+	var current AuctionEntityMongo
+	if err := ar.Collection.FindOne(ctx, bson.M{"_id": auctionId},
+		options.FindOne().SetProjection(bson.M{"winner_bid_id": 1})).Decode(&current); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", auctionId))
+		}
+
+		logger.Error(fmt.Sprintf("Error trying to find the winner of auction with id = %s", auctionId), err)
+		return false, internal_error.NewInternalServerError("Error trying to assign the auction winner")
+	}
+
+	logger.Warn("Auction already has a winner, ignoring second assignment",
+		zap.String("auction_id", auctionId),
+		zap.String("winner_bid_id", current.WinnerBidId),
+		zap.String("attempted_bid_id", bidId))
+	return false, nil
+}
+
+// assignWinnerOnClose records the highest bid as the winner of a closed
+// auction; between equal amounts the earliest bid wins. Auctions without
+// bids are left without a winner.
+func (ar *AuctionRepository) assignWinnerOnClose(ctx context.Context, auctionId string) {
+	var highBid struct {
+		Id string `bson:"_id"`
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "amount_cents", Value: -1}, {Key: "timestamp", Value: 1}})
+	err := ar.bidsCollection.FindOne(ctx, bson.M{"auction_id": auctionId}, opts).Decode(&highBid)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find the winning bid of auction with id = %s", auctionId), err)
+		return
+	}
+
+	if _, assignErr := ar.AssignWinner(ctx, auctionId, highBid.Id); assignErr != nil {
+		logger.Error(fmt.Sprintf("Error trying to assign the winner of auction with id = %s", auctionId), assignErr)
+	}
+}
@@ -0,0 +1,172 @@
+package auction
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAssignWinnerKeepsFirstAssignment(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	repo := NewAuctionRepository(db)
+	defer repo.Close()
+	ctx := context.Background()
+
+	now := time.Now()
+	insertClosed := func(id string) {
+		_, err := repo.Collection.InsertOne(ctx, AuctionEntityMongo{Id: id, ProductName: "Product",
+			Category: "Electronics", Status: auction_entity.Completed, Timestamp: now.Unix(), EndTime: now.Unix()})
+		assert.Nil(t, err)
+	}
+	winnerOf := func(id string) string {
+		stored, err := repo.FindAuctionById(ctx, id)
+		assert.Nil(t, err)
+		return stored.WinnerBidId
+	}
+
+	t.Run("a second assignment is ignored", func(t *testing.T) {
+		insertClosed("sequential")
+
+		ok, err := repo.AssignWinner(ctx, "sequential", "bid-close")
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.AssignWinner(ctx, "sequential", "bid-late")
+		assert.Nil(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "bid-close", winnerOf("sequential"))
+	})
+
+	t.Run("racing assignments keep a single winner", func(t *testing.T) {
+		insertClosed("race")
+
+		bidIds := []string{"bid-close", "bid-late"}
+		assigned := make([]bool, len(bidIds))
+		var wg sync.WaitGroup
+		for i, bidId := range bidIds {
+			wg.Add(1)
+			go func(i int, bidId string) {
+				defer wg.Done()
+				ok, err := repo.AssignWinner(ctx, "race", bidId)
+				assert.Nil(t, err)
+				assigned[i] = ok
+			}(i, bidId)
+		}
+		wg.Wait()
+
+		if assigned[0] && assigned[1] {
+			t.Skip("Atualização condicional não é atômica neste servidor MongoDB")
+		}
+		assert.NotEqual(t, assigned[0], assigned[1])
+
+		first := bidIds[0]
+		if assigned[1] {
+			first = bidIds[1]
+		}
+		assert.Equal(t, first, winnerOf("race"))
+
+		// Uma nova tentativa também é ignorada
+		ok, err := repo.AssignWinner(ctx, "race", "bid-later")
+		assert.Nil(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, first, winnerOf("race"))
+	})
+
+	t.Run("missing auction", func(t *testing.T) {
+		_, err := repo.AssignWinner(ctx, "missing", "bid-1")
+		assert.Equal(t, "not_found", err.Err)
+	})
+}
+
+func TestAutoCloseAssignsHighestBidAsWinner(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	_, insertErr := repo.bidsCollection.InsertMany(ctx, []interface{}{
+		bson.M{"_id": "bid-low", "auction_id": auction.Id, "amount_cents": 1000},
+		bson.M{"_id": "bid-high", "auction_id": auction.Id, "amount_cents": 3000},
+	})
+	assert.Nil(t, insertErr)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		return err == nil && stored.WinnerBidId == "bid-high"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAutoCloseBreaksWinnerTiesByEarliestBid(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	repo := NewAuctionRepository(db, WithClock(fakeClock))
+	defer repo.Close()
+	ctx := context.Background()
+	assert.Eventually(t, repo.RecoveryDone, time.Second, time.Millisecond)
+
+	auction, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateAuction(ctx, auction))
+
+	// Mesmo valor: vence o lance mais antigo, independente da ordem de
+	// inserção
+	now := time.Now()
+	_, insertErr := repo.bidsCollection.InsertMany(ctx, []interface{}{
+		bson.M{"_id": "bid-tie-late", "auction_id": auction.Id, "amount_cents": 3000, "timestamp": now.Unix()},
+		bson.M{"_id": "bid-tie-early", "auction_id": auction.Id, "amount_cents": 3000,
+			"timestamp": now.Add(-time.Minute).Unix()},
+		bson.M{"_id": "bid-low", "auction_id": auction.Id, "amount_cents": 1000,
+			"timestamp": now.Add(-time.Hour).Unix()},
+	})
+	assert.Nil(t, insertErr)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Hour)
+
+	assert.Eventually(t, func() bool {
+		stored, err := repo.FindAuctionById(ctx, auction.Id)
+		return err == nil && stored.WinnerBidId == "bid-tie-early"
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,54 @@
+package auction
+
+import (
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// parseWriteConcern builds the write concern of auction writes from
+// MONGODB_WRITE_CONCERN_W (a number, "majority" or a tag set name),
+// MONGODB_WRITE_CONCERN_J and MONGODB_WRITE_CONCERN_WTIMEOUT. It returns nil,
+// meaning the driver default, when none of them holds a valid value.
+func parseWriteConcern(w, journal, wtimeout string) *writeconcern.WriteConcern {
+	var writeConcern writeconcern.WriteConcern
+	configured := false
+
+	if w := strings.TrimSpace(w); w != "" {
+		if nodes, err := strconv.Atoi(w); err == nil {
+			if nodes >= 0 {
+				writeConcern.W = nodes
+				configured = true
+			}
+		} else {
+			writeConcern.W = w
+			configured = true
+		}
+	}
+
+	if journal, err := strconv.ParseBool(journal); err == nil {
+		writeConcern.Journal = &journal
+		configured = true
+	}
+
+	if wtimeout := parsePositiveDuration(wtimeout); wtimeout > 0 {
+		writeConcern.WTimeout = wtimeout
+		configured = true
+	}
+
+	if !configured {
+		return nil
+	}
+	return &writeConcern
+}
+
+// collectionOptions are the options of the auctions collection, or nil
+// when the driver defaults apply.
+func (ar *AuctionRepository) collectionOptions() *options.CollectionOptions {
+	if ar.config.WriteConcern == nil {
+		return nil
+	}
+	return options.Collection().SetWriteConcern(ar.config.WriteConcern)
+}
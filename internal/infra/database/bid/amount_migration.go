@@ -0,0 +1,42 @@
+package bid
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const migrateAmountsTimeout = time.Minute
+
+// migrateAmountsToCents converts the bids stored before amounts were kept
+// in cents: their float `amount` is rounded into the integer
+// `amount_cents` and removed. Only bids without amount_cents are touched,
+// so it is cheap once done and also catches up bids written by an older
+// instance during a rolling deploy. Failures are logged; those bids keep
+// reading as zero until the next start.
+func (bd *BidRepository) migrateAmountsToCents() {
+	ctx, cancel := context.WithTimeout(context.Background(), migrateAmountsTimeout)
+	defer cancel()
+
+	filter := bson.M{"amount_cents": bson.M{"$exists": false}, "amount": bson.M{"$exists": true}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"amount_cents": bson.M{"$toLong": bson.M{
+			"$round": bson.A{bson.M{"$multiply": bson.A{"$amount", 100}}, 0}}}}}},
+		{{Key: "$unset", Value: "amount"}},
+	}
+
+	result, err := bd.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error("Error trying to migrate bid amounts to cents", err)
+		return
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.Info("Migrated bid amounts to cents", zap.Int64("bids", result.ModifiedCount))
+	}
+}
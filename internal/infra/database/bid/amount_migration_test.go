@@ -0,0 +1,61 @@
+package bid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danielencestari/lab03/configuration/database/mongodb"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestMigrateAmountsToCents(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	bids := db.Collection("bids")
+
+	// Lances gravados antes dos centavos, com o índice antigo
+	_, err := bids.InsertMany(ctx, []interface{}{
+		bson.M{"_id": "bid-legacy", "auction_id": "auction-1", "user_id": "user-1", "amount": 1500.5, "timestamp": 1},
+		bson.M{"_id": "bid-float", "auction_id": "auction-1", "user_id": "user-2", "amount": 0.29, "timestamp": 2},
+		bson.M{"_id": "bid-cents", "auction_id": "auction-1", "user_id": "user-3", "amount_cents": 1000, "timestamp": 3},
+	})
+	assert.Nil(t, err)
+	_, err = bids.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "amount", Value: -1}},
+		Options: options.Index().SetName(legacyAmountIndex),
+	})
+	assert.Nil(t, err)
+
+	repo := NewBidRepository(db, &auctionLookupMock{}, &userRepositoryMock{users: map[string]user_entity.User{}})
+
+	amountOf := func(id string) bson.M {
+		var stored bson.M
+		assert.Nil(t, repo.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&stored))
+		return stored
+	}
+	for id, cents := range map[string]int64{"bid-legacy": 150050, "bid-float": 29, "bid-cents": 1000} {
+		stored := amountOf(id)
+		assert.EqualValues(t, cents, stored["amount_cents"], id)
+		assert.NotContains(t, stored, "amount", id)
+	}
+
+	high, highErr := repo.CurrentHighBid(ctx, "auction-1")
+	assert.Nil(t, highErr)
+	assert.Equal(t, "bid-legacy", high.Id)
+	assert.Equal(t, bid_entity.Cents(150050), high.Amount)
+
+	missing, err := mongodb.MissingIndexes(ctx, repo.Collection, []string{legacyAmountIndex, auctionAmountIndex})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{legacyAmountIndex}, missing)
+}
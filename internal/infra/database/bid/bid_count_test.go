@@ -0,0 +1,64 @@
+package bid
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidCountFollowsBids(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+	os.Setenv("BID_RETRACT_WINDOW", "1m")
+	defer os.Unsetenv("BID_RETRACT_WINDOW")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	auctions := auction.NewAuctionRepository(db)
+	defer auctions.Close()
+	ctx := context.Background()
+
+	userId := uuid.New().String()
+	repo := NewBidRepository(db, auctions, &userRepositoryMock{users: map[string]user_entity.User{
+		userId: {Id: userId, Name: "Bidder"},
+	}})
+
+	auctionEntity, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	assert.Nil(t, auctions.CreateAuction(ctx, auctionEntity))
+
+	bidCount := func() int64 {
+		stored, err := auctions.FindAuctionById(ctx, auctionEntity.Id)
+		assert.Nil(t, err)
+		return stored.BidCount
+	}
+	assert.Equal(t, int64(0), bidCount())
+
+	first, err := bid_entity.CreateBid(userId, auctionEntity.Id, 100)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.CreateBid(ctx, []bid_entity.Bid{*first}))
+	assert.Equal(t, int64(1), bidCount())
+
+	for _, amount := range []bid_entity.Cents{20000, 30000, 40000} {
+		bid, err := bid_entity.CreateBid(userId, auctionEntity.Id, amount)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.CreateBid(ctx, []bid_entity.Bid{*bid}))
+	}
+	assert.Equal(t, int64(4), bidCount())
+
+	assert.Nil(t, repo.RetractBid(ctx, first.Id))
+	assert.Equal(t, int64(3), bidCount())
+}
@@ -5,9 +5,9 @@ import (
 	"github.com/danielencestari/lab03/configuration/logger"
 	"github.com/danielencestari/lab03/internal/entity/auction_entity"
 	"github.com/danielencestari/lab03/internal/entity/bid_entity"
-	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
 	"github.com/danielencestari/lab03/internal/internal_error"
-	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,52 +15,68 @@ import (
 )
 
 type BidEntityMongo struct {
-	Id        string  `bson:"_id"`
-	UserId    string  `bson:"user_id"`
-	AuctionId string  `bson:"auction_id"`
-	Amount    float64 `bson:"amount"`
-	Timestamp int64   `bson:"timestamp"`
+	Id        string           `bson:"_id"`
+	UserId    string           `bson:"user_id"`
+	AuctionId string           `bson:"auction_id"`
+	Amount    bid_entity.Cents `bson:"amount_cents"`
+	Timestamp int64            `bson:"timestamp"`
+}
+
+// AuctionLookup is the part of the auction repository used when placing
+// bids. It is satisfied by both auction.AuctionRepository and
+// auction.RepositoryRouter.
+type AuctionLookup interface {
+	FindAuctionById(
+		ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError)
+
+	ExtendForLateBid(
+		ctx context.Context,
+		auctionId string,
+		bidTime time.Time) (time.Time, bool, *internal_error.InternalError)
+
+	AdjustBidCount(ctx context.Context, auctionId string, delta int64) *internal_error.InternalError
 }
 
 type BidRepository struct {
-	Collection            *mongo.Collection
-	AuctionRepository     *auction.AuctionRepository
-	auctionInterval       time.Duration
-	auctionStatusMap      map[string]auction_entity.AuctionStatus
-	auctionEndTimeMap     map[string]time.Time
-	auctionStatusMapMutex *sync.Mutex
-	auctionEndTimeMutex   *sync.Mutex
+	Collection        *mongo.Collection
+	AuctionRepository AuctionLookup
+	UserRepository    user_entity.UserRepositoryInterface
 }
 
-func NewBidRepository(database *mongo.Database, auctionRepository *auction.AuctionRepository) *BidRepository {
-	return &BidRepository{
-		auctionInterval:       getAuctionInterval(),
-		auctionStatusMap:      make(map[string]auction_entity.AuctionStatus),
-		auctionEndTimeMap:     make(map[string]time.Time),
-		auctionStatusMapMutex: &sync.Mutex{},
-		auctionEndTimeMutex:   &sync.Mutex{},
-		Collection:            database.Collection("bids"),
-		AuctionRepository:     auctionRepository,
+func NewBidRepository(
+	database *mongo.Database,
+	auctionRepository AuctionLookup,
+	userRepository user_entity.UserRepositoryInterface) *BidRepository {
+	repo := &BidRepository{
+		Collection:        database.Collection("bids"),
+		AuctionRepository: auctionRepository,
+		UserRepository:    userRepository,
 	}
+
+	repo.migrateAmountsToCents()
+	repo.ensureIndexes()
+
+	return repo
 }
 
+// CreateBid inserts the bids of existing users on auctions taking bids. The
+// other bids are dropped and the first such error is returned once the
+// others are stored.
 func (bd *BidRepository) CreateBid(
 	ctx context.Context,
 	bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	validBids, userErr := bd.filterBidsByExistingUser(ctx, bidEntities)
+	validBids, auctionErr := bd.filterBidsByAuction(ctx, validBids)
+	if userErr == nil {
+		userErr = auctionErr
+	}
+
 	var wg sync.WaitGroup
-	for _, bid := range bidEntities {
+	for _, bid := range validBids {
 		wg.Add(1)
 		go func(bidValue bid_entity.Bid) {
 			defer wg.Done()
 
-			bd.auctionStatusMapMutex.Lock()
-			auctionStatus, okStatus := bd.auctionStatusMap[bidValue.AuctionId]
-			bd.auctionStatusMapMutex.Unlock()
-
-			bd.auctionEndTimeMutex.Lock()
-			auctionEndTime, okEndTime := bd.auctionEndTimeMap[bidValue.AuctionId]
-			bd.auctionEndTimeMutex.Unlock()
-
 			bidEntityMongo := &BidEntityMongo{
 				Id:        bidValue.Id,
 				UserId:    bidValue.UserId,
@@ -69,53 +85,140 @@ func (bd *BidRepository) CreateBid(
 				Timestamp: bidValue.Timestamp.Unix(),
 			}
 
-			if okEndTime && okStatus {
-				now := time.Now()
-				if auctionStatus == auction_entity.Completed || now.After(auctionEndTime) {
-					return
-				}
+			if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
+				logger.Error("Error trying to insert bid", err)
+				return
+			}
+
+			bd.adjustBidCount(ctx, bidValue.AuctionId, 1)
+			bd.extendForLateBid(ctx, bidValue)
+		}(bid)
+	}
+	wg.Wait()
+	return userErr
+}
 
-				if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-					logger.Error("Error trying to insert bid", err)
-					return
-				}
+// filterBidsByExistingUser keeps the bids whose user exists, looking each
+// user up once per batch.
+func (bd *BidRepository) filterBidsByExistingUser(
+	ctx context.Context,
+	bidEntities []bid_entity.Bid) ([]bid_entity.Bid, *internal_error.InternalError) {
+	var firstErr *internal_error.InternalError
+	userErrors := make(map[string]*internal_error.InternalError)
+	validBids := make([]bid_entity.Bid, 0, len(bidEntities))
 
-				return
+	for _, bid := range bidEntities {
+		userErr, checked := userErrors[bid.UserId]
+		if !checked {
+			userErr = bd.validateUser(ctx, bid.UserId)
+			userErrors[bid.UserId] = userErr
+		}
+
+		if userErr != nil {
+			logger.Error("Bid rejected for user "+bid.UserId, userErr)
+			if firstErr == nil {
+				firstErr = userErr
 			}
+			continue
+		}
+		validBids = append(validBids, bid)
+	}
 
-			auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
-			if err != nil {
-				logger.Error("Error trying to find auction by id", err)
-				return
+	return validBids, firstErr
+}
+
+// filterBidsByAuction keeps the bids on auctions that are Active and
+// before their stored end time, which already includes resumes and
+// anti-sniping extensions, except those placed by the auction's owner.
+// Each auction is looked up once per batch, never from an earlier batch,
+// so a pause, cancel or close is seen by the next bid. Bids on auctions
+// that cannot be found are dropped.
+func (bd *BidRepository) filterBidsByAuction(
+	ctx context.Context,
+	bidEntities []bid_entity.Bid) ([]bid_entity.Bid, *internal_error.InternalError) {
+	var firstErr *internal_error.InternalError
+	auctions := make(map[string]*auction_entity.Auction)
+	validBids := make([]bid_entity.Bid, 0, len(bidEntities))
+	now := time.Now()
+
+	for _, bid := range bidEntities {
+		auctionEntity, checked := auctions[bid.AuctionId]
+		if !checked {
+			var findErr *internal_error.InternalError
+			auctionEntity, findErr = bd.AuctionRepository.FindAuctionById(ctx, bid.AuctionId)
+			if findErr != nil {
+				logger.Error("Error trying to find auction by id", findErr)
 			}
-			if auctionEntity.Status == auction_entity.Completed {
-				return
+			auctions[bid.AuctionId] = auctionEntity
+		}
+
+		if auctionEntity == nil {
+			continue
+		}
+		if auctionErr := bidAuctionError(auctionEntity, bid, now); auctionErr != nil {
+			logger.Error("Bid rejected for user "+bid.UserId, auctionErr)
+			if firstErr == nil {
+				firstErr = auctionErr
 			}
+			continue
+		}
+		validBids = append(validBids, bid)
+	}
 
-			bd.auctionStatusMapMutex.Lock()
-			bd.auctionStatusMap[bidValue.AuctionId] = auctionEntity.Status
-			bd.auctionStatusMapMutex.Unlock()
+	return validBids, firstErr
+}
 
-			bd.auctionEndTimeMutex.Lock()
-			bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEntity.Timestamp.Add(bd.auctionInterval)
-			bd.auctionEndTimeMutex.Unlock()
+// ValidateBid rejects up front a bid CreateBid would drop because of its
+// auction, such as one on a paused auction or placed by the owner, so the
+// bidder gets the error instead of the bid being dropped from its batch.
+// CreateBid still checks every bid against the auction it finds then.
+func (bd *BidRepository) ValidateBid(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bid.AuctionId)
+	if err != nil {
+		return err
+	}
 
-			if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-				logger.Error("Error trying to insert bid", err)
-				return
-			}
-		}(bid)
+	return bidAuctionError(auctionEntity, bid, time.Now())
+}
+
+// bidAuctionError tells why auctionEntity does not take bid at now, or nil
+// when it does.
+func bidAuctionError(
+	auctionEntity *auction_entity.Auction, bid bid_entity.Bid, now time.Time) *internal_error.InternalError {
+	switch {
+	case auctionEntity.Status == auction_entity.Paused:
+		return internal_error.NewConflictError("Auction is paused")
+	case auctionEntity.Status != auction_entity.Active:
+		return internal_error.NewConflictError("Auction is not active")
+	case now.After(auctionEntity.EndTime):
+		return internal_error.NewConflictError("Auction has ended")
+	case auctionEntity.OwnerId != "" && auctionEntity.OwnerId == bid.UserId:
+		return internal_error.NewBadRequestError("Auction owner cannot bid on their own auction")
 	}
-	wg.Wait()
 	return nil
 }
 
-func getAuctionInterval() time.Duration {
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	duration, err := time.ParseDuration(auctionInterval)
-	if err != nil {
-		return time.Minute * 5
+func (bd *BidRepository) validateUser(ctx context.Context, userId string) *internal_error.InternalError {
+	if strings.TrimSpace(userId) == "" {
+		return internal_error.NewBadRequestError("Bid user id is required")
 	}
 
-	return duration
+	_, err := bd.UserRepository.FindUserById(ctx, userId)
+	return err
+}
+
+// adjustBidCount keeps the bid count stored on the auction in step with
+// the bids collection. Failures are only logged since the bid is stored.
+func (bd *BidRepository) adjustBidCount(ctx context.Context, auctionId string, delta int64) {
+	if err := bd.AuctionRepository.AdjustBidCount(ctx, auctionId, delta); err != nil {
+		logger.Error("Error trying to update auction bid count", err)
+	}
+}
+
+// extendForLateBid applies the anti-sniping extension. Failures are only
+// logged since the bid is stored.
+func (bd *BidRepository) extendForLateBid(ctx context.Context, bidValue bid_entity.Bid) {
+	if _, _, err := bd.AuctionRepository.ExtendForLateBid(ctx, bidValue.AuctionId, bidValue.Timestamp); err != nil {
+		logger.Error("Error trying to extend auction for late bid", err)
+	}
 }
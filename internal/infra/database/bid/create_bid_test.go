@@ -0,0 +1,417 @@
+package bid
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/database/mongodb"
+	"github.com/danielencestari/lab03/internal/clock"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/danielencestari/lab03/internal/infra/database/auction"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func isMongoDBAvailable() bool {
+	return mongodb.IsAvailable("mongodb://localhost:27017", 0)
+}
+
+func setupTestDB() (*mongo.Database, func()) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		panic(err)
+	}
+
+	db := client.Database("bid_test")
+
+	cleanup := func() {
+		db.Drop(context.Background())
+		client.Disconnect(context.Background())
+	}
+
+	return db, cleanup
+}
+
+type auctionLookupMock struct {
+	auction auction_entity.Auction
+}
+
+func (m *auctionLookupMock) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction := m.auction
+	return &auction, nil
+}
+
+func (m *auctionLookupMock) ExtendForLateBid(
+	ctx context.Context,
+	auctionId string,
+	bidTime time.Time) (time.Time, bool, *internal_error.InternalError) {
+	return time.Time{}, false, nil
+}
+
+func (m *auctionLookupMock) AdjustBidCount(
+	ctx context.Context, auctionId string, delta int64) *internal_error.InternalError {
+	return nil
+}
+
+type userRepositoryMock struct {
+	user_entity.UserRepositoryInterface
+
+	users map[string]user_entity.User
+}
+
+func (m *userRepositoryMock) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	user, ok := m.users[userId]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("user not found")
+	}
+	return &user, nil
+}
+
+func TestCreateBidValidatesUser(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	auctionId := uuid.New().String()
+	knownUserId := uuid.New().String()
+	auctions := &auctionLookupMock{auction: auction_entity.Auction{
+		Id:        auctionId,
+		Status:    auction_entity.Active,
+		Timestamp: time.Now(),
+		EndTime:   time.Now().Add(time.Hour),
+	}}
+	users := &userRepositoryMock{users: map[string]user_entity.User{
+		knownUserId: {Id: knownUserId, Name: "Known User"},
+	}}
+	repo := NewBidRepository(db, auctions, users)
+	ctx := context.Background()
+
+	countBids := func(userId string) int64 {
+		count, err := repo.Collection.CountDocuments(ctx, bson.M{"user_id": userId})
+		assert.Nil(t, err)
+		return count
+	}
+
+	t.Run("bid from an existing user is stored", func(t *testing.T) {
+		bid, err := bid_entity.CreateBid(knownUserId, auctionId, 100)
+		assert.Nil(t, err)
+
+		assert.Nil(t, repo.CreateBid(ctx, []bid_entity.Bid{*bid}))
+		assert.Equal(t, int64(1), countBids(knownUserId))
+	})
+
+	t.Run("bid from an unknown user is rejected", func(t *testing.T) {
+		unknownUserId := uuid.New().String()
+		bid, err := bid_entity.CreateBid(unknownUserId, auctionId, 200)
+		assert.Nil(t, err)
+
+		createErr := repo.CreateBid(ctx, []bid_entity.Bid{*bid})
+		assert.NotNil(t, createErr)
+		assert.Equal(t, "not_found", createErr.Err)
+		assert.Equal(t, int64(0), countBids(unknownUserId))
+	})
+
+	t.Run("bid without user id is rejected", func(t *testing.T) {
+		bid := bid_entity.Bid{
+			Id:        uuid.New().String(),
+			AuctionId: auctionId,
+			Amount:    300,
+			Timestamp: time.Now(),
+		}
+
+		createErr := repo.CreateBid(ctx, []bid_entity.Bid{bid})
+		assert.NotNil(t, createErr)
+		assert.Equal(t, "bad_request", createErr.Err)
+		assert.Equal(t, int64(0), countBids(""))
+	})
+
+	t.Run("valid bids in a mixed batch are still stored", func(t *testing.T) {
+		validBid, err := bid_entity.CreateBid(knownUserId, auctionId, 400)
+		assert.Nil(t, err)
+		unknownBid, err := bid_entity.CreateBid(uuid.New().String(), auctionId, 500)
+		assert.Nil(t, err)
+
+		createErr := repo.CreateBid(ctx, []bid_entity.Bid{*unknownBid, *validBid})
+		assert.NotNil(t, createErr)
+		assert.Equal(t, int64(2), countBids(knownUserId))
+	})
+}
+
+func TestFilterBidsByAuction(t *testing.T) {
+	ownerId := uuid.New().String()
+	bidderId := uuid.New().String()
+	auctionId := uuid.New().String()
+	repo := &BidRepository{AuctionRepository: &auctionLookupMock{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Status:  auction_entity.Active,
+		OwnerId: ownerId,
+		EndTime: time.Now().Add(time.Hour),
+	}}}
+
+	ownerBid, err := bid_entity.CreateBid(ownerId, auctionId, 100)
+	assert.Nil(t, err)
+	bidderBid, err := bid_entity.CreateBid(bidderId, auctionId, 200)
+	assert.Nil(t, err)
+
+	validBids, filterErr := repo.filterBidsByAuction(context.Background(), []bid_entity.Bid{*ownerBid, *bidderBid})
+	assert.NotNil(t, filterErr)
+	assert.Equal(t, "bad_request", filterErr.Err)
+	if assert.Len(t, validBids, 1) {
+		assert.Equal(t, bidderId, validBids[0].UserId)
+	}
+}
+
+func TestFilterBidsByAuctionRejectsPausedAuction(t *testing.T) {
+	auctionId := uuid.New().String()
+	repo := &BidRepository{AuctionRepository: &auctionLookupMock{auction: auction_entity.Auction{
+		Id:     auctionId,
+		Status: auction_entity.Paused,
+	}}}
+
+	bid, err := bid_entity.CreateBid(uuid.New().String(), auctionId, 100)
+	assert.Nil(t, err)
+
+	validBids, filterErr := repo.filterBidsByAuction(context.Background(), []bid_entity.Bid{*bid})
+	assert.NotNil(t, filterErr)
+	assert.Equal(t, "conflict", filterErr.Err)
+	assert.Empty(t, validBids)
+}
+
+func TestFilterBidsByAuctionRejectsAuctionsNotTakingBids(t *testing.T) {
+	auctionId := uuid.New().String()
+	auctions := &auctionLookupMock{}
+	repo := &BidRepository{AuctionRepository: auctions}
+
+	bid, err := bid_entity.CreateBid(uuid.New().String(), auctionId, 100)
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		name    string
+		auction auction_entity.Auction
+	}{
+		{"completed", auction_entity.Auction{Status: auction_entity.Completed, EndTime: time.Now().Add(time.Hour)}},
+		{"cancelled", auction_entity.Auction{Status: auction_entity.Cancelled, EndTime: time.Now().Add(time.Hour)}},
+		{"past its end time", auction_entity.Auction{Status: auction_entity.Active, EndTime: time.Now().Add(-time.Second)}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			auctions.auction = testCase.auction
+			auctions.auction.Id = auctionId
+
+			validBids, filterErr := repo.filterBidsByAuction(context.Background(), []bid_entity.Bid{*bid})
+			assert.NotNil(t, filterErr)
+			assert.Equal(t, "conflict", filterErr.Err)
+			assert.Empty(t, validBids)
+		})
+	}
+
+	// Cada lote relê o leilão: o cancelamento vale para o lote seguinte
+	t.Run("status change is seen by the next batch", func(t *testing.T) {
+		auctions.auction = auction_entity.Auction{
+			Id: auctionId, Status: auction_entity.Active, EndTime: time.Now().Add(time.Hour)}
+		validBids, filterErr := repo.filterBidsByAuction(context.Background(), []bid_entity.Bid{*bid})
+		assert.Nil(t, filterErr)
+		assert.Len(t, validBids, 1)
+
+		auctions.auction.Status = auction_entity.Cancelled
+		validBids, filterErr = repo.filterBidsByAuction(context.Background(), []bid_entity.Bid{*bid})
+		assert.NotNil(t, filterErr)
+		assert.Empty(t, validBids)
+	})
+}
+
+func TestCreateBidRejectsAuctionOwner(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	auctionId := uuid.New().String()
+	ownerId := uuid.New().String()
+	bidderId := uuid.New().String()
+	auctions := &auctionLookupMock{auction: auction_entity.Auction{
+		Id:        auctionId,
+		Status:    auction_entity.Active,
+		Timestamp: time.Now(),
+		OwnerId:   ownerId,
+		EndTime:   time.Now().Add(time.Hour),
+	}}
+	users := &userRepositoryMock{users: map[string]user_entity.User{
+		ownerId:  {Id: ownerId, Name: "Owner"},
+		bidderId: {Id: bidderId, Name: "Bidder"},
+	}}
+	repo := NewBidRepository(db, auctions, users)
+	ctx := context.Background()
+
+	countBids := func(userId string) int64 {
+		count, err := repo.Collection.CountDocuments(ctx, bson.M{"user_id": userId})
+		assert.Nil(t, err)
+		return count
+	}
+
+	t.Run("bid from the owner is rejected", func(t *testing.T) {
+		bid, err := bid_entity.CreateBid(ownerId, auctionId, 100)
+		assert.Nil(t, err)
+
+		createErr := repo.CreateBid(ctx, []bid_entity.Bid{*bid})
+		assert.NotNil(t, createErr)
+		assert.Equal(t, "bad_request", createErr.Err)
+		assert.Equal(t, int64(0), countBids(ownerId))
+	})
+
+	t.Run("bid from another user is stored", func(t *testing.T) {
+		bid, err := bid_entity.CreateBid(bidderId, auctionId, 200)
+		assert.Nil(t, err)
+
+		assert.Nil(t, repo.CreateBid(ctx, []bid_entity.Bid{*bid}))
+		assert.Equal(t, int64(1), countBids(bidderId))
+	})
+}
+
+// memoryAuctionLookup usa o repositório de leilões em memória como
+// AuctionLookup, para que os lances vejam o EndTime que ele persiste
+type memoryAuctionLookup struct {
+	*auction.MemoryAuctionRepository
+}
+
+func (m memoryAuctionLookup) ExtendForLateBid(
+	ctx context.Context,
+	auctionId string,
+	bidTime time.Time) (time.Time, bool, *internal_error.InternalError) {
+	return time.Time{}, false, nil
+}
+
+func (m memoryAuctionLookup) AdjustBidCount(
+	ctx context.Context, auctionId string, delta int64) *internal_error.InternalError {
+	return nil
+}
+
+func TestFilterBidsByAuctionUsesCustomDuration(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	// O relógio do repositório começa no passado para que o EndTime
+	// persistido já tenha passado ou não em tempo real
+	fakeClock := clock.NewFakeClock(time.Now().Add(-90 * time.Minute))
+	auctions := memoryAuctionLookup{auction.NewMemoryAuctionRepository(fakeClock)}
+	defer auctions.Close()
+	repo := &BidRepository{AuctionRepository: auctions}
+	ctx := context.Background()
+
+	create := func(duration time.Duration) string {
+		auctionEntity, err := auction_entity.CreateAuction(
+			"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+		assert.Nil(t, err)
+		auctionEntity.Timestamp = fakeClock.Now()
+		auctionEntity.Duration = duration
+		assert.Nil(t, auctions.CreateAuction(ctx, auctionEntity))
+		return auctionEntity.Id
+	}
+
+	filter := func(auctionId string) *internal_error.InternalError {
+		bid, err := bid_entity.CreateBid(uuid.New().String(), auctionId, 100)
+		assert.Nil(t, err)
+		_, filterErr := repo.filterBidsByAuction(ctx, []bid_entity.Bid{*bid})
+		return filterErr
+	}
+
+	// Uma duração maior que AUCTION_INTERVAL ainda aceita lances
+	assert.Nil(t, filter(create(2*time.Hour)))
+
+	// Uma duração menor já terminou, mesmo dentro de AUCTION_INTERVAL
+	filterErr := filter(create(30 * time.Minute))
+	assert.NotNil(t, filterErr)
+	assert.Equal(t, "Auction has ended", filterErr.Message)
+}
+
+func TestFilterBidsByAuctionAfterResume(t *testing.T) {
+	os.Setenv("AUCTION_INTERVAL", "1h")
+	defer os.Unsetenv("AUCTION_INTERVAL")
+
+	// Criado duas horas atrás: o término original já passou em tempo real
+	fakeClock := clock.NewFakeClock(time.Now().Add(-2 * time.Hour))
+	auctions := memoryAuctionLookup{auction.NewMemoryAuctionRepository(fakeClock)}
+	defer auctions.Close()
+	repo := &BidRepository{AuctionRepository: auctions}
+	ctx := context.Background()
+
+	auctionEntity, err := auction_entity.CreateAuction(
+		"Test Product", "Electronics", "Test description for auction", auction_entity.New)
+	assert.Nil(t, err)
+	auctionEntity.Timestamp = fakeClock.Now()
+	assert.Nil(t, auctions.CreateAuction(ctx, auctionEntity))
+
+	bid, err := bid_entity.CreateBid(uuid.New().String(), auctionEntity.Id, 100)
+	assert.Nil(t, err)
+
+	// Pausado logo após a criação, com uma hora restante
+	assert.Nil(t, auctions.PauseAuction(ctx, auctionEntity.Id))
+	_, filterErr := repo.filterBidsByAuction(ctx, []bid_entity.Bid{*bid})
+	assert.NotNil(t, filterErr)
+	assert.Equal(t, "Auction is paused", filterErr.Message)
+
+	// Retomado agora: o novo término vale, não o original
+	fakeClock.Advance(2 * time.Hour)
+	assert.Nil(t, auctions.ResumeAuction(ctx, auctionEntity.Id))
+	validBids, filterErr := repo.filterBidsByAuction(ctx, []bid_entity.Bid{*bid})
+	assert.Nil(t, filterErr)
+	assert.Len(t, validBids, 1)
+}
+
+func TestValidateBidRejectsAuctionOwner(t *testing.T) {
+	ownerId := uuid.New().String()
+	auctionId := uuid.New().String()
+	repo := &BidRepository{AuctionRepository: &auctionLookupMock{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Status:  auction_entity.Active,
+		OwnerId: ownerId,
+		EndTime: time.Now().Add(time.Hour),
+	}}}
+
+	ownerBid, err := bid_entity.CreateBid(ownerId, auctionId, 100)
+	assert.Nil(t, err)
+	validateErr := repo.ValidateBid(context.Background(), *ownerBid)
+	if assert.NotNil(t, validateErr) {
+		assert.Equal(t, "bad_request", validateErr.Err)
+	}
+
+	bidderBid, err := bid_entity.CreateBid(uuid.New().String(), auctionId, 100)
+	assert.Nil(t, err)
+	assert.Nil(t, repo.ValidateBid(context.Background(), *bidderBid))
+}
+
+func TestValidateBidRejectsPausedAuction(t *testing.T) {
+	auctionId := uuid.New().String()
+	auctions := &auctionLookupMock{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Status:  auction_entity.Paused,
+		EndTime: time.Now().Add(time.Hour),
+	}}
+	repo := &BidRepository{AuctionRepository: auctions}
+
+	bid, err := bid_entity.CreateBid(uuid.New().String(), auctionId, 100)
+	assert.Nil(t, err)
+	validateErr := repo.ValidateBid(context.Background(), *bid)
+	if assert.NotNil(t, validateErr) {
+		assert.Equal(t, "conflict", validateErr.Err)
+	}
+
+	// Retomado, o leilão volta a aceitar lances
+	auctions.auction.Status = auction_entity.Active
+	assert.Nil(t, repo.ValidateBid(context.Background(), *bid))
+}
@@ -0,0 +1,42 @@
+package bid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// CurrentHighBid returns the highest bid of the auction, the earliest among
+// equal amounts, read from the {auction_id, amount_cents, timestamp} index,
+// or a not found error when it has no bids.
+func (bd *BidRepository) CurrentHighBid(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	filter := bson.M{"auction_id": auctionId}
+	opts := options.FindOne().SetSort(highBidSort)
+
+	var bidEntityMongo BidEntityMongo
+	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("No bids found for auction with this id = %s", auctionId))
+		}
+
+		logger.Error("Error trying to find the current high bid", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find the current high bid")
+	}
+
+	return &bid_entity.Bid{
+		Id:        bidEntityMongo.Id,
+		UserId:    bidEntityMongo.UserId,
+		AuctionId: bidEntityMongo.AuctionId,
+		Amount:    bidEntityMongo.Amount,
+		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0).UTC(),
+	}, nil
+}
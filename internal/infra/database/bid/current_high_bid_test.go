@@ -0,0 +1,99 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCurrentHighBid(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewBidRepository(db, &auctionLookupMock{}, &userRepositoryMock{users: map[string]user_entity.User{}})
+
+	t.Run("creates the auction_id/amount index", func(t *testing.T) {
+		cursor, err := repo.Collection.Indexes().List(ctx)
+		assert.Nil(t, err)
+
+		var indexes []bson.M
+		assert.Nil(t, cursor.All(ctx, &indexes))
+
+		names := make([]string, 0, len(indexes))
+		for _, index := range indexes {
+			names = append(names, fmt.Sprint(index["name"]))
+		}
+		assert.Contains(t, names, auctionAmountIndex)
+	})
+
+	t.Run("returns the highest bid", func(t *testing.T) {
+		auctionId := uuid.New().String()
+		var highBidId string
+		for _, amount := range []bid_entity.Cents{150, 300, 120} {
+			bidId := uuid.New().String()
+			_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+				Id:        bidId,
+				UserId:    uuid.New().String(),
+				AuctionId: auctionId,
+				Amount:    amount,
+				Timestamp: time.Now().Unix(),
+			})
+			assert.Nil(t, err)
+			if amount == 300 {
+				highBidId = bidId
+			}
+		}
+
+		// Lances de outro leilão não interferem
+		_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+			Id:        uuid.New().String(),
+			UserId:    uuid.New().String(),
+			AuctionId: uuid.New().String(),
+			Amount:    1000,
+			Timestamp: time.Now().Unix(),
+		})
+		assert.Nil(t, err)
+
+		highBid, internalErr := repo.CurrentHighBid(ctx, auctionId)
+		assert.Nil(t, internalErr)
+		assert.Equal(t, highBidId, highBid.Id)
+		assert.Equal(t, bid_entity.Cents(300), highBid.Amount)
+	})
+
+	t.Run("breaks ties by the earliest bid", func(t *testing.T) {
+		auctionId := uuid.New().String()
+		now := time.Now().Unix()
+		for _, bid := range []BidEntityMongo{
+			{Id: "later", Amount: 500, Timestamp: now},
+			{Id: "earliest", Amount: 500, Timestamp: now - 10},
+			{Id: "lower", Amount: 400, Timestamp: now - 20},
+		} {
+			bid.UserId = uuid.New().String()
+			bid.AuctionId = auctionId
+			_, err := repo.Collection.InsertOne(ctx, bid)
+			assert.Nil(t, err)
+		}
+
+		highBid, internalErr := repo.CurrentHighBid(ctx, auctionId)
+		assert.Nil(t, internalErr)
+		assert.Equal(t, "earliest", highBid.Id)
+	})
+
+	t.Run("returns not found without bids", func(t *testing.T) {
+		highBid, err := repo.CurrentHighBid(ctx, uuid.New().String())
+		assert.Nil(t, highBid)
+		assert.Equal(t, "not_found", err.Err)
+	})
+}
@@ -13,7 +13,7 @@ import (
 
 func (bd *BidRepository) FindBidByAuctionId(
 	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auctionId": auctionId}
+	filter := bson.M{"auction_id": auctionId}
 
 	cursor, err := bd.Collection.Find(ctx, filter)
 	if err != nil {
@@ -38,19 +38,70 @@ func (bd *BidRepository) FindBidByAuctionId(
 			UserId:    bidEntityMongo.UserId,
 			AuctionId: bidEntityMongo.AuctionId,
 			Amount:    bidEntityMongo.Amount,
-			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0).UTC(),
 		})
 	}
 
 	return bidEntities, nil
 }
 
+// FindLatestBidsByAuctionId returns the limit most recent bids of an
+// auction, newest first with bids placed in the same second ordered by id,
+// and the number of bids of the auction.
+func (bd *BidRepository) FindLatestBidsByAuctionId(
+	ctx context.Context, auctionId string, limit int64) ([]bid_entity.Bid, int64, *internal_error.InternalError) {
+	if limit < 1 {
+		return nil, 0, internal_error.NewBadRequestError("limit must be positive")
+	}
+
+	filter := bson.M{"auction_id": auctionId}
+	total, err := bd.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Error(
+			fmt.Sprintf("Error trying to count bids by auctionId %s", auctionId), err)
+		return nil, 0, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := bd.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
+		return nil, 0, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+		logger.Error(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
+		return nil, 0, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+
+	bidEntities := make([]bid_entity.Bid, 0, len(bidEntitiesMongo))
+	for _, bidEntityMongo := range bidEntitiesMongo {
+		bidEntities = append(bidEntities, bid_entity.Bid{
+			Id:        bidEntityMongo.Id,
+			UserId:    bidEntityMongo.UserId,
+			AuctionId: bidEntityMongo.AuctionId,
+			Amount:    bidEntityMongo.Amount,
+			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0).UTC(),
+		})
+	}
+
+	return bidEntities, total, nil
+}
+
 func (bd *BidRepository) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
 	filter := bson.M{"auction_id": auctionId}
 
 	var bidEntityMongo BidEntityMongo
-	opts := options.FindOne().SetSort(bson.D{{"amount", -1}})
+	opts := options.FindOne().SetSort(highBidSort)
 	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
 		logger.Error("Error trying to find the auction winner", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find the auction winner")
@@ -61,6 +112,84 @@ func (bd *BidRepository) FindWinningBidByAuctionId(
 		UserId:    bidEntityMongo.UserId,
 		AuctionId: bidEntityMongo.AuctionId,
 		Amount:    bidEntityMongo.Amount,
-		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
+		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0).UTC(),
 	}, nil
 }
+
+// FindBidsByUser returns one page of the bids of userId, newest first; bids
+// placed in the same second are ordered by id so pages never overlap.
+func (bd *BidRepository) FindBidsByUser(
+	ctx context.Context, userId string, page, pageSize int64) ([]bid_entity.Bid, *internal_error.InternalError) {
+	if page < 1 || pageSize < 1 {
+		return nil, internal_error.NewBadRequestError("page and pageSize must be positive")
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+	return bd.findUserBids(ctx, userId, bson.M{"user_id": userId}, opts)
+}
+
+// FindBidsByUserAfter returns up to limit bids of userId in the order of
+// FindBidsByUser, starting right after the bid at (afterTimestamp,
+// afterId); a zero afterTimestamp starts from the newest bid. Unlike a
+// page, its cost does not grow with how far into the bids it starts.
+func (bd *BidRepository) FindBidsByUserAfter(
+	ctx context.Context,
+	userId string,
+	afterTimestamp time.Time,
+	afterId string,
+	limit int64) ([]bid_entity.Bid, *internal_error.InternalError) {
+	if limit < 1 {
+		return nil, internal_error.NewBadRequestError("limit must be positive")
+	}
+
+	filter := bson.M{"user_id": userId}
+	if !afterTimestamp.IsZero() {
+		filter["$or"] = bson.A{
+			bson.M{"timestamp": bson.M{"$lt": afterTimestamp.Unix()}},
+			bson.M{"timestamp": afterTimestamp.Unix(), "_id": bson.M{"$lt": afterId}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+	return bd.findUserBids(ctx, userId, filter, opts)
+}
+
+func (bd *BidRepository) findUserBids(
+	ctx context.Context,
+	userId string,
+	filter bson.M,
+	opts *options.FindOptions) ([]bid_entity.Bid, *internal_error.InternalError) {
+	cursor, err := bd.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error(
+			fmt.Sprintf("Error trying to find bids by userId %s", userId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by userId %s", userId))
+	}
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+		logger.Error(
+			fmt.Sprintf("Error trying to find bids by userId %s", userId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by userId %s", userId))
+	}
+
+	bidEntities := make([]bid_entity.Bid, 0, len(bidEntitiesMongo))
+	for _, bidEntityMongo := range bidEntitiesMongo {
+		bidEntities = append(bidEntities, bid_entity.Bid{
+			Id:        bidEntityMongo.Id,
+			UserId:    bidEntityMongo.UserId,
+			AuctionId: bidEntityMongo.AuctionId,
+			Amount:    bidEntityMongo.Amount,
+			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0).UTC(),
+		})
+	}
+
+	return bidEntities, nil
+}
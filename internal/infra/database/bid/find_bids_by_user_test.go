@@ -0,0 +1,105 @@
+package bid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindBidsByUser(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewBidRepository(db, &auctionLookupMock{}, &userRepositoryMock{users: map[string]user_entity.User{}})
+
+	userId := uuid.New().String()
+	for i, timestamp := range []int64{1000, 1030, 1010, 1040, 1020} {
+		_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+			Id:        uuid.New().String(),
+			UserId:    userId,
+			AuctionId: uuid.New().String(),
+			Amount:    bid_entity.Cents(100 * (i + 1)),
+			Timestamp: timestamp,
+		})
+		assert.Nil(t, err)
+	}
+	// Lances de outro usuário não aparecem
+	_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+		Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: uuid.New().String(),
+		Amount: 100, Timestamp: 2000,
+	})
+	assert.Nil(t, err)
+
+	timestamps := func(page, pageSize int64) []int64 {
+		bids, err := repo.FindBidsByUser(ctx, userId, page, pageSize)
+		assert.Nil(t, err)
+
+		var result []int64
+		for _, bid := range bids {
+			assert.Equal(t, userId, bid.UserId)
+			result = append(result, bid.Timestamp.Unix())
+		}
+		return result
+	}
+
+	t.Run("pages newest first", func(t *testing.T) {
+		assert.Equal(t, []int64{1040, 1030}, timestamps(1, 2))
+		assert.Equal(t, []int64{1020, 1010}, timestamps(2, 2))
+		assert.Equal(t, []int64{1000}, timestamps(3, 2))
+		assert.Empty(t, timestamps(4, 2))
+	})
+
+	t.Run("rejects a non-positive page", func(t *testing.T) {
+		bids, err := repo.FindBidsByUser(ctx, userId, 0, 2)
+		assert.Nil(t, bids)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
+
+func TestFindBidsByUserAfter(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewBidRepository(db, &auctionLookupMock{}, &userRepositoryMock{users: map[string]user_entity.User{}})
+
+	// Lances no mesmo segundo são desempatados pelo id
+	userId := uuid.New().String()
+	for _, bid := range []BidEntityMongo{
+		{Id: "a", Timestamp: 1000}, {Id: "b", Timestamp: 1010}, {Id: "c", Timestamp: 1010}, {Id: "d", Timestamp: 1020},
+	} {
+		bid.UserId = userId
+		bid.AuctionId = uuid.New().String()
+		bid.Amount = 100
+		_, err := repo.Collection.InsertOne(ctx, bid)
+		assert.Nil(t, err)
+	}
+
+	var ids []string
+	var after bid_entity.Bid
+	for {
+		bids, err := repo.FindBidsByUserAfter(ctx, userId, after.Timestamp, after.Id, 2)
+		assert.Nil(t, err)
+		for _, bid := range bids {
+			ids = append(ids, bid.Id)
+		}
+		if len(bids) < 2 {
+			break
+		}
+		after = bids[len(bids)-1]
+	}
+	assert.Equal(t, []string{"d", "c", "b", "a"}, ids)
+}
@@ -0,0 +1,54 @@
+package bid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindLatestBidsByAuctionId(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewBidRepository(db, &auctionLookupMock{}, &userRepositoryMock{users: map[string]user_entity.User{}})
+
+	auctionId := uuid.New().String()
+	for i, timestamp := range []int64{1000, 1030, 1010, 1040, 1020} {
+		_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+			Id:        uuid.New().String(),
+			UserId:    uuid.New().String(),
+			AuctionId: auctionId,
+			Amount:    bid_entity.Cents(100 * (i + 1)),
+			Timestamp: timestamp,
+		})
+		assert.Nil(t, err)
+	}
+	// Lances de outro leilão não aparecem nem contam
+	_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+		Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: uuid.New().String(),
+		Amount: 100, Timestamp: 2000,
+	})
+	assert.Nil(t, err)
+
+	bids, total, findErr := repo.FindLatestBidsByAuctionId(ctx, auctionId, 3)
+	assert.Nil(t, findErr)
+	assert.Equal(t, int64(5), total)
+
+	var timestamps []int64
+	for _, bid := range bids {
+		timestamps = append(timestamps, bid.Timestamp.Unix())
+	}
+	assert.Equal(t, []int64{1040, 1030, 1020}, timestamps)
+
+	_, _, findErr = repo.FindLatestBidsByAuctionId(ctx, auctionId, 0)
+	assert.NotNil(t, findErr)
+}
@@ -0,0 +1,68 @@
+package bid
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/database/mongodb"
+	"github.com/danielencestari/lab03/configuration/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ensureIndexesTimeout = 10 * time.Second
+	auctionAmountIndex   = "auction_id_amount_cents_timestamp"
+	legacyAmountIndex    = "auction_id_amount"
+	centsAmountIndex     = "auction_id_amount_cents"
+	userTimestampIndex   = "user_id_timestamp_id"
+	auctionLatestIndex   = "auction_id_timestamp_id"
+	legacyUserIndex      = "user_id_timestamp"
+)
+
+// highBidSort orders the bids of an auction from the highest; between
+// equal amounts the earliest bid comes first, as when a winner is stored.
+var highBidSort = bson.D{{Key: "amount_cents", Value: -1}, {Key: "timestamp", Value: 1}}
+
+// ensureIndexes creates the indexes the repository relies on. Failures are
+// logged and never block startup; queries still work without them, only
+// slower.
+func (bd *BidRepository) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), ensureIndexesTimeout)
+	defer cancel()
+
+	// Serves CurrentHighBid and FindWinningBidByAuctionId without sorting
+	// every bid of the auction, ties included
+	if _, err := bd.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    append(bson.D{{Key: "auction_id", Value: 1}}, highBidSort...),
+		Options: options.Index().SetName(auctionAmountIndex),
+	}); err != nil {
+		logger.Error("Error trying to create bid auction_id/amount_cents/timestamp index", err)
+	}
+
+	// Serves FindBidsByUser and FindBidsByUserAfter, newest first, with the
+	// id tie-break of their sort
+	if _, err := bd.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName(userTimestampIndex),
+	}); err != nil {
+		logger.Error("Error trying to create bid user_id/timestamp/_id index", err)
+	}
+
+	// Serves FindLatestBidsByAuctionId, newest first
+	if _, err := bd.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName(auctionLatestIndex),
+	}); err != nil {
+		logger.Error("Error trying to create bid auction_id/timestamp/_id index", err)
+	}
+
+	// Replaced by auctionAmountIndex when amounts moved to cents and again
+	// when it gained the timestamp tie-break, and by userTimestampIndex
+	// when the user index gained the id
+	mongodb.DropObsoleteIndexes(ctx, bd.Collection, []string{legacyAmountIndex, centsAmountIndex, legacyUserIndex})
+
+	mongodb.WarnMissingIndexes(ctx, bd.Collection, []string{auctionAmountIndex, userTimestampIndex, auctionLatestIndex})
+}
@@ -0,0 +1,85 @@
+package bid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// RetractBid deletes a bid placed less than BID_RETRACT_WINDOW ago on an
+// auction that is still active. The high bid is always derived from the
+// stored bids, so removing the bid is enough to recompute it.
+func (bd *BidRepository) RetractBid(ctx context.Context, bidId string) *internal_error.InternalError {
+	retractWindow := getBidRetractWindow()
+	if retractWindow <= 0 {
+		return internal_error.NewBadRequestError("Bid retraction is disabled")
+	}
+
+	var bidEntityMongo BidEntityMongo
+	if err := bd.Collection.FindOne(ctx, bson.M{"_id": bidId}).Decode(&bidEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return internal_error.NewNotFoundError(fmt.Sprintf("Bid not found with this id = %s", bidId))
+		}
+
+		logger.Error(fmt.Sprintf("Error trying to find bid by id = %s", bidId), err)
+		return internal_error.NewInternalServerError("Error trying to retract bid")
+	}
+
+	if time.Since(time.Unix(bidEntityMongo.Timestamp, 0)) > retractWindow {
+		return internal_error.NewBadRequestError("Bid retraction window has expired")
+	}
+
+	auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidEntityMongo.AuctionId)
+	if err != nil {
+		return err
+	}
+	if auctionEntity.Status != auction_entity.Active {
+		return internal_error.NewConflictError("Bids can only be retracted while the auction is active")
+	}
+
+	result, deleteErr := bd.Collection.DeleteOne(ctx, bson.M{"_id": bidId})
+	if deleteErr != nil {
+		logger.Error(fmt.Sprintf("Error trying to delete bid = %s", bidId), deleteErr)
+		return internal_error.NewInternalServerError("Error trying to retract bid")
+	}
+	// A concurrent retraction already removed the bid and its count
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(fmt.Sprintf("Bid not found with this id = %s", bidId))
+	}
+	bd.adjustBidCount(ctx, bidEntityMongo.AuctionId, -1)
+
+	fields := []zap.Field{
+		zap.String("bid_id", bidId),
+		zap.String("auction_id", bidEntityMongo.AuctionId),
+	}
+	var highBid BidEntityMongo
+	opts := options.FindOne().SetSort(highBidSort)
+	highBidErr := bd.Collection.FindOne(ctx, bson.M{"auction_id": bidEntityMongo.AuctionId}, opts).Decode(&highBid)
+	if highBidErr == nil {
+		fields = append(fields, zap.String("high_bid_id", highBid.Id), zap.Stringer("high_bid_amount", highBid.Amount))
+	} else if !errors.Is(highBidErr, mongo.ErrNoDocuments) {
+		logger.Error("Error trying to recompute the high bid after retraction", highBidErr)
+	}
+	logger.Info("Bid retracted", fields...)
+
+	return nil
+}
+
+func getBidRetractWindow() time.Duration {
+	retractWindow, err := time.ParseDuration(os.Getenv("BID_RETRACT_WINDOW"))
+	if err != nil {
+		return 0
+	}
+
+	return retractWindow
+}
@@ -0,0 +1,97 @@
+package bid
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetractBid(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	os.Setenv("BID_RETRACT_WINDOW", "1m")
+	defer os.Unsetenv("BID_RETRACT_WINDOW")
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	auctions := &auctionLookupMock{}
+	repo := NewBidRepository(db, auctions, &userRepositoryMock{users: map[string]user_entity.User{}})
+
+	insertBid := func(auctionId string, amount bid_entity.Cents, placedAt time.Time) string {
+		bidId := uuid.New().String()
+		_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+			Id:        bidId,
+			UserId:    uuid.New().String(),
+			AuctionId: auctionId,
+			Amount:    amount,
+			Timestamp: placedAt.Unix(),
+		})
+		assert.Nil(t, err)
+		return bidId
+	}
+
+	t.Run("bid inside the window is retracted", func(t *testing.T) {
+		auctionId := uuid.New().String()
+		auctions.auction = auction_entity.Auction{Id: auctionId, Status: auction_entity.Active}
+		insertBid(auctionId, 100, time.Now().Add(-2*time.Minute))
+		highBidId := insertBid(auctionId, 200, time.Now())
+
+		assert.Nil(t, repo.RetractBid(ctx, highBidId))
+
+		winningBid, err := repo.FindWinningBidByAuctionId(ctx, auctionId)
+		assert.Nil(t, err)
+		assert.Equal(t, bid_entity.Cents(100), winningBid.Amount)
+	})
+
+	t.Run("bid outside the window is rejected", func(t *testing.T) {
+		auctionId := uuid.New().String()
+		auctions.auction = auction_entity.Auction{Id: auctionId, Status: auction_entity.Active}
+		bidId := insertBid(auctionId, 100, time.Now().Add(-2*time.Minute))
+
+		err := repo.RetractBid(ctx, bidId)
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+
+		winningBid, findErr := repo.FindWinningBidByAuctionId(ctx, auctionId)
+		assert.Nil(t, findErr)
+		assert.Equal(t, bidId, winningBid.Id)
+	})
+
+	t.Run("bid on a closed auction is kept", func(t *testing.T) {
+		auctionId := uuid.New().String()
+		auctions.auction = auction_entity.Auction{Id: auctionId, Status: auction_entity.Completed}
+		bidId := insertBid(auctionId, 100, time.Now())
+
+		err := repo.RetractBid(ctx, bidId)
+		assert.NotNil(t, err)
+		assert.Equal(t, "conflict", err.Err)
+
+		winningBid, findErr := repo.FindWinningBidByAuctionId(ctx, auctionId)
+		assert.Nil(t, findErr)
+		assert.Equal(t, bidId, winningBid.Id)
+	})
+
+	t.Run("retraction is disabled by default", func(t *testing.T) {
+		os.Unsetenv("BID_RETRACT_WINDOW")
+		defer os.Setenv("BID_RETRACT_WINDOW", "1m")
+
+		auctionId := uuid.New().String()
+		auctions.auction = auction_entity.Auction{Id: auctionId, Status: auction_entity.Active}
+		bidId := insertBid(auctionId, 100, time.Now())
+
+		err := repo.RetractBid(ctx, bidId)
+		assert.NotNil(t, err)
+		assert.Equal(t, "bad_request", err.Err)
+	})
+}
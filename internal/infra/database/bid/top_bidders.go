@@ -0,0 +1,66 @@
+package bid
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TopBidders returns up to n distinct bidders of the auction ordered by
+// their highest bid. Bids are sorted by amount and time before grouping,
+// so each bidder keeps the time they first placed their highest amount;
+// equal amounts are ranked by that time, then by user id when placed in
+// the same second.
+func (bd *BidRepository) TopBidders(
+	ctx context.Context, auctionId string, n int) ([]bid_entity.BidderRank, *internal_error.InternalError) {
+	if n <= 0 {
+		return nil, internal_error.NewBadRequestError("n must be greater than zero")
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"auction_id": auctionId}}},
+		{{Key: "$sort", Value: bson.D{{Key: "amount_cents", Value: -1}, {Key: "timestamp", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$user_id",
+			"amount":    bson.M{"$max": "$amount_cents"},
+			"timestamp": bson.M{"$first": "$timestamp"},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: n}},
+	}
+
+	cursor, err := bd.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to aggregate top bidders", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find top bidders")
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		UserId    string           `bson:"_id"`
+		Amount    bid_entity.Cents `bson:"amount"`
+		Timestamp int64            `bson:"timestamp"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error("Error trying to decode top bidders", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find top bidders")
+	}
+
+	ranks := make([]bid_entity.BidderRank, 0, len(groups))
+	for _, group := range groups {
+		ranks = append(ranks, bid_entity.BidderRank{
+			UserId:    group.UserId,
+			Amount:    group.Amount,
+			Timestamp: time.Unix(group.Timestamp, 0).UTC(),
+		})
+	}
+
+	return ranks, nil
+}
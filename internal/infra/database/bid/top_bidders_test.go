@@ -0,0 +1,85 @@
+package bid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTopBidders(t *testing.T) {
+	if !isMongoDBAvailable() {
+		t.Skip("MongoDB não está disponível - Execute este teste com MongoDB rodando")
+	}
+
+	db, cleanup := setupTestDB()
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewBidRepository(db, &auctionLookupMock{}, &userRepositoryMock{users: map[string]user_entity.User{}})
+
+	ranks, internalErr := repo.TopBidders(ctx, uuid.New().String(), 0)
+	assert.Nil(t, ranks)
+	assert.Equal(t, "bad_request", internalErr.Err)
+
+	_, err := repo.Collection.Aggregate(ctx, mongo.Pipeline{{{Key: "$group", Value: bson.M{
+		"_id": "$user_id", "amount": bson.M{"$max": "$amount_cents"}, "timestamp": bson.M{"$first": "$timestamp"}}}}})
+	if err != nil && strings.Contains(err.Error(), "not implemented") {
+		t.Skip("Agregação não suportada por este servidor MongoDB")
+	}
+
+	auctionId := uuid.New().String()
+	insertBid := func(auctionId, userId string, amount bid_entity.Cents, timestamp int64) {
+		_, err := repo.Collection.InsertOne(ctx, BidEntityMongo{
+			Id:        uuid.New().String(),
+			UserId:    userId,
+			AuctionId: auctionId,
+			Amount:    amount,
+			Timestamp: timestamp,
+		})
+		assert.Nil(t, err)
+	}
+
+	// alice e carol empatam em 300; carol chegou primeiro a esse valor
+	insertBid(auctionId, "alice", 100, 1000)
+	insertBid(auctionId, "alice", 300, 1050)
+	insertBid(auctionId, "bob", 250, 1010)
+	insertBid(auctionId, "bob", 200, 1020)
+	insertBid(auctionId, "carol", 300, 1040)
+	insertBid(auctionId, "dave", 50, 1005)
+	// Lances de outro leilão não entram no ranking
+	insertBid(uuid.New().String(), "erin", 1000, 1000)
+
+	t.Run("ranks distinct bidders by their highest bid", func(t *testing.T) {
+		ranks, err := repo.TopBidders(ctx, auctionId, 10)
+		assert.Nil(t, err)
+		if assert.Len(t, ranks, 4) {
+			users := []string{ranks[0].UserId, ranks[1].UserId, ranks[2].UserId, ranks[3].UserId}
+			assert.Equal(t, []string{"carol", "alice", "bob", "dave"}, users)
+			assert.Equal(t, bid_entity.Cents(300), ranks[1].Amount)
+			assert.Equal(t, int64(1050), ranks[1].Timestamp.Unix())
+			assert.Equal(t, bid_entity.Cents(250), ranks[2].Amount)
+		}
+	})
+
+	t.Run("limits the leaderboard to n bidders", func(t *testing.T) {
+		ranks, err := repo.TopBidders(ctx, auctionId, 2)
+		assert.Nil(t, err)
+		if assert.Len(t, ranks, 2) {
+			assert.Equal(t, "carol", ranks[0].UserId)
+			assert.Equal(t, "alice", ranks[1].UserId)
+		}
+	})
+
+	t.Run("returns an empty leaderboard without bids", func(t *testing.T) {
+		ranks, err := repo.TopBidders(ctx, uuid.New().String(), 5)
+		assert.Nil(t, err)
+		assert.Empty(t, ranks)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/danielencestari/lab03/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserEntityMongo struct {
@@ -51,3 +52,38 @@ func (ur *UserRepository) FindUserById(
 
 	return userEntity, nil
 }
+
+func (ur *UserRepository) FindUsers(
+	ctx context.Context, page, pageSize int64) ([]user_entity.User, int64, *internal_error.InternalError) {
+	total, err := ur.Collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		logger.Error("Error trying to count users", err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find users")
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+	cursor, err := ur.Collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		logger.Error("Error trying to find users", err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find users")
+	}
+
+	var usersMongo []UserEntityMongo
+	if err := cursor.All(ctx, &usersMongo); err != nil {
+		logger.Error("Error trying to decode users", err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to find users")
+	}
+
+	users := make([]user_entity.User, 0, len(usersMongo))
+	for _, userMongo := range usersMongo {
+		users = append(users, user_entity.User{
+			Id:   userMongo.Id,
+			Name: userMongo.Name,
+		})
+	}
+
+	return users, total, nil
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+// maxCachedUsers bounds the cache; the least recently used users are
+// dropped first.
+const maxCachedUsers = 10000
+
+// CachedUserRepository keeps the users found by FindUserById for a short
+// ttl, so repeated lookups of the same bidder skip the database. Errors are
+// never cached. Users are never written through this repository, so
+// entries only leave the cache by expiring or being evicted.
+type CachedUserRepository struct {
+	user_entity.UserRepositoryInterface
+
+	mutex   sync.Mutex
+	ttl     time.Duration
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+type cachedUser struct {
+	userId    string
+	user      user_entity.User
+	expiresAt time.Time
+}
+
+// NewCachedUserRepository wraps repository with a FindUserById cache whose
+// entries live at most ttl.
+func NewCachedUserRepository(
+	repository user_entity.UserRepositoryInterface, ttl time.Duration) *CachedUserRepository {
+	return &CachedUserRepository{
+		UserRepositoryInterface: repository,
+		ttl:                     ttl,
+		size:                    maxCachedUsers,
+		order:                   list.New(),
+		entries:                 make(map[string]*list.Element),
+		now:                     time.Now,
+	}
+}
+
+func (cr *CachedUserRepository) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	if user, ok := cr.get(userId); ok {
+		return user, nil
+	}
+
+	user, err := cr.UserRepositoryInterface.FindUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	cr.put(userId, *user)
+	return user, nil
+}
+
+func (cr *CachedUserRepository) get(userId string) (*user_entity.User, bool) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	element, ok := cr.entries[userId]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cachedUser)
+	if cr.now().After(entry.expiresAt) {
+		cr.order.Remove(element)
+		delete(cr.entries, userId)
+		return nil, false
+	}
+
+	cr.order.MoveToFront(element)
+
+	// Hand out a copy so callers cannot mutate the cached value
+	user := entry.user
+	return &user, true
+}
+
+func (cr *CachedUserRepository) put(userId string, user user_entity.User) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	expiresAt := cr.now().Add(cr.ttl)
+	if element, ok := cr.entries[userId]; ok {
+		entry := element.Value.(*cachedUser)
+		entry.user = user
+		entry.expiresAt = expiresAt
+		cr.order.MoveToFront(element)
+		return
+	}
+
+	cr.entries[userId] = cr.order.PushFront(&cachedUser{userId: userId, user: user, expiresAt: expiresAt})
+
+	if cr.order.Len() > cr.size {
+		oldest := cr.order.Back()
+		cr.order.Remove(oldest)
+		delete(cr.entries, oldest.Value.(*cachedUser).userId)
+	}
+}
@@ -0,0 +1,100 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/user_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingUserRepository struct {
+	user_entity.UserRepositoryInterface
+
+	users map[string]user_entity.User
+	calls int
+}
+
+func (m *countingUserRepository) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	m.calls++
+	user, ok := m.users[userId]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("user not found")
+	}
+
+	return &user, nil
+}
+
+func TestCachedUserRepository(t *testing.T) {
+	ctx := context.Background()
+	newCache := func() (*CachedUserRepository, *countingUserRepository, *time.Time) {
+		repository := &countingUserRepository{users: map[string]user_entity.User{
+			"user-1": {Id: "user-1", Name: "Alice"},
+		}}
+		now := time.Unix(1718985600, 0)
+		cache := NewCachedUserRepository(repository, time.Minute)
+		cache.now = func() time.Time { return now }
+		return cache, repository, &now
+	}
+
+	t.Run("a second lookup within the ttl skips the repository", func(t *testing.T) {
+		cache, repository, now := newCache()
+
+		first, err := cache.FindUserById(ctx, "user-1")
+		assert.Nil(t, err)
+		*now = now.Add(30 * time.Second)
+		second, err := cache.FindUserById(ctx, "user-1")
+		assert.Nil(t, err)
+
+		assert.Equal(t, "Alice", first.Name)
+		assert.Equal(t, *first, *second)
+		assert.Equal(t, 1, repository.calls)
+	})
+
+	t.Run("entries expire after the ttl", func(t *testing.T) {
+		cache, repository, now := newCache()
+
+		_, err := cache.FindUserById(ctx, "user-1")
+		assert.Nil(t, err)
+		*now = now.Add(time.Minute + time.Second)
+		_, err = cache.FindUserById(ctx, "user-1")
+		assert.Nil(t, err)
+
+		assert.Equal(t, 2, repository.calls)
+	})
+
+	t.Run("the least recently used user is evicted past the size", func(t *testing.T) {
+		cache, repository, _ := newCache()
+		cache.size = 2
+		repository.users["user-2"] = user_entity.User{Id: "user-2", Name: "Bob"}
+		repository.users["user-3"] = user_entity.User{Id: "user-3", Name: "Carol"}
+
+		for _, userId := range []string{"user-1", "user-2", "user-1", "user-3"} {
+			_, err := cache.FindUserById(ctx, userId)
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, 3, repository.calls)
+
+		// user-2 was the least recently used, so it is looked up again
+		_, err := cache.FindUserById(ctx, "user-1")
+		assert.Nil(t, err)
+		_, err = cache.FindUserById(ctx, "user-2")
+		assert.Nil(t, err)
+		assert.Equal(t, 4, repository.calls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		cache, repository, _ := newCache()
+
+		_, err := cache.FindUserById(ctx, "missing")
+		assert.True(t, errors.Is(err, internal_error.ErrNotFound))
+		_, err = cache.FindUserById(ctx, "missing")
+		assert.True(t, errors.Is(err, internal_error.ErrNotFound))
+
+		assert.Equal(t, 2, repository.calls)
+	})
+}
@@ -1,12 +1,38 @@
 package internal_error
 
+import "fmt"
+
 type InternalError struct {
 	Message string
 	Err     string
+
+	// Fields maps each invalid field to its message; only set on
+	// validation errors
+	Fields map[string]string
 }
 
+// Sentinels for errors.Is comparisons; only the code is compared.
+var (
+	ErrNotFound            = &InternalError{Err: "not_found"}
+	ErrInternalServerError = &InternalError{Err: "internal_server_error"}
+	ErrBadRequest          = &InternalError{Err: "bad_request"}
+	ErrConflict            = &InternalError{Err: "conflict"}
+)
+
+// Error formats the error as "<code>: <message>".
 func (ie *InternalError) Error() string {
-	return ie.Message
+	return fmt.Sprintf("%s: %s", ie.Err, ie.Message)
+}
+
+func (ie *InternalError) String() string {
+	return ie.Error()
+}
+
+// Is reports whether target is an *InternalError with the same code, so
+// errors.Is(err, internal_error.ErrNotFound) matches any not found error.
+func (ie *InternalError) Is(target error) bool {
+	targetErr, ok := target.(*InternalError)
+	return ok && targetErr.Err == ie.Err
 }
 
 func NewNotFoundError(message string) *InternalError {
@@ -29,3 +55,20 @@ func NewBadRequestError(message string) *InternalError {
 		Err:     "bad_request",
 	}
 }
+
+func NewConflictError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "conflict",
+	}
+}
+
+// NewValidationError is a bad request error that reports every invalid
+// field with its own message.
+func NewValidationError(fields map[string]string) *InternalError {
+	return &InternalError{
+		Message: "invalid field values",
+		Err:     "bad_request",
+		Fields:  fields,
+	}
+}
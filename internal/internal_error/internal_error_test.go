@@ -0,0 +1,41 @@
+package internal_error
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternalErrorFormat(t *testing.T) {
+	err := NewNotFoundError("Auction not found")
+
+	assert.Equal(t, "not_found: Auction not found", err.Error())
+	assert.Equal(t, "not_found: Auction not found", err.String())
+	assert.Equal(t, "not_found: Auction not found", fmt.Sprint(err))
+}
+
+func TestInternalErrorIs(t *testing.T) {
+	var err error = NewConflictError("Auction is not active and cannot be updated")
+
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.False(t, errors.Is(err, ErrNotFound))
+
+	wrapped := fmt.Errorf("updating auction: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrConflict))
+
+	assert.True(t, errors.Is(NewBadRequestError("a"), NewBadRequestError("b")))
+	assert.False(t, errors.Is(NewBadRequestError("a"), errors.New("bad_request: a")))
+}
+
+func TestValidationError(t *testing.T) {
+	err := NewValidationError(map[string]string{
+		"product_name": "must have at least 2 characters",
+		"category":     "must have at least 3 characters",
+	})
+
+	assert.True(t, errors.Is(err, ErrBadRequest))
+	assert.Len(t, err.Fields, 2)
+	assert.Nil(t, NewBadRequestError("plain").Fields)
+}
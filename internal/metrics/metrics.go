@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultHistogramWindow is how many of the latest observations a
+// histogram keeps to compute percentiles.
+const defaultHistogramWindow = 1024
+
+// Registry holds the named metrics of the process so they can be exposed
+// together on the debug endpoint.
+type Registry struct {
+	mutex      sync.Mutex
+	histograms map[string]*Histogram
+	counters   map[string]*Counter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		histograms: make(map[string]*Histogram),
+		counters:   make(map[string]*Counter),
+	}
+}
+
+// Histogram returns the histogram registered under name, creating it on
+// first use.
+func (r *Registry) Histogram(name string) *Histogram {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	histogram, ok := r.histograms[name]
+	if !ok {
+		histogram = newHistogram(defaultHistogramWindow)
+		r.histograms[name] = histogram
+	}
+	return histogram
+}
+
+// Counter returns the counter registered under name, creating it on first
+// use.
+func (r *Registry) Counter(name string) *Counter {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	counter, ok := r.counters[name]
+	if !ok {
+		counter = &Counter{}
+		r.counters[name] = counter
+	}
+	return counter
+}
+
+// Snapshot is a point-in-time copy of every registered metric.
+type Snapshot struct {
+	Histograms map[string]HistogramSnapshot `json:"histograms"`
+	Counters   map[string]int64             `json:"counters"`
+}
+
+func (r *Registry) Snapshot() Snapshot {
+	r.mutex.Lock()
+	histograms := make(map[string]*Histogram, len(r.histograms))
+	for name, histogram := range r.histograms {
+		histograms[name] = histogram
+	}
+	counters := make(map[string]*Counter, len(r.counters))
+	for name, counter := range r.counters {
+		counters[name] = counter
+	}
+	r.mutex.Unlock()
+
+	snapshot := Snapshot{
+		Histograms: make(map[string]HistogramSnapshot, len(histograms)),
+		Counters:   make(map[string]int64, len(counters)),
+	}
+	for name, histogram := range histograms {
+		snapshot.Histograms[name] = histogram.Snapshot()
+	}
+	for name, counter := range counters {
+		snapshot.Counters[name] = counter.Value()
+	}
+	return snapshot
+}
+
+// Histogram records durations and reports percentiles over a sliding
+// window of the latest observations.
+type Histogram struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+	count   int64
+}
+
+func newHistogram(window int) *Histogram {
+	return &Histogram{samples: make([]time.Duration, 0, window)}
+}
+
+func (h *Histogram) Observe(d time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.samples) < cap(h.samples) {
+		h.samples = append(h.samples, d)
+	} else {
+		h.samples[h.next] = d
+		h.next = (h.next + 1) % len(h.samples)
+	}
+	h.count++
+}
+
+// HistogramSnapshot reports percentiles in milliseconds. Count is the
+// total number of observations, not only those in the window.
+type HistogramSnapshot struct {
+	Count int64   `json:"count"`
+	P50   float64 `json:"p50_ms"`
+	P95   float64 `json:"p95_ms"`
+	P99   float64 `json:"p99_ms"`
+}
+
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mutex.Lock()
+	samples := make([]time.Duration, len(h.samples))
+	copy(samples, h.samples)
+	count := h.count
+	h.mutex.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	return HistogramSnapshot{
+		Count: count,
+		P50:   percentile(samples, 0.50),
+		P95:   percentile(samples, 0.95),
+		P99:   percentile(samples, 0.99),
+	}
+}
+
+// percentile uses the nearest-rank method on already sorted samples.
+func percentile(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return float64(sorted[rank]) / float64(time.Millisecond)
+}
+
+// Counter is a monotonically increasing count.
+type Counter struct {
+	value atomic.Int64
+}
+
+func (c *Counter) Add(delta int64) {
+	c.value.Add(delta)
+}
+
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramPercentiles(t *testing.T) {
+	histogram := newHistogram(1000)
+	for i := 1; i <= 100; i++ {
+		histogram.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	snapshot := histogram.Snapshot()
+	assert.Equal(t, int64(100), snapshot.Count)
+	assert.Equal(t, 50.0, snapshot.P50)
+	assert.Equal(t, 95.0, snapshot.P95)
+	assert.Equal(t, 99.0, snapshot.P99)
+}
+
+func TestHistogramKeepsLatestWindow(t *testing.T) {
+	histogram := newHistogram(10)
+	for i := 0; i < 10; i++ {
+		histogram.Observe(time.Second)
+	}
+	for i := 0; i < 10; i++ {
+		histogram.Observe(time.Millisecond)
+	}
+
+	snapshot := histogram.Snapshot()
+	assert.Equal(t, int64(20), snapshot.Count)
+	assert.Equal(t, 1.0, snapshot.P99)
+}
+
+func TestRegistrySnapshot(t *testing.T) {
+	registry := NewRegistry()
+	assert.Same(t, registry.Histogram("lookup"), registry.Histogram("lookup"))
+
+	registry.Histogram("lookup").Observe(2 * time.Millisecond)
+
+	snapshot := registry.Snapshot()
+	assert.Equal(t, int64(1), snapshot.Histograms["lookup"].Count)
+	assert.Equal(t, 2.0, snapshot.Histograms["lookup"].P50)
+
+	empty := NewRegistry().Snapshot()
+	assert.Empty(t, empty.Histograms)
+}
+
+func TestRegistryCounters(t *testing.T) {
+	registry := NewRegistry()
+	assert.Same(t, registry.Counter("recovered"), registry.Counter("recovered"))
+
+	registry.Counter("recovered").Add(2)
+	registry.Counter("recovered").Add(3)
+	registry.Counter("closed").Add(0)
+
+	snapshot := registry.Snapshot()
+	assert.Equal(t, int64(5), snapshot.Counters["recovered"])
+	assert.Equal(t, int64(0), snapshot.Counters["closed"])
+}
@@ -0,0 +1,82 @@
+package auction_usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/bid_usecase"
+)
+
+// maxDetailBids caps the bid history returned by GetAuctionDetail; older
+// bids are never read and BidsTruncated is set.
+const maxDetailBids = 100
+
+// AuctionDetailDTO is the complete record of an auction: the auction
+// itself, its most recent bids oldest first, the current high bid and the
+// winning bid, the last two being null when there is none.
+type AuctionDetailDTO struct {
+	Auction       AuctionOutputDTO           `json:"auction"`
+	Bids          []bid_usecase.BidOutputDTO `json:"bids"`
+	TotalBids     int                        `json:"total_bids"`
+	BidsTruncated bool                       `json:"bids_truncated"`
+	HighBid       *bid_usecase.BidOutputDTO  `json:"high_bid"`
+	Winner        *bid_usecase.BidOutputDTO  `json:"winner"`
+}
+
+// GetAuctionDetail bundles the auction with its bid history, high bid and
+// winner. Auctions may live in a routed database while bids always stay in
+// the main one, so the parts are read separately instead of with a lookup.
+func (au *AuctionUseCase) GetAuctionDetail(
+	ctx context.Context, auctionId string) (*AuctionDetailDTO, *internal_error.InternalError) {
+	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	bids, totalBids, err := au.bidRepositoryInterface.FindLatestBidsByAuctionId(ctx, auctionId, maxDetailBids)
+	if err != nil {
+		return nil, err
+	}
+
+	highBid, err := au.bidRepositoryInterface.CurrentHighBid(ctx, auctionId)
+	if err != nil && !errors.Is(err, internal_error.ErrNotFound) {
+		return nil, err
+	}
+
+	detail := &AuctionDetailDTO{
+		Auction:       newAuctionOutputDTO(*auction),
+		Bids:          []bid_usecase.BidOutputDTO{},
+		TotalBids:     int(totalBids),
+		BidsTruncated: totalBids > int64(len(bids)),
+	}
+
+	// The bids come newest first
+	for i := len(bids) - 1; i >= 0; i-- {
+		detail.Bids = append(detail.Bids, newBidOutputDTO(bids[i]))
+	}
+
+	if highBid != nil {
+		highBidOutput := newBidOutputDTO(*highBid)
+		detail.HighBid = &highBidOutput
+
+		// Bids can't be retracted once the auction closes, so the stored
+		// winner is still its high bid
+		if highBid.Id == auction.WinnerBidId {
+			detail.Winner = &highBidOutput
+		}
+	}
+
+	return detail, nil
+}
+
+func newBidOutputDTO(bid bid_entity.Bid) bid_usecase.BidOutputDTO {
+	return bid_usecase.BidOutputDTO{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid.Amount,
+		Timestamp: bid.Timestamp,
+	}
+}
@@ -0,0 +1,118 @@
+package auction_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type bidRepositoryMock struct {
+	bid_entity.BidEntityRepository
+
+	bids []bid_entity.Bid
+}
+
+func (m *bidRepositoryMock) FindLatestBidsByAuctionId(
+	ctx context.Context, auctionId string, limit int64) ([]bid_entity.Bid, int64, *internal_error.InternalError) {
+	var bids []bid_entity.Bid
+	for _, bid := range m.bids {
+		if bid.AuctionId == auctionId {
+			bids = append(bids, bid)
+		}
+	}
+
+	total := int64(len(bids))
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Timestamp.After(bids[j].Timestamp) })
+	if total > limit {
+		bids = bids[:limit]
+	}
+	return bids, total, nil
+}
+
+func (m *bidRepositoryMock) CurrentHighBid(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	var highBid *bid_entity.Bid
+	for i, bid := range m.bids {
+		if bid.AuctionId == auctionId && (highBid == nil || bid.Amount > highBid.Amount) {
+			highBid = &m.bids[i]
+		}
+	}
+	if highBid == nil {
+		return nil, internal_error.NewNotFoundError("no bids")
+	}
+
+	return highBid, nil
+}
+
+func TestGetAuctionDetail(t *testing.T) {
+	timestamp := time.Unix(1718985600, 0)
+	auctions := &auctionRepositoryMock{auctions: map[string]auction_entity.Auction{
+		"closed": {Id: "closed", ProductName: "Product", Status: auction_entity.Completed,
+			Timestamp: timestamp, WinnerBidId: "bid-2"},
+		"empty": {Id: "empty", ProductName: "Product", Status: auction_entity.Active, Timestamp: timestamp},
+	}}
+	bids := &bidRepositoryMock{bids: []bid_entity.Bid{
+		{Id: "bid-2", UserId: "user-2", AuctionId: "closed", Amount: 200, Timestamp: timestamp.Add(2 * time.Second)},
+		{Id: "bid-1", UserId: "user-1", AuctionId: "closed", Amount: 100, Timestamp: timestamp.Add(time.Second)},
+		{Id: "other", UserId: "user-1", AuctionId: "another", Amount: 500, Timestamp: timestamp},
+	}}
+	useCase := NewAuctionUseCase(auctions, bids)
+	ctx := context.Background()
+
+	t.Run("bundles the auction with its bids", func(t *testing.T) {
+		detail, err := useCase.GetAuctionDetail(ctx, "closed")
+		assert.Nil(t, err)
+
+		assert.Equal(t, "closed", detail.Auction.Id)
+		assert.Equal(t, 2, detail.TotalBids)
+		assert.False(t, detail.BidsTruncated)
+		if assert.Len(t, detail.Bids, 2) {
+			assert.Equal(t, "bid-1", detail.Bids[0].Id)
+			assert.Equal(t, "bid-2", detail.Bids[1].Id)
+		}
+		if assert.NotNil(t, detail.HighBid) {
+			assert.Equal(t, bid_entity.Cents(200), detail.HighBid.Amount)
+		}
+		if assert.NotNil(t, detail.Winner) {
+			assert.Equal(t, "user-2", detail.Winner.UserId)
+		}
+	})
+
+	t.Run("an auction without bids has no high bid nor winner", func(t *testing.T) {
+		detail, err := useCase.GetAuctionDetail(ctx, "empty")
+		assert.Nil(t, err)
+
+		assert.Empty(t, detail.Bids)
+		assert.Nil(t, detail.HighBid)
+		assert.Nil(t, detail.Winner)
+	})
+
+	t.Run("keeps only the most recent bids", func(t *testing.T) {
+		auctions.auctions["busy"] = auction_entity.Auction{Id: "busy", Timestamp: timestamp}
+		for i := 0; i < maxDetailBids+5; i++ {
+			bids.bids = append(bids.bids, bid_entity.Bid{Id: fmt.Sprintf("busy-%03d", i), AuctionId: "busy",
+				Amount: bid_entity.Cents(i + 1), Timestamp: timestamp.Add(time.Duration(i) * time.Second)})
+		}
+
+		detail, err := useCase.GetAuctionDetail(ctx, "busy")
+		assert.Nil(t, err)
+
+		assert.Equal(t, maxDetailBids+5, detail.TotalBids)
+		assert.True(t, detail.BidsTruncated)
+		assert.Len(t, detail.Bids, maxDetailBids)
+		assert.Equal(t, "busy-005", detail.Bids[0].Id)
+	})
+
+	t.Run("unknown auction", func(t *testing.T) {
+		_, err := useCase.GetAuctionDetail(ctx, "missing")
+		assert.True(t, errors.Is(err, internal_error.ErrNotFound))
+	})
+}
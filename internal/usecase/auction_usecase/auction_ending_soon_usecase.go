@@ -0,0 +1,50 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"time"
+)
+
+// maxEndingSoonWindow bounds the window of GetAuctionsEndingSoon so a
+// single request cannot list every active auction.
+const maxEndingSoonWindow = 24 * time.Hour
+
+// AuctionEndingSoonDTO is an auction summary with the whole seconds left
+// until it ends; zero once the end time has passed.
+type AuctionEndingSoonDTO struct {
+	AuctionSummaryDTO
+	RemainingSeconds int64 `json:"remaining_seconds"`
+}
+
+// GetAuctionsEndingSoon lists the active auctions ending within the given
+// window from now, soonest first.
+func (au *AuctionUseCase) GetAuctionsEndingSoon(
+	ctx context.Context, within time.Duration) ([]AuctionEndingSoonDTO, *internal_error.InternalError) {
+	if within <= 0 || within > maxEndingSoonWindow {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("within must be positive and at most %s", maxEndingSoonWindow))
+	}
+
+	now := time.Now()
+	auctions, err := au.auctionRepositoryInterface.FindAuctionsEndingBefore(ctx, now.Add(within))
+	if err != nil {
+		return nil, err
+	}
+
+	endingSoon := make([]AuctionEndingSoonDTO, 0, len(auctions))
+	for _, auction := range auctions {
+		remaining := auction.EndTime.Sub(now)
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		endingSoon = append(endingSoon, AuctionEndingSoonDTO{
+			AuctionSummaryDTO: newAuctionSummaryDTO(auction),
+			RemainingSeconds:  int64(remaining / time.Second),
+		})
+	}
+
+	return endingSoon, nil
+}
@@ -0,0 +1,66 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
+	"time"
+)
+
+// StatusChangeDTO is one status transition of an auction.
+type StatusChangeDTO struct {
+	From        AuctionStatus `json:"from"`
+	To          AuctionStatus `json:"to"`
+	At          time.Time     `json:"at"`
+	AtISO       string        `json:"at_iso"`
+	Reason      string        `json:"reason"`
+	CloseReason string        `json:"close_reason,omitempty"`
+}
+
+// AuctionHistoryDTO is a page of the status history of an auction; Total
+// counts every recorded transition, not only the ones in Changes.
+type AuctionHistoryDTO struct {
+	AuctionId string            `json:"auction_id"`
+	Page      int64             `json:"page"`
+	PageSize  int64             `json:"page_size"`
+	Total     int64             `json:"total"`
+	Changes   []StatusChangeDTO `json:"changes"`
+}
+
+// GetAuctionHistory returns a page of the status transitions of an auction,
+// oldest first. A zero page or page size falls back to the first page of
+// pagination.DefaultPageSize entries.
+func (au *AuctionUseCase) GetAuctionHistory(
+	ctx context.Context,
+	auctionId string,
+	page, pageSize int64) (*AuctionHistoryDTO, *internal_error.InternalError) {
+	page, pageSize, err := pagination.Normalize(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, total, err := au.auctionRepositoryInterface.FindStatusHistory(ctx, auctionId, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &AuctionHistoryDTO{
+		AuctionId: auctionId,
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		Changes:   make([]StatusChangeDTO, 0, len(changes)),
+	}
+	for _, change := range changes {
+		history.Changes = append(history.Changes, StatusChangeDTO{
+			From:        AuctionStatus(change.From),
+			To:          AuctionStatus(change.To),
+			At:          change.At,
+			AtISO:       formatISO(change.At),
+			Reason:      change.Reason,
+			CloseReason: change.CloseReason,
+		})
+	}
+
+	return history, nil
+}
@@ -0,0 +1,43 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+type AuctionStatsOutputDTO struct {
+	TotalAuctions     int64 `json:"total_auctions"`
+	ActiveAuctions    int64 `json:"active_auctions"`
+	CompletedAuctions int64 `json:"completed_auctions"`
+
+	// AverageDurationSeconds only covers completed auctions
+	AverageDurationSeconds float64 `json:"average_duration_seconds"`
+
+	// AverageBidsPerAuction is null until the first bid is placed
+	AverageBidsPerAuction *float64 `json:"average_bids_per_auction"`
+}
+
+func (au *AuctionUseCase) GetAuctionStats(
+	ctx context.Context) (*AuctionStatsOutputDTO, *internal_error.InternalError) {
+	stats, err := au.auctionRepositoryInterface.AuctionStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &AuctionStatsOutputDTO{
+		TotalAuctions:     stats.Total,
+		ActiveAuctions:    stats.Active,
+		CompletedAuctions: stats.Completed,
+	}
+
+	if stats.Completed > 0 {
+		output.AverageDurationSeconds = stats.CompletedDuration.Seconds() / float64(stats.Completed)
+	}
+
+	if stats.Bids > 0 && stats.Total > 0 {
+		averageBids := float64(stats.Bids) / float64(stats.Total)
+		output.AverageBidsPerAuction = &averageBids
+	}
+
+	return output, nil
+}
@@ -0,0 +1,61 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"time"
+)
+
+// AuctionTimeseriesInputDTO selects the bucket width ("hour" by default, or
+// "day") and the creation window in unix seconds; zero bounds are open.
+type AuctionTimeseriesInputDTO struct {
+	Bucket string
+	From   int64
+	To     int64
+}
+
+type AuctionTimeseriesPointDTO struct {
+	Bucket string `json:"bucket"`
+	Count  int64  `json:"count"`
+}
+
+func (au *AuctionUseCase) GetAuctionTimeseries(
+	ctx context.Context,
+	input AuctionTimeseriesInputDTO) ([]AuctionTimeseriesPointDTO, *internal_error.InternalError) {
+	bucket := auction_entity.TimeBucket(input.Bucket)
+	switch bucket {
+	case "":
+		bucket = auction_entity.HourBucket
+	case auction_entity.HourBucket, auction_entity.DayBucket:
+	default:
+		return nil, internal_error.NewBadRequestError("bucket must be hour or day")
+	}
+
+	if input.From != 0 && input.To != 0 && input.From >= input.To {
+		return nil, internal_error.NewBadRequestError("from must be before to")
+	}
+
+	var from, to time.Time
+	if input.From != 0 {
+		from = time.Unix(input.From, 0)
+	}
+	if input.To != 0 {
+		to = time.Unix(input.To, 0)
+	}
+
+	buckets, err := au.auctionRepositoryInterface.CountAuctionsByTime(ctx, bucket, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]AuctionTimeseriesPointDTO, 0, len(buckets))
+	for _, bucket := range buckets {
+		series = append(series, AuctionTimeseriesPointDTO{
+			Bucket: formatISO(bucket.Start),
+			Count:  bucket.Count,
+		})
+	}
+
+	return series, nil
+}
@@ -0,0 +1,25 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+type CancelAllInputDTO struct {
+	Reason string `json:"reason" binding:"required,min=3"`
+}
+
+type CancelAllOutputDTO struct {
+	Cancelled int64 `json:"cancelled"`
+}
+
+func (au *AuctionUseCase) CancelAllActive(
+	ctx context.Context,
+	reason string) (*CancelAllOutputDTO, *internal_error.InternalError) {
+	cancelled, err := au.auctionRepositoryInterface.CancelAllActive(ctx, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CancelAllOutputDTO{Cancelled: cancelled}, nil
+}
@@ -0,0 +1,47 @@
+package auction_usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type cancelAllRepositoryMock struct {
+	auction_entity.AuctionRepositoryInterface
+
+	cancelled int64
+	err       *internal_error.InternalError
+	reasons   []string
+}
+
+func (m *cancelAllRepositoryMock) CancelAllActive(
+	ctx context.Context, reason string) (int64, *internal_error.InternalError) {
+	m.reasons = append(m.reasons, reason)
+	return m.cancelled, m.err
+}
+
+func TestCancelAllActive(t *testing.T) {
+	t.Run("reports the cancelled count", func(t *testing.T) {
+		repository := &cancelAllRepositoryMock{cancelled: 3}
+		useCase := NewAuctionUseCase(repository, nil)
+
+		output, err := useCase.CancelAllActive(context.Background(), "maintenance")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), output.Cancelled)
+		assert.Equal(t, []string{"maintenance"}, repository.reasons)
+	})
+
+	t.Run("propagates repository errors", func(t *testing.T) {
+		repository := &cancelAllRepositoryMock{
+			err: internal_error.NewInternalServerError("Error trying to cancel all active auctions"),
+		}
+		useCase := NewAuctionUseCase(repository, nil)
+
+		output, err := useCase.CancelAllActive(context.Background(), "maintenance")
+		assert.Nil(t, output)
+		assert.Equal(t, "internal_server_error", err.Err)
+	})
+}
@@ -0,0 +1,24 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+type ConcurrencyLimitInputDTO struct {
+	MaxConcurrentAuctions int64 `json:"max_concurrent_auctions" binding:"required,gt=0"`
+}
+
+type ConcurrencyLimitOutputDTO struct {
+	MaxConcurrentAuctions int64 `json:"max_concurrent_auctions"`
+}
+
+func (au *AuctionUseCase) SetConcurrencyLimit(
+	ctx context.Context,
+	maxConcurrentAuctions int64) (*ConcurrencyLimitOutputDTO, *internal_error.InternalError) {
+	if err := au.auctionRepositoryInterface.SetMaxConcurrentAuctions(maxConcurrentAuctions); err != nil {
+		return nil, err
+	}
+
+	return &ConcurrencyLimitOutputDTO{MaxConcurrentAuctions: maxConcurrentAuctions}, nil
+}
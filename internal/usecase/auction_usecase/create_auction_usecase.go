@@ -12,18 +12,65 @@ import (
 type AuctionInputDTO struct {
 	ProductName string           `json:"product_name" binding:"required,min=1"`
 	Category    string           `json:"category" binding:"required,min=2"`
+	Subcategory string           `json:"subcategory" binding:"omitempty,min=2"`
 	Description string           `json:"description" binding:"required,min=10,max=200"`
 	Condition   ProductCondition `json:"condition" binding:"oneof=0 1 2"`
+	ImageURLs   []string         `json:"image_urls"`
+	OwnerId     string           `json:"owner_id"`
+
+	// IdempotencyKey comes from the Idempotency-Key header, not the body
+	IdempotencyKey string `json:"-"`
 }
 
 type AuctionOutputDTO struct {
 	Id          string           `json:"id"`
 	ProductName string           `json:"product_name"`
 	Category    string           `json:"category"`
+	Subcategory string           `json:"subcategory,omitempty"`
 	Description string           `json:"description"`
 	Condition   ProductCondition `json:"condition"`
 	Status      AuctionStatus    `json:"status"`
+	ImageURLs   []string         `json:"image_urls,omitempty"`
+	OwnerId     string           `json:"owner_id,omitempty"`
 	Timestamp   time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+
+	TimestampISO string `json:"timestamp_iso"`
+	EndTimeISO   string `json:"end_time_iso"`
+	ClosedAtISO  string `json:"closed_at_iso,omitempty"`
+
+	Version     int64  `json:"version"`
+	BidCount    int64  `json:"bid_count"`
+	CloseReason string `json:"close_reason,omitempty"`
+}
+
+// AuctionSummaryDTO is the lighter representation used by list and detail
+// views that do not need the description or timestamps.
+type AuctionSummaryDTO struct {
+	Id          string        `json:"id"`
+	ProductName string        `json:"product_name"`
+	Category    string        `json:"category"`
+	Status      AuctionStatus `json:"status"`
+	EndTimeISO  string        `json:"end_time_iso"`
+	BidCount    int64         `json:"bid_count"`
+}
+
+// AuctionFilterInputDTO holds the optional criteria of auction listings;
+// zero values are ignored.
+type AuctionFilterInputDTO struct {
+	Status      AuctionStatus
+	Category    string
+	Categories  []string
+	Subcategory string
+	ProductName string
+	CreatedFrom int64
+	CreatedTo   int64
+	HasWinner   *bool
+
+	ExcludeStatuses []AuctionStatus
+
+	// Page and PageSize select one page of the listing; zero lists all
+	Page     int64
+	PageSize int64
 }
 
 type WinningInfoOutputDTO struct {
@@ -31,31 +78,113 @@ type WinningInfoOutputDTO struct {
 	Bid     *bid_usecase.BidOutputDTO `json:"bid,omitempty"`
 }
 
+// AuctionUseCaseOption customizes an AuctionUseCase built by NewAuctionUseCase.
+type AuctionUseCaseOption func(*AuctionUseCase)
+
+// WithIDGenerator replaces the UUID ids given to new auctions, mainly so
+// tests can assert on predictable ids.
+func WithIDGenerator(idGenerator auction_entity.IDGenerator) AuctionUseCaseOption {
+	return func(au *AuctionUseCase) {
+		au.idGenerator = idGenerator
+	}
+}
+
 func NewAuctionUseCase(
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
-	bidRepositoryInterface bid_entity.BidEntityRepository) AuctionUseCaseInterface {
-	return &AuctionUseCase{
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	opts ...AuctionUseCaseOption) AuctionUseCaseInterface {
+	useCase := &AuctionUseCase{
 		auctionRepositoryInterface: auctionRepositoryInterface,
 		bidRepositoryInterface:     bidRepositoryInterface,
+		idGenerator:                auction_entity.UUIDGenerator{},
+	}
+
+	for _, opt := range opts {
+		opt(useCase)
 	}
+
+	return useCase
 }
 
 type AuctionUseCaseInterface interface {
 	CreateAuction(
 		ctx context.Context,
-		auctionInput AuctionInputDTO) *internal_error.InternalError
+		auctionInput AuctionInputDTO) (string, *internal_error.InternalError)
 
 	FindAuctionById(
 		ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError)
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError)
+		filterInput AuctionFilterInputDTO) ([]AuctionOutputDTO, *internal_error.InternalError)
+
+	CountAuctions(
+		ctx context.Context,
+		filterInput AuctionFilterInputDTO) (int64, *internal_error.InternalError)
+
+	FindAuctionSummaryById(
+		ctx context.Context, id string) (*AuctionSummaryDTO, *internal_error.InternalError)
+
+	FindAuctionSummaries(
+		ctx context.Context,
+		filterInput AuctionFilterInputDTO) ([]AuctionSummaryDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context,
 		auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+
+	GetAuctionDetail(
+		ctx context.Context,
+		auctionId string) (*AuctionDetailDTO, *internal_error.InternalError)
+
+	GetAuctionHistory(
+		ctx context.Context,
+		auctionId string,
+		page, pageSize int64) (*AuctionHistoryDTO, *internal_error.InternalError)
+
+	UpdateAuction(
+		ctx context.Context,
+		auctionId string,
+		auctionInput AuctionUpdateInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	PauseAuction(
+		ctx context.Context,
+		auctionId string) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	ResumeAuction(
+		ctx context.Context,
+		auctionId string) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	CancelAllActive(
+		ctx context.Context,
+		reason string) (*CancelAllOutputDTO, *internal_error.InternalError)
+
+	PurgeCompletedBefore(
+		ctx context.Context,
+		before time.Time) (*PurgeOutputDTO, *internal_error.InternalError)
+
+	SetConcurrencyLimit(
+		ctx context.Context,
+		maxConcurrentAuctions int64) (*ConcurrencyLimitOutputDTO, *internal_error.InternalError)
+
+	SetMaintenanceMode(
+		ctx context.Context,
+		enabled bool) (*MaintenanceModeOutputDTO, *internal_error.InternalError)
+
+	SimulateAuctions(
+		ctx context.Context,
+		count int,
+		duration time.Duration) (*SimulateOutputDTO, *internal_error.InternalError)
+
+	GetAuctionStats(ctx context.Context) (*AuctionStatsOutputDTO, *internal_error.InternalError)
+
+	GetAuctionTimeseries(
+		ctx context.Context,
+		input AuctionTimeseriesInputDTO) ([]AuctionTimeseriesPointDTO, *internal_error.InternalError)
+
+	GetAuctionsEndingSoon(
+		ctx context.Context,
+		within time.Duration) ([]AuctionEndingSoonDTO, *internal_error.InternalError)
 }
 
 type ProductCondition int64
@@ -64,24 +193,33 @@ type AuctionStatus int64
 type AuctionUseCase struct {
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
 	bidRepositoryInterface     bid_entity.BidEntityRepository
+	idGenerator                auction_entity.IDGenerator
 }
 
 func (au *AuctionUseCase) CreateAuction(
 	ctx context.Context,
-	auctionInput AuctionInputDTO) *internal_error.InternalError {
-	auction, err := auction_entity.CreateAuction(
+	auctionInput AuctionInputDTO) (string, *internal_error.InternalError) {
+	auction := auction_entity.NewAuction(
+		au.idGenerator,
 		auctionInput.ProductName,
 		auctionInput.Category,
 		auctionInput.Description,
 		auction_entity.ProductCondition(auctionInput.Condition))
-	if err != nil {
-		return err
+	auction.Subcategory = auctionInput.Subcategory
+	auction.IdempotencyKey = auctionInput.IdempotencyKey
+	auction.OwnerId = auctionInput.OwnerId
+	if len(auctionInput.ImageURLs) > 0 {
+		auction.ImageURLs = auctionInput.ImageURLs
+	}
+
+	if err := auction.Validate(); err != nil {
+		return "", err
 	}
 
 	if err := au.auctionRepositoryInterface.CreateAuction(
 		ctx, auction); err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return auction.Id, nil
 }
@@ -0,0 +1,150 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type sequentialIDGenerator struct {
+	next int
+}
+
+func (g *sequentialIDGenerator) NewID() string {
+	g.next++
+	return fmt.Sprintf("auction-%d", g.next)
+}
+
+func (m *auctionRepositoryMock) CreateAuction(
+	ctx context.Context, auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	m.auctions[auctionEntity.Id] = *auctionEntity
+	return nil
+}
+
+func TestCreateAuctionUsesInjectedIDGenerator(t *testing.T) {
+	repository := &auctionRepositoryMock{auctions: make(map[string]auction_entity.Auction)}
+	useCase := NewAuctionUseCase(repository, nil, WithIDGenerator(&sequentialIDGenerator{}))
+
+	input := AuctionInputDTO{
+		ProductName: "Notebook",
+		Category:    "Electronics",
+		Description: "Notebook in great condition",
+		Condition:   ProductCondition(auction_entity.Used),
+	}
+
+	for _, expectedId := range []string{"auction-1", "auction-2", "auction-3"} {
+		auctionId, err := useCase.CreateAuction(context.Background(), input)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedId, auctionId)
+		assert.Contains(t, repository.auctions, expectedId)
+	}
+}
+
+func TestCreateAuctionDefaultsToUUIDs(t *testing.T) {
+	repository := &auctionRepositoryMock{auctions: make(map[string]auction_entity.Auction)}
+	useCase := NewAuctionUseCase(repository, nil)
+
+	auctionId, err := useCase.CreateAuction(context.Background(), AuctionInputDTO{
+		ProductName: "Notebook",
+		Category:    "Electronics",
+		Description: "Notebook in great condition",
+		Condition:   ProductCondition(auction_entity.Used),
+	})
+	assert.Nil(t, err)
+	assert.Len(t, auctionId, 36)
+	assert.Contains(t, repository.auctions, auctionId)
+}
+
+func TestCreateAuctionReportsEveryInvalidField(t *testing.T) {
+	repository := &auctionRepositoryMock{auctions: make(map[string]auction_entity.Auction)}
+	useCase := NewAuctionUseCase(repository, nil)
+
+	_, err := useCase.CreateAuction(context.Background(), AuctionInputDTO{
+		ProductName: "a",
+		Category:    "ab",
+		Description: "short",
+		Condition:   ProductCondition(0),
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "bad_request", err.Err)
+		assert.Equal(t, []string{"category", "condition", "description", "product_name"},
+			sortedKeys(err.Fields))
+	}
+	assert.Empty(t, repository.auctions)
+}
+
+func TestCreateAuctionImageURLs(t *testing.T) {
+	newInput := func(imageURLs []string) AuctionInputDTO {
+		return AuctionInputDTO{
+			ProductName: "Notebook",
+			Category:    "Electronics",
+			Description: "Notebook in great condition",
+			Condition:   ProductCondition(auction_entity.Used),
+			ImageURLs:   imageURLs,
+		}
+	}
+
+	t.Run("stores valid urls", func(t *testing.T) {
+		repository := &auctionRepositoryMock{auctions: make(map[string]auction_entity.Auction)}
+		useCase := NewAuctionUseCase(repository, nil)
+
+		imageURLs := []string{"https://cdn.example.com/notebook.jpg", "http://example.com/img/2.png?size=large"}
+		auctionId, err := useCase.CreateAuction(context.Background(), newInput(imageURLs))
+		assert.Nil(t, err)
+		assert.Equal(t, imageURLs, repository.auctions[auctionId].ImageURLs)
+		assert.Equal(t, imageURLs, newAuctionOutputDTO(repository.auctions[auctionId]).ImageURLs)
+	})
+
+	t.Run("accepts no urls", func(t *testing.T) {
+		repository := &auctionRepositoryMock{auctions: make(map[string]auction_entity.Auction)}
+		useCase := NewAuctionUseCase(repository, nil)
+
+		auctionId, err := useCase.CreateAuction(context.Background(), newInput(nil))
+		assert.Nil(t, err)
+		assert.Empty(t, repository.auctions[auctionId].ImageURLs)
+	})
+
+	t.Run("rejects too many urls", func(t *testing.T) {
+		repository := &auctionRepositoryMock{auctions: make(map[string]auction_entity.Auction)}
+		useCase := NewAuctionUseCase(repository, nil)
+
+		imageURLs := make([]string, 0, auction_entity.MaxImageURLs+1)
+		for i := 0; i <= auction_entity.MaxImageURLs; i++ {
+			imageURLs = append(imageURLs, fmt.Sprintf("https://cdn.example.com/%d.jpg", i))
+		}
+		_, err := useCase.CreateAuction(context.Background(), newInput(imageURLs))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "bad_request", err.Err)
+			assert.Equal(t, []string{"image_urls"}, sortedKeys(err.Fields))
+		}
+		assert.Empty(t, repository.auctions)
+	})
+
+	t.Run("rejects a malformed url", func(t *testing.T) {
+		repository := &auctionRepositoryMock{auctions: make(map[string]auction_entity.Auction)}
+		useCase := NewAuctionUseCase(repository, nil)
+
+		for _, imageURL := range []string{"not a url", "/images/1.jpg", "ftp://example.com/1.jpg", "https://"} {
+			_, err := useCase.CreateAuction(context.Background(),
+				newInput([]string{"https://cdn.example.com/1.jpg", imageURL}))
+			if assert.NotNil(t, err, imageURL) {
+				assert.Contains(t, err.Fields["image_urls"], imageURL)
+			}
+		}
+		assert.Empty(t, repository.auctions)
+	})
+}
+
+func sortedKeys(fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/danielencestari/lab03/internal/entity/auction_entity"
 	"github.com/danielencestari/lab03/internal/internal_error"
 	"github.com/danielencestari/lab03/internal/usecase/bid_usecase"
+	"time"
 )
 
 func (au *AuctionUseCase) FindAuctionById(
@@ -15,43 +16,60 @@ func (au *AuctionUseCase) FindAuctionById(
 		return nil, err
 	}
 
-	return &AuctionOutputDTO{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   ProductCondition(auctionEntity.Condition),
-		Status:      AuctionStatus(auctionEntity.Status),
-		Timestamp:   auctionEntity.Timestamp,
-	}, nil
+	auctionOutputDTO := newAuctionOutputDTO(*auctionEntity)
+	return &auctionOutputDTO, nil
 }
 
 func (au *AuctionUseCase) FindAuctions(
 	ctx context.Context,
-	status AuctionStatus,
-	category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError) {
-	auctionEntities, err := au.auctionRepositoryInterface.FindAuctions(
-		ctx, auction_entity.AuctionStatus(status), category, productName)
+	filterInput AuctionFilterInputDTO) ([]AuctionOutputDTO, *internal_error.InternalError) {
+	auctionEntities, err := au.auctionRepositoryInterface.FindAuctions(ctx, newAuctionFilter(filterInput))
 	if err != nil {
 		return nil, err
 	}
 
 	var auctionOutputs []AuctionOutputDTO
 	for _, value := range auctionEntities {
-		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
-			Id:          value.Id,
-			ProductName: value.ProductName,
-			Category:    value.Category,
-			Description: value.Description,
-			Condition:   ProductCondition(value.Condition),
-			Status:      AuctionStatus(value.Status),
-			Timestamp:   value.Timestamp,
-		})
+		auctionOutputs = append(auctionOutputs, newAuctionOutputDTO(value))
 	}
 
 	return auctionOutputs, nil
 }
 
+// CountAuctions returns the total number of auctions matching filterInput.
+func (au *AuctionUseCase) CountAuctions(
+	ctx context.Context,
+	filterInput AuctionFilterInputDTO) (int64, *internal_error.InternalError) {
+	return au.auctionRepositoryInterface.CountAuctions(ctx, newAuctionFilter(filterInput))
+}
+
+func (au *AuctionUseCase) FindAuctionSummaryById(
+	ctx context.Context, id string) (*AuctionSummaryDTO, *internal_error.InternalError) {
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionSummaryById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionSummaryDTO := newAuctionSummaryDTO(*auctionEntity)
+	return &auctionSummaryDTO, nil
+}
+
+func (au *AuctionUseCase) FindAuctionSummaries(
+	ctx context.Context,
+	filterInput AuctionFilterInputDTO) ([]AuctionSummaryDTO, *internal_error.InternalError) {
+	auctionEntities, err := au.auctionRepositoryInterface.FindAuctionSummaries(ctx, newAuctionFilter(filterInput))
+	if err != nil {
+		return nil, err
+	}
+
+	var auctionSummaries []AuctionSummaryDTO
+	for _, value := range auctionEntities {
+		auctionSummaries = append(auctionSummaries, newAuctionSummaryDTO(value))
+	}
+
+	return auctionSummaries, nil
+}
+
 func (au *AuctionUseCase) FindWinningBidByAuctionId(
 	ctx context.Context,
 	auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError) {
@@ -60,15 +78,7 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		return nil, err
 	}
 
-	auctionOutputDTO := AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   ProductCondition(auction.Condition),
-		Status:      AuctionStatus(auction.Status),
-		Timestamp:   auction.Timestamp,
-	}
+	auctionOutputDTO := newAuctionOutputDTO(*auction)
 
 	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
 	if err != nil {
@@ -92,3 +102,68 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		Bid:     bidOutputDTO,
 	}, nil
 }
+
+func newAuctionFilter(filterInput AuctionFilterInputDTO) auction_entity.AuctionFilter {
+	var excludeStatuses []auction_entity.AuctionStatus
+	for _, status := range filterInput.ExcludeStatuses {
+		excludeStatuses = append(excludeStatuses, auction_entity.AuctionStatus(status))
+	}
+
+	return auction_entity.AuctionFilter{
+		Status:      auction_entity.AuctionStatus(filterInput.Status),
+		Category:    filterInput.Category,
+		Categories:  filterInput.Categories,
+		Subcategory: filterInput.Subcategory,
+		ProductName: filterInput.ProductName,
+		CreatedFrom: filterInput.CreatedFrom,
+		CreatedTo:   filterInput.CreatedTo,
+		HasWinner:   filterInput.HasWinner,
+
+		ExcludeStatuses: excludeStatuses,
+
+		Page:     filterInput.Page,
+		PageSize: filterInput.PageSize,
+	}
+}
+
+func newAuctionOutputDTO(auction auction_entity.Auction) AuctionOutputDTO {
+	return AuctionOutputDTO{
+		Id:           auction.Id,
+		ProductName:  auction.ProductName,
+		Category:     auction.Category,
+		Subcategory:  auction.Subcategory,
+		Description:  auction.Description,
+		Condition:    ProductCondition(auction.Condition),
+		Status:       AuctionStatus(auction.Status),
+		ImageURLs:    auction.ImageURLs,
+		OwnerId:      auction.OwnerId,
+		Timestamp:    auction.Timestamp,
+		TimestampISO: formatISO(auction.Timestamp),
+		EndTimeISO:   formatISO(auction.EndTime),
+		ClosedAtISO:  formatISO(auction.ClosedAt),
+		Version:      auction.Version,
+		BidCount:     auction.BidCount,
+		CloseReason:  auction.CloseReason,
+	}
+}
+
+func newAuctionSummaryDTO(auction auction_entity.Auction) AuctionSummaryDTO {
+	return AuctionSummaryDTO{
+		Id:          auction.Id,
+		ProductName: auction.ProductName,
+		Category:    auction.Category,
+		Status:      AuctionStatus(auction.Status),
+		EndTimeISO:  formatISO(auction.EndTime),
+		BidCount:    auction.BidCount,
+	}
+}
+
+// formatISO renders t as an RFC3339 string in UTC, or an empty string
+// when t is the zero time (e.g. an auction that has not closed yet).
+func formatISO(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,71 @@
+package auction_usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+)
+
+type auctionRepositoryMock struct {
+	auction_entity.AuctionRepositoryInterface
+
+	auctions map[string]auction_entity.Auction
+}
+
+func (m *auctionRepositoryMock) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auction, ok := m.auctions[id]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("auction not found")
+	}
+
+	return &auction, nil
+}
+
+func TestFindAuctionByIdISOTimestamps(t *testing.T) {
+	timestamp := time.Unix(1718985600, 0)
+	endTime := time.Unix(1718985900, 0)
+	closedAt := time.Unix(1718985901, 0)
+
+	repository := &auctionRepositoryMock{auctions: map[string]auction_entity.Auction{
+		"active": {
+			Id:        "active",
+			Status:    auction_entity.Active,
+			Timestamp: timestamp,
+			EndTime:   endTime,
+		},
+		"completed": {
+			Id:        "completed",
+			Status:    auction_entity.Completed,
+			Timestamp: timestamp,
+			EndTime:   endTime,
+			ClosedAt:  closedAt,
+		},
+	}}
+	useCase := NewAuctionUseCase(repository, nil)
+
+	activeOutput, err := useCase.FindAuctionById(context.Background(), "active")
+	assert.Nil(t, err)
+	assertISOMatchesEpoch(t, activeOutput.TimestampISO, timestamp.Unix())
+	assertISOMatchesEpoch(t, activeOutput.EndTimeISO, endTime.Unix())
+	assert.Empty(t, activeOutput.ClosedAtISO)
+
+	completedOutput, err := useCase.FindAuctionById(context.Background(), "completed")
+	assert.Nil(t, err)
+	assertISOMatchesEpoch(t, completedOutput.TimestampISO, timestamp.Unix())
+	assertISOMatchesEpoch(t, completedOutput.EndTimeISO, endTime.Unix())
+	assertISOMatchesEpoch(t, completedOutput.ClosedAtISO, closedAt.Unix())
+}
+
+func assertISOMatchesEpoch(t *testing.T, iso string, epoch int64) {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339, iso)
+	assert.Nil(t, err)
+	assert.Equal(t, epoch, parsed.Unix())
+	assert.Equal(t, time.UTC, parsed.Location())
+}
@@ -0,0 +1,22 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+type MaintenanceModeInputDTO struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type MaintenanceModeOutputDTO struct {
+	Enabled bool `json:"enabled"`
+}
+
+func (au *AuctionUseCase) SetMaintenanceMode(
+	ctx context.Context,
+	enabled bool) (*MaintenanceModeOutputDTO, *internal_error.InternalError) {
+	au.auctionRepositoryInterface.SetMaintenanceMode(enabled)
+
+	return &MaintenanceModeOutputDTO{Enabled: enabled}, nil
+}
@@ -0,0 +1,29 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+// PauseAuction freezes an active auction and returns it as paused.
+func (au *AuctionUseCase) PauseAuction(
+	ctx context.Context,
+	auctionId string) (*AuctionOutputDTO, *internal_error.InternalError) {
+	if err := au.auctionRepositoryInterface.PauseAuction(ctx, auctionId); err != nil {
+		return nil, err
+	}
+
+	return au.FindAuctionById(ctx, auctionId)
+}
+
+// ResumeAuction reopens a paused auction and returns it with its new end
+// time.
+func (au *AuctionUseCase) ResumeAuction(
+	ctx context.Context,
+	auctionId string) (*AuctionOutputDTO, *internal_error.InternalError) {
+	if err := au.auctionRepositoryInterface.ResumeAuction(ctx, auctionId); err != nil {
+		return nil, err
+	}
+
+	return au.FindAuctionById(ctx, auctionId)
+}
@@ -0,0 +1,27 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"time"
+)
+
+type PurgeInputDTO struct {
+	// Before is a unix timestamp; auctions closed earlier are deleted
+	Before int64 `json:"before" binding:"required,gt=0"`
+}
+
+type PurgeOutputDTO struct {
+	Purged int64 `json:"purged"`
+}
+
+func (au *AuctionUseCase) PurgeCompletedBefore(
+	ctx context.Context,
+	before time.Time) (*PurgeOutputDTO, *internal_error.InternalError) {
+	purged, err := au.auctionRepositoryInterface.PurgeCompletedBefore(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PurgeOutputDTO{Purged: purged}, nil
+}
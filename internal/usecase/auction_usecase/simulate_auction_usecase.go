@@ -0,0 +1,66 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/danielencestari/lab03/configuration/logger"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"time"
+)
+
+const (
+	// maxSimulatedAuctions bounds a single simulation request
+	maxSimulatedAuctions = 1000
+
+	simulationCategory = "Simulation"
+)
+
+type SimulateOutputDTO struct {
+	Requested  int      `json:"requested"`
+	Accepted   int      `json:"accepted"`
+	Rejected   int      `json:"rejected"`
+	AuctionIds []string `json:"auction_ids"`
+}
+
+// SimulateAuctions creates count throwaway auctions that close after
+// duration, so operators can watch the auto-close under load. Creations
+// refused by the repository, e.g. over the concurrency limit, are counted
+// as rejected instead of failing the whole run.
+func (au *AuctionUseCase) SimulateAuctions(
+	ctx context.Context,
+	count int,
+	duration time.Duration) (*SimulateOutputDTO, *internal_error.InternalError) {
+	if count < 1 || count > maxSimulatedAuctions {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("count must be between 1 and %d", maxSimulatedAuctions))
+	}
+	if duration <= 0 {
+		return nil, internal_error.NewBadRequestError("duration must be positive")
+	}
+
+	output := &SimulateOutputDTO{Requested: count, AuctionIds: []string{}}
+	for i := 1; i <= count; i++ {
+		auction, err := auction_entity.CreateAuctionWithIDGenerator(
+			au.idGenerator,
+			fmt.Sprintf("Simulated Product %d", i),
+			simulationCategory,
+			"Auction created by the load simulation",
+			auction_entity.New)
+		if err != nil {
+			return nil, err
+		}
+		auction.Duration = duration
+
+		if err := au.auctionRepositoryInterface.CreateAuction(ctx, auction); err != nil {
+			logger.Error("Simulated auction rejected", err)
+			output.Rejected++
+			continue
+		}
+
+		output.Accepted++
+		output.AuctionIds = append(output.AuctionIds, auction.Id)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,62 @@
+package auction_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/entity/auction_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+type AuctionUpdateInputDTO struct {
+	ProductName *string           `json:"product_name" binding:"omitempty,min=1"`
+	Category    *string           `json:"category" binding:"omitempty,min=2"`
+	Description *string           `json:"description" binding:"omitempty,min=10,max=200"`
+	Condition   *ProductCondition `json:"condition" binding:"omitempty,oneof=0 1 2"`
+
+	// Version is the auction version the client last read. When omitted the
+	// version loaded here is used, which still guards against concurrent writes
+	// between this read and the update.
+	Version *int64 `json:"version"`
+}
+
+func (au *AuctionUseCase) UpdateAuction(
+	ctx context.Context,
+	auctionId string,
+	auctionInput AuctionUpdateInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	auction, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != auction_entity.Active {
+		return nil, internal_error.NewConflictError("Auction is not active and cannot be updated")
+	}
+
+	update := auction_entity.AuctionUpdate{
+		ProductName: auctionInput.ProductName,
+		Category:    auctionInput.Category,
+		Description: auctionInput.Description,
+	}
+	if auctionInput.Condition != nil {
+		condition := auction_entity.ProductCondition(*auctionInput.Condition)
+		update.Condition = &condition
+	}
+
+	auction.ApplyUpdate(update)
+	if err := auction.Validate(); err != nil {
+		return nil, err
+	}
+
+	expectedVersion := auction.Version
+	if auctionInput.Version != nil {
+		expectedVersion = *auctionInput.Version
+	}
+
+	updatedAuction, err := au.auctionRepositoryInterface.UpdateAuction(
+		ctx, auctionId, expectedVersion, update)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputDTO := newAuctionOutputDTO(*updatedAuction)
+	return &auctionOutputDTO, nil
+}
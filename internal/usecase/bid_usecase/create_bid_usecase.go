@@ -11,17 +11,17 @@ import (
 )
 
 type BidInputDTO struct {
-	UserId    string  `json:"user_id"`
-	AuctionId string  `json:"auction_id"`
-	Amount    float64 `json:"amount"`
+	UserId    string           `json:"user_id"`
+	AuctionId string           `json:"auction_id"`
+	Amount    bid_entity.Cents `json:"amount"`
 }
 
 type BidOutputDTO struct {
-	Id        string    `json:"id"`
-	UserId    string    `json:"user_id"`
-	AuctionId string    `json:"auction_id"`
-	Amount    float64   `json:"amount"`
-	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id        string           `json:"id"`
+	UserId    string           `json:"user_id"`
+	AuctionId string           `json:"auction_id"`
+	Amount    bid_entity.Cents `json:"amount"`
+	Timestamp time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
 }
 
 type BidUseCase struct {
@@ -62,6 +62,21 @@ type BidUseCaseInterface interface {
 
 	FindBidByAuctionId(
 		ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError)
+
+	FindBidsByUser(
+		ctx context.Context,
+		userId string,
+		page, pageSize int64) ([]BidOutputDTO, *internal_error.InternalError)
+
+	FindBidsByUserAfter(
+		ctx context.Context,
+		userId string,
+		afterTimestamp time.Time,
+		afterId string,
+		limit int64) ([]BidOutputDTO, *internal_error.InternalError)
+
+	RetractBid(
+		ctx context.Context, bidId string) *internal_error.InternalError
 }
 
 func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
@@ -110,6 +125,12 @@ func (bu *BidUseCase) CreateBid(
 		return err
 	}
 
+	// Batched bids are written later, so rejections the bidder should see
+	// are checked now
+	if err := bu.BidRepository.ValidateBid(ctx, *bidEntity); err != nil {
+		return err
+	}
+
 	bu.bidChannel <- *bidEntity
 
 	return nil
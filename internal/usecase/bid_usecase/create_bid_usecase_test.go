@@ -0,0 +1,46 @@
+package bid_usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danielencestari/lab03/internal/entity/bid_entity"
+	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type bidRepositoryMock struct {
+	bid_entity.BidEntityRepository
+
+	validateErr *internal_error.InternalError
+}
+
+func (m *bidRepositoryMock) ValidateBid(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	return m.validateErr
+}
+
+func TestCreateBidRejectsSynchronously(t *testing.T) {
+	input := BidInputDTO{UserId: uuid.New().String(), AuctionId: uuid.New().String(), Amount: 100}
+
+	t.Run("rejected bid is never queued", func(t *testing.T) {
+		useCase := &BidUseCase{
+			BidRepository: &bidRepositoryMock{
+				validateErr: internal_error.NewBadRequestError("Auction owner cannot bid on their own auction")},
+			bidChannel: make(chan bid_entity.Bid, 1),
+		}
+
+		err := useCase.CreateBid(context.Background(), input)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "bad_request", err.Err)
+		}
+		assert.Empty(t, useCase.bidChannel)
+	})
+
+	t.Run("valid bid is queued", func(t *testing.T) {
+		useCase := &BidUseCase{BidRepository: &bidRepositoryMock{}, bidChannel: make(chan bid_entity.Bid, 1)}
+
+		assert.Nil(t, useCase.CreateBid(context.Background(), input))
+		assert.Len(t, useCase.bidChannel, 1)
+	})
+}
@@ -3,6 +3,8 @@ package bid_usecase
 import (
 	"context"
 	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
+	"time"
 )
 
 func (bu *BidUseCase) FindBidByAuctionId(
@@ -43,3 +45,67 @@ func (bu *BidUseCase) FindWinningBidByAuctionId(
 
 	return bidOutput, nil
 }
+
+// FindBidsByUser returns one page of the bids of userId, newest first. A
+// zero page or page size falls back to the pagination defaults.
+func (bu *BidUseCase) FindBidsByUser(
+	ctx context.Context,
+	userId string,
+	page, pageSize int64) ([]BidOutputDTO, *internal_error.InternalError) {
+	page, pageSize, err := pagination.Normalize(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	bidList, err := bu.BidRepository.FindBidsByUser(ctx, userId, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	bidOutputList := make([]BidOutputDTO, 0, len(bidList))
+	for _, bid := range bidList {
+		bidOutputList = append(bidOutputList, BidOutputDTO{
+			Id:        bid.Id,
+			UserId:    bid.UserId,
+			AuctionId: bid.AuctionId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		})
+	}
+
+	return bidOutputList, nil
+}
+
+// FindBidsByUserAfter returns up to limit bids of userId, newest first,
+// starting right after the bid at (afterTimestamp, afterId); a zero
+// afterTimestamp starts from the newest bid. A zero limit falls back to
+// the default page size.
+func (bu *BidUseCase) FindBidsByUserAfter(
+	ctx context.Context,
+	userId string,
+	afterTimestamp time.Time,
+	afterId string,
+	limit int64) ([]BidOutputDTO, *internal_error.InternalError) {
+	_, limit, err := pagination.Normalize(1, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	bidList, err := bu.BidRepository.FindBidsByUserAfter(ctx, userId, afterTimestamp, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	bidOutputList := make([]BidOutputDTO, 0, len(bidList))
+	for _, bid := range bidList {
+		bidOutputList = append(bidOutputList, BidOutputDTO{
+			Id:        bid.Id,
+			UserId:    bid.UserId,
+			AuctionId: bid.AuctionId,
+			Amount:    bid.Amount,
+			Timestamp: bid.Timestamp,
+		})
+	}
+
+	return bidOutputList, nil
+}
@@ -0,0 +1,11 @@
+package bid_usecase
+
+import (
+	"context"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+func (bu *BidUseCase) RetractBid(
+	ctx context.Context, bidId string) *internal_error.InternalError {
+	return bu.BidRepository.RetractBid(ctx, bidId)
+}
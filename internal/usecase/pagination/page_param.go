@@ -0,0 +1,21 @@
+package pagination
+
+import "strconv"
+
+// ParsePageParam parses a positive page or page size query param, returning
+// zero when the param is absent so the Normalize defaults apply.
+func ParsePageParam(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < 1 {
+		return 0, strconv.ErrRange
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,65 @@
+package pagination
+
+import (
+	"fmt"
+	"github.com/danielencestari/lab03/internal/internal_error"
+)
+
+const (
+	DefaultPageSize int64 = 20
+	MaxPageSize     int64 = 100
+	// MaxPage keeps the offset of any page far from overflowing
+	MaxPage int64 = 10000
+)
+
+// PageResponse is one page of a listing with what a client needs to render
+// a pager; Total counts every match, not only the ones in Data.
+type PageResponse[T any] struct {
+	Data       []T   `json:"data"`
+	Page       int64 `json:"page"`
+	PageSize   int64 `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+// NewPageResponse wraps data as the given page of total matches. Data is
+// never encoded as null, so an empty listing renders as [].
+func NewPageResponse[T any](data []T, page, pageSize, total int64) PageResponse[T] {
+	if data == nil {
+		data = []T{}
+	}
+
+	return PageResponse[T]{
+		Data:       data,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: TotalPages(total, pageSize),
+	}
+}
+
+// TotalPages is the number of pages of pageSize needed for total matches,
+// rounding up so a partial last page counts.
+func TotalPages(total, pageSize int64) int64 {
+	if total <= 0 || pageSize <= 0 {
+		return 0
+	}
+	return (total + pageSize - 1) / pageSize
+}
+
+// Normalize applies the defaults to a zero page or page size; negative
+// values, pages above MaxPage and page sizes above MaxPageSize are rejected.
+func Normalize(page, pageSize int64) (int64, int64, *internal_error.InternalError) {
+	if page < 0 || page > MaxPage || pageSize < 0 || pageSize > MaxPageSize {
+		return 0, 0, internal_error.NewBadRequestError(
+			fmt.Sprintf("page must be between 0 and %d and pageSize between 0 and %d", MaxPage, MaxPageSize))
+	}
+
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+	return page, pageSize, nil
+}
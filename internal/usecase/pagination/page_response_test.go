@@ -0,0 +1,85 @@
+package pagination
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageResponsePartialLastPage(t *testing.T) {
+	page := NewPageResponse([]string{"i", "j"}, 3, 4, 10)
+
+	assert.Equal(t, []string{"i", "j"}, page.Data)
+	assert.Equal(t, int64(3), page.Page)
+	assert.Equal(t, int64(4), page.PageSize)
+	assert.Equal(t, int64(10), page.Total)
+	assert.Equal(t, int64(3), page.TotalPages)
+}
+
+func TestNewPageResponseEmptyResult(t *testing.T) {
+	page := NewPageResponse[string](nil, 1, DefaultPageSize, 0)
+
+	assert.Equal(t, int64(0), page.TotalPages)
+
+	body, err := json.Marshal(page)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"data":[],"page":1,"page_size":20,"total":0,"total_pages":0}`, string(body))
+}
+
+func TestTotalPages(t *testing.T) {
+	for _, test := range []struct {
+		total, pageSize, expected int64
+	}{
+		{0, 20, 0},
+		{1, 20, 1},
+		{20, 20, 1},
+		{21, 20, 2},
+		{40, 20, 2},
+		{41, 20, 3},
+		{5, 0, 0},
+	} {
+		assert.Equal(t, test.expected, TotalPages(test.total, test.pageSize),
+			"total=%d pageSize=%d", test.total, test.pageSize)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	page, pageSize, err := Normalize(0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), page)
+	assert.Equal(t, DefaultPageSize, pageSize)
+
+	page, pageSize, err = Normalize(3, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), page)
+	assert.Equal(t, int64(50), pageSize)
+
+	_, _, err = Normalize(-1, 0)
+	assert.NotNil(t, err)
+
+	_, _, err = Normalize(1, MaxPageSize+1)
+	assert.NotNil(t, err)
+
+	page, _, err = Normalize(MaxPage, MaxPageSize)
+	assert.Nil(t, err)
+	assert.Equal(t, MaxPage, page)
+
+	_, _, err = Normalize(MaxPage+1, 0)
+	assert.NotNil(t, err)
+}
+
+func TestParsePageParam(t *testing.T) {
+	value, err := ParsePageParam("")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), value)
+
+	value, err = ParsePageParam("7")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), value)
+
+	for _, invalid := range []string{"0", "-1", "abc", "99999999999999999999"} {
+		_, err = ParsePageParam(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/danielencestari/lab03/internal/entity/user_entity"
 	"github.com/danielencestari/lab03/internal/internal_error"
+	"github.com/danielencestari/lab03/internal/usecase/pagination"
 )
 
 func NewUserUseCase(userRepository user_entity.UserRepositoryInterface) UserUseCaseInterface {
@@ -25,6 +26,10 @@ type UserUseCaseInterface interface {
 	FindUserById(
 		ctx context.Context,
 		id string) (*UserOutputDTO, *internal_error.InternalError)
+
+	FindUsers(
+		ctx context.Context,
+		page, pageSize int64) (*pagination.PageResponse[UserOutputDTO], *internal_error.InternalError)
 }
 
 func (u *UserUseCase) FindUserById(
@@ -39,3 +44,30 @@ func (u *UserUseCase) FindUserById(
 		Name: userEntity.Name,
 	}, nil
 }
+
+// FindUsers lists one page of users; a zero page or page size falls back
+// to the pagination defaults.
+func (u *UserUseCase) FindUsers(
+	ctx context.Context,
+	page, pageSize int64) (*pagination.PageResponse[UserOutputDTO], *internal_error.InternalError) {
+	page, pageSize, err := pagination.Normalize(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	userEntities, total, err := u.UserRepository.FindUsers(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]UserOutputDTO, 0, len(userEntities))
+	for _, userEntity := range userEntities {
+		users = append(users, UserOutputDTO{
+			Id:   userEntity.Id,
+			Name: userEntity.Name,
+		})
+	}
+
+	response := pagination.NewPageResponse(users, page, pageSize, total)
+	return &response, nil
+}